@@ -0,0 +1,154 @@
+package barrier
+
+import (
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+)
+
+/*
+Component that blocks in Open until its checks of external dependencies pass or the timeout expires.
+Other components declare the barrier in "depends_on" to be opened only after the dependencies are available.
+
+Configuration parameters
+  - timeout: time in milliseconds to wait for all checks to pass (default: 60000)
+  - interval: time in milliseconds between retries (default: 1000)
+  - checks: list of checks (see BarrierCheck)
+References
+  - *:logger:*:*:1.0 (optional) ILogger components to pass log messages
+Example
+  - descriptor: "pip-services:barrier:default:database:1.0"
+    timeout: 120000
+    checks:
+      - type: tcp
+        address: postgres:5432
+      - type: http
+        url: http://auth:8080/health
+
+  - descriptor: "mygroup:persistence:postgres:default:1.0"
+    depends_on: "pip-services:barrier:default:database:1.0"
+*/
+type Barrier struct {
+	logger   *log.CompositeLogger
+	timeout  time.Duration
+	interval time.Duration
+	checks   []*BarrierCheck
+	opened   bool
+}
+
+// Creates a new instance of the barrier.
+// Returns *Barrier
+func NewBarrier() *Barrier {
+	return &Barrier{
+		logger:   log.NewCompositeLogger(),
+		timeout:  time.Minute,
+		interval: time.Second,
+		checks:   []*BarrierCheck{},
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *Barrier) Configure(config *cconfig.ConfigParams) {
+	timeout := config.GetAsLongWithDefault("timeout", int64(c.timeout/time.Millisecond))
+	c.timeout = time.Duration(timeout) * time.Millisecond
+	interval := config.GetAsLongWithDefault("interval", int64(c.interval/time.Millisecond))
+	c.interval = time.Duration(interval) * time.Millisecond
+
+	checks := config.GetSection("checks")
+	c.checks = []*BarrierCheck{}
+	for _, name := range checks.GetSectionNames() {
+		check, err := ReadBarrierCheckFromConfig(checks.GetSection(name))
+		if err != nil {
+			c.logger.Error("", err, "Invalid barrier check %s", name)
+			continue
+		}
+		c.checks = append(c.checks, check)
+	}
+}
+
+// Sets references to dependent components.
+// Parameters:
+//   - references crefer.IReferences
+//   references to locate the component dependencies.
+func (c *Barrier) SetReferences(references crefer.IReferences) {
+	c.logger.SetReferences(references)
+}
+
+// Adds a check to the barrier.
+// Parameters:
+//   - check *BarrierCheck
+//   a check to be added.
+func (c *Barrier) AddCheck(check *BarrierCheck) {
+	c.checks = append(c.checks, check)
+}
+
+// Checks if the component is opened.
+// Returns bool
+// true if all checks have passed and false otherwise.
+func (c *Barrier) IsOpen() bool {
+	return c.opened
+}
+
+// Opens the component. It blocks until all checks pass or the timeout expires.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+// ConnectionError when checks haven't passed within the timeout.
+func (c *Barrier) Open(correlationId string) error {
+	if c.opened {
+		return nil
+	}
+
+	deadline := time.Now().Add(c.timeout)
+	pending := c.checks
+
+	for {
+		failed := []*BarrierCheck{}
+		var lastErr error
+		for _, check := range pending {
+			if err := check.Check(c.interval); err != nil {
+				failed = append(failed, check)
+				lastErr = err
+			}
+		}
+
+		if len(failed) == 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			names := []string{}
+			for _, check := range failed {
+				names = append(names, check.String())
+			}
+			return cerr.NewConnectionError(
+				correlationId, "BARRIER_TIMEOUT", "Dependencies are not available",
+			).WithDetails("checks", names).WithCause(lastErr)
+		}
+
+		c.logger.Debug(correlationId, "Waiting for %s: %v", failed[0].String(), lastErr)
+		pending = failed
+		time.Sleep(c.interval)
+	}
+
+	c.logger.Info(correlationId, "All barrier checks passed")
+	c.opened = true
+	return nil
+}
+
+// Closes component and frees used resources.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+func (c *Barrier) Close(correlationId string) error {
+	c.opened = false
+	return nil
+}
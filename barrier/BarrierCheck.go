@@ -0,0 +1,132 @@
+package barrier
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+A single check of an external dependency performed by Barrier.
+
+Configuration parameters
+  - type: check type: "tcp", "http", "file" or "command"
+  - address: host:port to connect (tcp)
+  - url: URL that shall respond with 2xx status (http)
+  - path: file that shall exist (file)
+  - command: shell command that shall exit with zero code (command)
+*/
+type BarrierCheck struct {
+	Type    string
+	Address string
+	Url     string
+	Path    string
+	Command string
+}
+
+// Creates a new instance of BarrierCheck based on section from barrier configuration.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   check parameters.
+// Returns *BarrierCheck, error
+// a newly created check and ConfigError when check type is unknown or its target is not set.
+func ReadBarrierCheckFromConfig(config *cconfig.ConfigParams) (*BarrierCheck, error) {
+	check := &BarrierCheck{
+		Type:    config.GetAsString("type"),
+		Address: config.GetAsString("address"),
+		Url:     config.GetAsString("url"),
+		Path:    config.GetAsString("path"),
+		Command: config.GetAsString("command"),
+	}
+
+	var target string
+	switch check.Type {
+	case "tcp":
+		target = check.Address
+	case "http":
+		target = check.Url
+	case "file":
+		target = check.Path
+	case "command":
+		target = check.Command
+	default:
+		return nil, cerr.NewConfigError(
+			"", "BAD_CHECK_TYPE", "Barrier check type "+check.Type+" is not supported",
+		).WithDetails("type", check.Type)
+	}
+
+	if target == "" {
+		return nil, cerr.NewConfigError(
+			"", "BAD_CHECK", "Barrier check "+check.Type+" has no target",
+		).WithDetails("type", check.Type)
+	}
+
+	return check, nil
+}
+
+// Gets a human-readable description of the check.
+// Returns string
+func (c *BarrierCheck) String() string {
+	switch c.Type {
+	case "tcp":
+		return "tcp " + c.Address
+	case "http":
+		return "http " + c.Url
+	case "file":
+		return "file " + c.Path
+	default:
+		return "command " + c.Command
+	}
+}
+
+// Performs the check once.
+// Parameters:
+//   - timeout time.Duration
+//   a timeout of the check.
+// Returns error
+// nil when the dependency is available or an error otherwise.
+func (c *BarrierCheck) Check(timeout time.Duration) error {
+	switch c.Type {
+	case "tcp":
+		conn, err := net.DialTimeout("tcp", c.Address, timeout)
+		if err == nil {
+			conn.Close()
+		}
+		return err
+	case "http":
+		client := &http.Client{Timeout: timeout}
+		resp, err := client.Get(c.Url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		ioutil.ReadAll(resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return cerr.NewConnectionError(
+				"", "BAD_STATUS", "Unexpected status "+resp.Status,
+			).WithDetails("url", c.Url)
+		}
+		return nil
+	case "file":
+		_, err := os.Stat(c.Path)
+		return err
+	default:
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", c.Command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", c.Command)
+		}
+		return cmd.Run()
+	}
+}
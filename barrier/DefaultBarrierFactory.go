@@ -0,0 +1,16 @@
+package barrier
+
+import (
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	cbuild "github.com/pip-services3-go/pip-services3-components-go/build"
+)
+
+var BarrierDescriptor = crefer.NewDescriptor("pip-services", "barrier", "default", "*", "1.0")
+
+// Create a new instance of the factory that creates Barrier components.
+// Returns *cbuild.Factory
+func NewDefaultBarrierFactory() *cbuild.Factory {
+	factory := cbuild.NewFactory()
+	factory.RegisterType(BarrierDescriptor, NewBarrier)
+	return factory
+}
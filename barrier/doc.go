@@ -0,0 +1,6 @@
+/*
+Barrier components that block container startup until external dependencies become available.
+Other components declare them in "depends_on" to be opened only after the checks pass.
+*/
+
+package barrier
@@ -2,35 +2,96 @@ package build
 
 /*
 Creates default container components (loggers, counters, caches, locks, etc.) by their descriptors.
+
+Nested factories are registered by groups: "info", "log", "config", "count", "cache", "auth",
+"connect", "trace", "test", "barrier" and "health". A factory of any group can be replaced by SetDefaultFactory.
+
+Factories for counters, caches, credential stores, discovery, tracers, test components, barriers
+and health services are excluded from the binary when it is built with "minimal" build tag:
+  go build -tags minimal
+Use NewMinimalContainerFactory to skip them at runtime without changing the build.
 */
 import (
-	"github.com/pip-services3-go/pip-services3-components-go/auth"
+	"sync"
+
 	cbuild "github.com/pip-services3-go/pip-services3-components-go/build"
-	"github.com/pip-services3-go/pip-services3-components-go/cache"
 	"github.com/pip-services3-go/pip-services3-components-go/config"
-	"github.com/pip-services3-go/pip-services3-components-go/connect"
-	"github.com/pip-services3-go/pip-services3-components-go/count"
 	"github.com/pip-services3-go/pip-services3-components-go/info"
 	"github.com/pip-services3-go/pip-services3-components-go/log"
-	"github.com/pip-services3-go/pip-services3-components-go/test"
-	"github.com/pip-services3-go/pip-services3-components-go/trace"
 )
 
+type defaultFactory struct {
+	group  string
+	create func() cbuild.IFactory
+}
+
+var minimalFactories = []defaultFactory{
+	{"info", func() cbuild.IFactory { return info.NewDefaultInfoFactory() }},
+	{"log", func() cbuild.IFactory { return log.NewDefaultLoggerFactory() }},
+	{"config", func() cbuild.IFactory { return config.NewDefaultConfigReaderFactory() }},
+}
+
+var overrideLock sync.Mutex
+var overrideGroups []string
+var overrides = map[string]cbuild.IFactory{}
+
+// Replaces the default nested factory of the group in all default container factories created afterwards.
+// It shall be called before containers are created.
+// Parameters:
+//  - group string
+//  a group of the default factory, for instance "log" or "count".
+//  Factories of unknown groups are added after the default ones.
+//  - factory cbuild.IFactory
+//  a factory to be used instead of the default one or nil to exclude the group.
+//
+// Example
+//   build.SetDefaultFactory("log", NewMyLoggerFactory())
+func SetDefaultFactory(group string, factory cbuild.IFactory) {
+	overrideLock.Lock()
+	defer overrideLock.Unlock()
+
+	if _, ok := overrides[group]; !ok {
+		overrideGroups = append(overrideGroups, group)
+	}
+	overrides[group] = factory
+}
+
 // Create a new instance of the factory and sets nested factories.
 // Returns *DefaultContainerFactory
 func NewDefaultContainerFactory() *cbuild.CompositeFactory {
+	return newContainerFactory(append(append([]defaultFactory{}, minimalFactories...), extendedFactories...))
+}
+
+// Create a new instance of the factory that creates only context info, loggers and config readers.
+// It is used by small deployments, like edge or IoT devices, that do not need other default components.
+// Returns *cbuild.CompositeFactory
+func NewMinimalContainerFactory() *cbuild.CompositeFactory {
+	return newContainerFactory(minimalFactories)
+}
+
+func newContainerFactory(factories []defaultFactory) *cbuild.CompositeFactory {
+	overrideLock.Lock()
+	defer overrideLock.Unlock()
+
 	c := cbuild.NewCompositeFactory()
+	groups := map[string]bool{}
 
-	c.Add(info.NewDefaultInfoFactory())
-	c.Add(log.NewDefaultLoggerFactory())
-	c.Add(count.NewDefaultCountersFactory())
-	c.Add(config.NewDefaultConfigReaderFactory())
-	c.Add(cache.NewDefaultCacheFactory())
-	c.Add(auth.NewDefaultCredentialStoreFactory())
-	c.Add(connect.NewDefaultDiscoveryFactory())
-	c.Add(log.NewDefaultLoggerFactory())
-	c.Add(trace.NewDefaultTracerFactory())
-	c.Add(test.NewDefaultTestFactory())
+	for _, factory := range factories {
+		groups[factory.group] = true
+		if override, ok := overrides[factory.group]; ok {
+			if override != nil {
+				c.Add(override)
+			}
+			continue
+		}
+		c.Add(factory.create())
+	}
+
+	for _, group := range overrideGroups {
+		if !groups[group] && overrides[group] != nil {
+			c.Add(overrides[group])
+		}
+	}
 
 	return c
 }
@@ -0,0 +1,28 @@
+//go:build !minimal
+// +build !minimal
+
+package build
+
+import (
+	"github.com/pip-services3-go/pip-services3-components-go/auth"
+	cbuild "github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-components-go/cache"
+	"github.com/pip-services3-go/pip-services3-components-go/connect"
+	"github.com/pip-services3-go/pip-services3-components-go/count"
+	"github.com/pip-services3-go/pip-services3-components-go/test"
+	"github.com/pip-services3-go/pip-services3-components-go/trace"
+	"github.com/pip-services3-go/pip-services3-container-go/barrier"
+	"github.com/pip-services3-go/pip-services3-container-go/health"
+)
+
+// Default factories that are not required by minimal deployments.
+var extendedFactories = []defaultFactory{
+	{"count", func() cbuild.IFactory { return count.NewDefaultCountersFactory() }},
+	{"cache", func() cbuild.IFactory { return cache.NewDefaultCacheFactory() }},
+	{"auth", func() cbuild.IFactory { return auth.NewDefaultCredentialStoreFactory() }},
+	{"connect", func() cbuild.IFactory { return connect.NewDefaultDiscoveryFactory() }},
+	{"trace", func() cbuild.IFactory { return trace.NewDefaultTracerFactory() }},
+	{"test", func() cbuild.IFactory { return test.NewDefaultTestFactory() }},
+	{"barrier", func() cbuild.IFactory { return barrier.NewDefaultBarrierFactory() }},
+	{"health", func() cbuild.IFactory { return health.NewDefaultHealthFactory() }},
+}
@@ -0,0 +1,7 @@
+//go:build minimal
+// +build minimal
+
+package build
+
+// Extended factories are excluded from minimal builds.
+var extendedFactories = []defaultFactory{}
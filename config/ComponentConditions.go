@@ -0,0 +1,50 @@
+package config
+
+import (
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+/*
+Conditions include components into the configuration when their "when" expressions
+evaluate to true (see EvaluateExpression), so one configuration file can replace parallel files
+that differ in a few components. Components without conditions are always included.
+
+Example
+  - descriptor: "mygroup:cache:redis:default:1.0"
+    when: "{{FEATURE_CACHE}} == 'redis'"
+
+  - descriptor: "mygroup:cache:memory:default:1.0"
+    when: "{{FEATURE_CACHE}} != 'redis'"
+*/
+
+// Selects components whose "when" conditions evaluate to true or that have no conditions.
+// Parameters:
+//   - parameters *config.ConfigParams
+//   values of parameters referenced in conditions that were not substituted when the configuration was read.
+// Returns ContainerConfig, error
+// configurations of selected components and ConfigError when a condition is malformed.
+func (c ContainerConfig) FilterByConditions(parameters *config.ConfigParams) (ContainerConfig, error) {
+	result := []*ComponentConfig{}
+
+	for _, componentConfig := range c {
+		condition := ""
+		if componentConfig.Config != nil {
+			condition = componentConfig.Config.GetAsString("when")
+		}
+
+		selected := true
+		if condition != "" {
+			var err error
+			selected, err = EvaluateExpression(condition, parameters)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if selected {
+			result = append(result, componentConfig)
+		}
+	}
+
+	return result, nil
+}
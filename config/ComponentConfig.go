@@ -1,21 +1,90 @@
 package config
 
 import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
 	"github.com/pip-services3-go/pip-services3-commons-go/config"
 	"github.com/pip-services3-go/pip-services3-commons-go/errors"
 	"github.com/pip-services3-go/pip-services3-commons-go/refer"
 	"github.com/pip-services3-go/pip-services3-commons-go/reflect"
 )
 
+// Keys of component configuration consumed by the container itself.
+var containerKeys = map[string]bool{
+	"descriptor":          true,
+	"type":                true,
+	"depends_on":          true,
+	"resolution_priority": true,
+	"group":               true,
+	"profile":             true,
+	"create_timeout":      true,
+	"open_timeout":        true,
+	"close_timeout":       true,
+	"criticality":         true,
+	"critical":            true,
+	"retry":               true,
+	"leader_only":         true,
+	"shared":              true,
+	"optional":            true,
+	"when":                true,
+	"template":            true,
+	"env":                 true,
+	"count":               true,
+	"reload":              true,
+}
+
+// Checks if the configuration key is consumed by the container rather than by the component.
+// Keys of nested values are checked by their first segment, for instance "depends_on.0".
+// Parameters:
+//  - key string
+//  a key of component configuration.
+// Returns bool
+// true if the key is consumed by the container.
+func IsContainerKey(key string) bool {
+	if index := strings.Index(key, "."); index >= 0 {
+		key = key[:index]
+	}
+	return containerKeys[key]
+}
+
 /*
 Configuration of a component inside a container.
 
 The configuration includes type information or descriptor, and component configuration parameters.
+
+Configuration parameters
+  - descriptor: component descriptor (locator)
+  - type: component type
+  - depends_on: list of descriptors of components that shall be opened before and closed after this component
+  - resolution_priority: priority to resolve the component when a locator matches multiple components (default: 0)
+  - group: name of a group of components that are opened on demand
+  - profile: list of profiles the component belongs to (see GetActiveProfiles)
+  - template: name of a template in "templates" section of the container configuration, keys of the template
+    that are not set in the component configuration are copied from it (see ContainerConfig)
+  - env: environment variables set in the process while the component is created, configured and opened,
+    for components that read configuration only from the environment
+  - count: number of component instances with indexed descriptor names, like "orders-0", and ${index}
+    in configuration values replaced by the instance index (see InstanceIndexPlaceholder)
+  - when: condition to include the component, like "{{FEATURE_CACHE}} == 'redis'" (see FilterByConditions)
+  - create_timeout: time in milliseconds to wait for asynchronous construction of the component (default: 60000)
+  - open_timeout: deadline in milliseconds for context-aware components to open (default: 0, none)
+  - close_timeout: deadline in milliseconds for context-aware components to close (default: 0, none)
+  - criticality: weight of the component in the container degradation score (default: 1)
+  - retry: policy to retry opening and closing on transient errors with attempts, backoff, max_backoff and jitter (see RetryPolicy in refer package)
+  - leader_only: true to open the component only while the container holds the leadership (see LeaderElection in container package)
+  - critical: true to stop opening the container when the component fails even if "fail_fast" option is disabled (default: false)
+  - optional: true to skip the component with a warning when no registered factory can create it (default: false)
+  - shared: true to create the component once and share it with other containers in the process (see SharedComponentRegistry in refer package)
+  - reload: "never" to keep the component running untouched when the container reloads changed configuration,
+    for stateful components like in-memory session stores (see Container.ReloadConfig in container package)
 */
 type ComponentConfig struct {
 	Descriptor *refer.Descriptor
 	Type       *reflect.TypeDescriptor
 	Config     *config.ConfigParams
+	DependsOn  []*refer.Descriptor
 }
 
 // Creates a new instance of the component configuration.
@@ -70,9 +139,120 @@ func ReadComponentConfigFromConfig(config *config.ConfigParams) (result *Compone
 		return nil, err
 	}
 
+	dependsOn, err3 := readDescriptorList(config, "depends_on")
+	if err3 != nil {
+		return nil, err3
+	}
+
 	return &ComponentConfig{
 		Descriptor: descriptor,
 		Type:       typ,
 		Config:     config,
+		DependsOn:  dependsOn,
 	}, nil
 }
+
+// Reads a list of strings defined either as an array or as a comma-separated string.
+func readStringList(config *config.ConfigParams, key string) []string {
+	values := []string{}
+
+	if config.Contains(key) {
+		for _, item := range strings.Split(config.GetAsString(key), ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	} else {
+		section := config.GetSection(key)
+		for index := 0; ; index++ {
+			key := strconv.Itoa(index)
+			if !section.Contains(key) {
+				break
+			}
+			value := section.GetAsString(key)
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
+
+// Reads a list of descriptors defined either as an array or as a comma-separated string.
+func readDescriptorList(config *config.ConfigParams, key string) ([]*refer.Descriptor, error) {
+	result := []*refer.Descriptor{}
+	for _, value := range readStringList(config, key) {
+		descriptor, err := refer.ParseDescriptorFromString(value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, descriptor)
+	}
+	return result, nil
+}
+
+// Converts the component configuration into a tree of maps and arrays
+// that can be serialized into JSON or YAML.
+// Returns map[string]interface{}
+func (c *ComponentConfig) ToValue() map[string]interface{} {
+	result := configParamsToValue(c.Config)
+
+	if c.Descriptor != nil {
+		result["descriptor"] = c.Descriptor.String()
+	}
+	if c.Type != nil {
+		result["type"] = c.Type.String()
+	}
+
+	return result
+}
+
+// Serializes the component configuration into JSON.
+// Returns []byte, error
+func (c *ComponentConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.ToValue())
+}
+
+// Deserializes the component configuration from JSON.
+// Parameters:
+//  - data []byte
+//  JSON with component configuration.
+// Returns error
+func (c *ComponentConfig) UnmarshalJSON(data []byte) error {
+	var value map[string]interface{}
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+	return c.fromValue(value)
+}
+
+// Serializes the component configuration into YAML.
+// Returns interface{}, error
+func (c *ComponentConfig) MarshalYAML() (interface{}, error) {
+	return c.ToValue(), nil
+}
+
+// Deserializes the component configuration from YAML.
+// Parameters:
+//  - unmarshal func(interface{}) error
+//  a function provided by YAML decoder.
+// Returns error
+func (c *ComponentConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value interface{}
+	err := unmarshal(&value)
+	if err != nil {
+		return err
+	}
+	return c.fromValue(normalizeValue(value))
+}
+
+func (c *ComponentConfig) fromValue(value interface{}) error {
+	result, err := ReadComponentConfigFromConfig(config.NewConfigParamsFromValue(value))
+	if err != nil {
+		return err
+	}
+	*c = *result
+	return nil
+}
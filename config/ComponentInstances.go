@@ -0,0 +1,63 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+)
+
+// Placeholder replaced by the index of the component instance.
+const InstanceIndexPlaceholder = "${index}"
+
+/*
+Instances create several copies of a component from a single entry with "count" key,
+for instance partitioned consumers. Names of instance descriptors are suffixed with the index
+and ${index} placeholders in configuration values are replaced by the index.
+The placeholder differs from {{NAME}} parameters, so it survives parameterization of the configuration.
+
+Example
+  - descriptor: "mygroup:consumer:kafka:orders:1.0"
+    count: 3
+    partition: "${index}"
+
+creates "mygroup:consumer:kafka:orders-0:1.0" with partition 0 through
+"mygroup:consumer:kafka:orders-2:1.0" with partition 2.
+*/
+
+// Expands the component configuration into configurations of its instances defined by "count" key.
+func expandInstances(componentConfig *config.ConfigParams) ([]*config.ConfigParams, error) {
+	if !componentConfig.Contains("count") {
+		return []*config.ConfigParams{componentConfig}, nil
+	}
+	value := componentConfig.GetAsString("count")
+
+	count, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || count < 0 {
+		return nil, errors.NewConfigError(
+			"", "BAD_COUNT", "Component count "+value+" is not a non-negative integer",
+		).WithDetails("count", value)
+	}
+
+	descriptor, err := refer.ParseDescriptorFromString(componentConfig.GetAsString("descriptor"))
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*config.ConfigParams{}
+	for index := 0; index < count; index++ {
+		suffix := strconv.Itoa(index)
+		instance := config.NewEmptyConfigParams()
+		for _, key := range componentConfig.Keys() {
+			instance.Put(key, strings.ReplaceAll(componentConfig.GetAsString(key), InstanceIndexPlaceholder, suffix))
+		}
+		if descriptor != nil {
+			instance.Put("descriptor", refer.NewDescriptor(descriptor.Group(), descriptor.Type(), descriptor.Kind(),
+				descriptor.Name()+"-"+suffix, descriptor.Version()).String())
+		}
+		result = append(result, instance)
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+// Converts flat configuration parameters into a tree of maps and arrays.
+// Sections with sequential numeric keys starting from 0 are converted into arrays.
+func configParamsToValue(params *config.ConfigParams) map[string]interface{} {
+	result := map[string]interface{}{}
+	if params == nil {
+		return result
+	}
+
+	keys := params.Keys()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := params.GetAsString(key)
+		path := strings.Split(key, ".")
+
+		node := result
+		for _, name := range path[:len(path)-1] {
+			child, ok := node[name].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				node[name] = child
+			}
+			node = child
+		}
+
+		name := path[len(path)-1]
+		if _, ok := node[name].(map[string]interface{}); !ok {
+			node[name] = value
+		}
+	}
+
+	for key, value := range result {
+		result[key] = convertArrays(value)
+	}
+
+	return result
+}
+
+func convertArrays(value interface{}) interface{} {
+	node, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	for key, child := range node {
+		node[key] = convertArrays(child)
+	}
+
+	array := make([]interface{}, len(node))
+	for key, child := range node {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(node) || strconv.Itoa(index) != key {
+			return node
+		}
+		array[index] = child
+	}
+
+	if len(array) == 0 {
+		return node
+	}
+	return array
+}
+
+// Converts values produced by YAML parser into maps with string keys
+// that can be consumed by ConfigParams.
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+		for key, child := range v {
+			result[toKey(key)] = normalizeValue(child)
+		}
+		return result
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, child := range v {
+			result[key] = normalizeValue(child)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for index, child := range v {
+			result[index] = normalizeValue(child)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+func toKey(key interface{}) string {
+	if s, ok := key.(string); ok {
+		return s
+	}
+	return fmt.Sprint(key)
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+	cconfig "github.com/pip-services3-go/pip-services3-components-go/config"
+	"gopkg.in/yaml.v2"
+)
+
+// Reads JSON configuration.
+var jsonFormatReader = ConfigFormatReaderFunc(func(correlationId string, data []byte,
+	parameters *config.ConfigParams) (*config.ConfigParams, error) {
+	content, err := parameterizeConfig(string(data), parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	err = json.Unmarshal([]byte(content), &value)
+	if err != nil {
+		return nil, errors.NewConfigError(
+			correlationId, "BAD_JSON", "Failed to parse JSON configuration: "+err.Error(),
+		).WithCause(err)
+	}
+	return config.NewConfigParamsFromValue(value), nil
+})
+
+// Reads YAML configuration.
+var yamlFormatReader = ConfigFormatReaderFunc(func(correlationId string, data []byte,
+	parameters *config.ConfigParams) (*config.ConfigParams, error) {
+	content, err := parameterizeConfig(string(data), parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	err = yaml.Unmarshal([]byte(content), &value)
+	if err != nil {
+		return nil, errors.NewConfigError(
+			correlationId, "BAD_YAML", "Failed to parse YAML configuration: "+err.Error(),
+		).WithCause(err)
+	}
+	return config.NewConfigParamsFromValue(normalizeValue(value)), nil
+})
+
+// Reads TOML configuration. Without namespace the configuration is a plain list of components.
+var tomlFormatReader = ConfigFormatReaderFunc(func(correlationId string, data []byte,
+	parameters *config.ConfigParams) (*config.ConfigParams, error) {
+	content, err := parameterizeConfig(string(data), parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := parseToml(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var root interface{} = value
+	_, namespaced := value["namespace"]
+	_, templated := value["templates"]
+	if !namespaced && !templated {
+		root = value["components"]
+	}
+	return config.NewConfigParamsFromValue(root), nil
+})
+
+func parameterizeConfig(content string, parameters *config.ConfigParams) (string, error) {
+	if parameters == nil {
+		return content, nil
+	}
+	content, values, err := applyTemplateFunctions(content)
+	if err != nil {
+		return "", err
+	}
+	content = applyParameterDefaults(content, parameters)
+	if len(values) > 0 {
+		parameters = parameters.Override(config.NewConfigParams(values))
+	}
+	return cconfig.NewConfigReader().Parameterize(content, parameters)
+}
+
+// Converts file extension or MIME type into the key of registered readers.
+// Extensions are prefixed with dot and MIME types are stripped of their parameters.
+func normalizeFormat(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if index := strings.Index(format, ";"); index >= 0 {
+		format = strings.TrimSpace(format[:index])
+	}
+	if format != "" && !strings.Contains(format, "/") && !strings.HasPrefix(format, ".") {
+		format = "." + format
+	}
+	return format
+}
+
+func (c *TContainerConfigReader) initFormats() {
+	if c.formats != nil {
+		return
+	}
+	c.formats = map[string]IConfigFormatReader{}
+	for _, format := range []string{".json", "application/json"} {
+		c.formats[format] = jsonFormatReader
+	}
+	for _, format := range []string{".yaml", ".yml", "application/yaml", "application/x-yaml", "text/yaml"} {
+		c.formats[format] = yamlFormatReader
+	}
+	for _, format := range []string{".toml", "application/toml"} {
+		c.formats[format] = tomlFormatReader
+	}
+}
+
+// Registers a reader of configuration format. Previously registered reader
+// for the same format, including built-in JSON, YAML and TOML readers, is replaced.
+// Parameters:
+//  - format string
+//  a file extension, for instance ".enc", or a MIME type, for instance "application/x-encrypted".
+//  - reader IConfigFormatReader
+//  a reader of the format or nil to remove the reader.
+func (c *TContainerConfigReader) RegisterFormat(format string, reader IConfigFormatReader) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.initFormats()
+	format = normalizeFormat(format)
+	if reader == nil {
+		delete(c.formats, format)
+		return
+	}
+	c.formats[format] = reader
+}
+
+// Gets the reader of configuration format.
+// Parameters:
+//  - format string
+//  a file extension or a MIME type.
+// Returns IConfigFormatReader
+// the registered reader or nil when the format is not supported.
+func (c *TContainerConfigReader) GetFormatReader(format string) IConfigFormatReader {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.initFormats()
+	return c.formats[normalizeFormat(format)]
+}
+
+// Reads configuration parameters from the content in the given format.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - data []byte
+//  the configuration content.
+//  - format string
+//  a file extension or a MIME type of the content.
+//  - parameters *config.ConfigParams
+//  values to parameters the configuration or nil to skip parameterization.
+// Returns *config.ConfigParams, error
+// the read configuration and ConfigError when the format is not supported.
+func (c *TContainerConfigReader) ReadConfigParams(correlationId string, data []byte,
+	format string, parameters *config.ConfigParams) (*config.ConfigParams, error) {
+	reader := c.GetFormatReader(format)
+	if reader == nil {
+		return nil, errors.NewConfigError(
+			correlationId, "UNSUPPORTED_FORMAT", "Configuration format "+format+" is not supported",
+		).WithDetails("format", format)
+	}
+	return reader.ReadConfig(correlationId, data, parameters)
+}
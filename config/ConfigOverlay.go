@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+// Name of the parameter that selects the environment overlay, for instance "dev", "stage" or "prod".
+const EnvironmentParameter = "ENVIRONMENT"
+
+// Gets a path to the overlay file of the environment by inserting the environment name
+// before the file extension, for instance "config.prod.yml" for "config.yml".
+// Parameters:
+//  - path string
+//  a path to the base configuration file.
+//  - environment string
+//  an environment name.
+// Returns string
+// the overlay path or empty string when the environment is not set.
+func GetOverlayPath(path string, environment string) string {
+	if environment == "" {
+		return ""
+	}
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + environment + ext
+}
+
+// Merges the overlay on top of the configuration with MergeOverride strategy.
+// Parameters:
+//  - overlay ContainerConfig
+//  the configuration to be merged on top.
+// Returns ContainerConfig, error
+// the merged configuration and ConfigError when a merged component is invalid.
+func (c ContainerConfig) MergeOverlay(overlay ContainerConfig) (ContainerConfig, error) {
+	return c.Merge(overlay, MergeOverride)
+}
+
+// Reads the overlay file of the environment selected by ENVIRONMENT parameter
+// and merges it on top of the configuration. Missing overlay files are skipped.
+func (c *TContainerConfigReader) readOverlay(correlationId string, path string,
+	containerConfig ContainerConfig, parameters *config.ConfigParams) (ContainerConfig, error) {
+	if parameters == nil {
+		return containerConfig, nil
+	}
+
+	overlayPath := GetOverlayPath(path, parameters.GetAsString(EnvironmentParameter))
+	if overlayPath == "" {
+		return containerConfig, nil
+	}
+	if _, err := os.Stat(overlayPath); err != nil {
+		return containerConfig, nil
+	}
+
+	format := filepath.Ext(overlayPath)
+	if c.GetFormatReader(format) == nil {
+		format = ".json"
+	}
+
+	data, err := readFile(correlationId, overlayPath)
+	if err != nil {
+		return nil, err
+	}
+
+	overlay, err := c.ReadFromBytes(correlationId, data, format, parameters)
+	if err != nil {
+		return nil, err
+	}
+	return containerConfig.MergeOverlay(overlay)
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+/*
+Description of a parameter referenced in a configuration template as {{NAME}}.
+
+A parameter is required when it is referenced outside of conditional sections and has no default value,
+like {{NAME|default}} or ${NAME:-default}.
+Parameters used as conditions of {{#NAME}} or {{^NAME}} sections and parameters inside such sections are optional.
+Calls of built-in functions, like {{hostname()}}, are not parameters.
+*/
+type ConfigParameter struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Set      bool   `json:"set"`
+}
+
+var templateTagPattern = regexp.MustCompile(`\{\{\{?([^{}]*)\}?\}\}`)
+
+var templateHelpers = map[string]bool{"if": true, "unless": true, "each": true, "with": true}
+
+// Scans configuration template for referenced parameters and checks which of them are set.
+// Parameters:
+//   - template string
+//   a configuration template.
+//   - parameters *config.ConfigParams
+//   values of parameters or nil if there are no parameters.
+// Returns []*ConfigParameter
+// a list of referenced parameters sorted by name.
+func ScanConfigParameters(template string, parameters *config.ConfigParams) []*ConfigParameter {
+	found := map[string]*ConfigParameter{}
+	depth := 0
+
+	add := func(name string, required bool) {
+		if name == "" || name == "else" || name == "this" || strings.HasPrefix(name, "@") {
+			return
+		}
+		parameter, ok := found[name]
+		if !ok {
+			parameter = &ConfigParameter{Name: name}
+			if parameters != nil {
+				parameter.Set = parameters.Contains(name)
+			}
+			found[name] = parameter
+		}
+		parameter.Required = parameter.Required || required
+	}
+
+	for _, match := range templateTagPattern.FindAllStringSubmatch(template, -1) {
+		tag := strings.TrimSpace(match[1])
+		if tag == "" || isTemplateFunctionCall(tag) {
+			continue
+		}
+
+		switch tag[0] {
+		case '!':
+			// Comment
+		case '/':
+			if depth > 0 {
+				depth--
+			}
+		case '#', '^':
+			words := strings.Fields(tag[1:])
+			if len(words) > 1 && templateHelpers[words[0]] {
+				words = words[1:]
+			}
+			if len(words) > 0 {
+				add(words[0], false)
+			}
+			depth++
+		default:
+			words := strings.Fields(tag)
+			if len(words) > 1 && templateHelpers[words[0]] {
+				words = words[1:]
+			}
+			if len(words) == 0 {
+				continue
+			}
+			name := words[0]
+			hasDefault := strings.Contains(tag, "|")
+			if index := strings.Index(name, "|"); index >= 0 {
+				name = strings.TrimSpace(name[:index])
+			}
+			add(name, depth == 0 && !hasDefault)
+		}
+	}
+
+	for _, name := range scanShellDefaultParameters(template) {
+		add(name, false)
+	}
+
+	result := []*ConfigParameter{}
+	for _, parameter := range found {
+		result = append(result, parameter)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/convert"
+)
+
+// Property of component configuration described by a schema.
+type ConfigSchemaProperty struct {
+	Name     string
+	Type     convert.TypeCode
+	Required bool
+	Allowed  []string
+}
+
+// Violation of a configuration schema found in a component configuration.
+type SchemaViolation struct {
+	Descriptor string `json:"descriptor"`
+	Key        string `json:"key"`
+	Message    string `json:"message"`
+}
+
+// Gets the violation in "<descriptor>: <key>: <message>" format.
+// Returns string
+func (c *SchemaViolation) String() string {
+	return c.Descriptor + ": " + c.Key + ": " + c.Message
+}
+
+/*
+Schema of component configuration used to validate configurations before components are created.
+Nested keys are named with dots, for instance "connection.port". Values are checked to be convertible
+to the property type: integers, floats, booleans, durations in milliseconds or Go format like "5s",
+and date times in RFC3339 format. Map, Object and Array properties are only checked to be present.
+
+Keys consumed by the container, like "descriptor" and "depends_on", are always allowed.
+Other keys that are not defined in the schema are reported unless undefined keys are allowed.
+
+Example
+  schema := config.NewConfigSchema().
+      WithRequiredProperty("connection.port", convert.Integer).
+      WithOptionalProperty("level", convert.String, "debug", "info", "error")
+*/
+type ConfigSchema struct {
+	Properties     []*ConfigSchemaProperty
+	AllowUndefined bool
+}
+
+// Creates a new instance of the schema.
+// Returns *ConfigSchema
+func NewConfigSchema() *ConfigSchema {
+	return &ConfigSchema{
+		Properties: []*ConfigSchemaProperty{},
+	}
+}
+
+// Adds a required property to the schema.
+// Parameters:
+//  - name string
+//  a property key, nested keys are separated by dots.
+//  - typ convert.TypeCode
+//  a property type.
+//  - allowed ...string
+//  allowed values or none to allow any value.
+// Returns *ConfigSchema
+func (c *ConfigSchema) WithRequiredProperty(name string, typ convert.TypeCode, allowed ...string) *ConfigSchema {
+	c.Properties = append(c.Properties, &ConfigSchemaProperty{Name: name, Type: typ, Required: true, Allowed: allowed})
+	return c
+}
+
+// Adds an optional property to the schema.
+// Parameters:
+//  - name string
+//  a property key, nested keys are separated by dots.
+//  - typ convert.TypeCode
+//  a property type.
+//  - allowed ...string
+//  allowed values or none to allow any value.
+// Returns *ConfigSchema
+func (c *ConfigSchema) WithOptionalProperty(name string, typ convert.TypeCode, allowed ...string) *ConfigSchema {
+	c.Properties = append(c.Properties, &ConfigSchemaProperty{Name: name, Type: typ, Required: false, Allowed: allowed})
+	return c
+}
+
+// Sets whether keys that are not defined in the schema are allowed.
+// Parameters:
+//  - allow bool
+//  true to allow undefined keys.
+// Returns *ConfigSchema
+func (c *ConfigSchema) WithAllowUndefined(allow bool) *ConfigSchema {
+	c.AllowUndefined = allow
+	return c
+}
+
+// Validates the component configuration.
+// Parameters:
+//  - componentConfig *ComponentConfig
+//  the configuration to validate.
+// Returns []*SchemaViolation
+// all found violations or empty list when the configuration is valid.
+func (c *ConfigSchema) Validate(componentConfig *ComponentConfig) []*SchemaViolation {
+	result := []*SchemaViolation{}
+	descriptor := ""
+	if componentConfig.Descriptor != nil {
+		descriptor = componentConfig.Descriptor.String()
+	} else if componentConfig.Type != nil {
+		descriptor = componentConfig.Type.String()
+	}
+
+	keys := []string{}
+	if componentConfig.Config != nil {
+		keys = componentConfig.Config.Keys()
+	}
+	violate := func(key string, message string) {
+		result = append(result, &SchemaViolation{Descriptor: descriptor, Key: key, Message: message})
+	}
+
+	for _, property := range c.Properties {
+		if !isScalarType(property.Type) {
+			if property.Required && !hasSection(keys, property.Name) {
+				violate(property.Name, "Required key is missing")
+			}
+			continue
+		}
+
+		value, ok := "", false
+		if componentConfig.Config != nil {
+			ok = componentConfig.Config.Contains(property.Name)
+			value = componentConfig.Config.GetAsString(property.Name)
+		}
+		if !ok {
+			if property.Required {
+				violate(property.Name, "Required key is missing")
+			}
+			continue
+		}
+
+		if !isValueOfType(value, property.Type) {
+			violate(property.Name, "Value "+value+" is not of "+typeCodeName(property.Type)+" type")
+			continue
+		}
+		if len(property.Allowed) > 0 && !containsString(property.Allowed, value) {
+			violate(property.Name, "Value "+value+" is not one of "+strings.Join(property.Allowed, ", "))
+		}
+	}
+
+	if !c.AllowUndefined {
+		for _, key := range keys {
+			if !IsContainerKey(key) && !c.isDefined(key) {
+				violate(key, "Key is not defined in the schema")
+			}
+		}
+	}
+
+	return result
+}
+
+// Checks if the key is defined by a property or is nested in a Map, Object or Array property.
+func (c *ConfigSchema) isDefined(key string) bool {
+	for _, property := range c.Properties {
+		if key == property.Name {
+			return true
+		}
+		if !isScalarType(property.Type) && strings.HasPrefix(key, property.Name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func isScalarType(typ convert.TypeCode) bool {
+	return typ != convert.Map && typ != convert.Object && typ != convert.Array
+}
+
+func hasSection(keys []string, name string) bool {
+	for _, key := range keys {
+		if key == name || strings.HasPrefix(key, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isValueOfType(value string, typ convert.TypeCode) bool {
+	var err error
+	switch typ {
+	case convert.Integer, convert.Long:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case convert.Float, convert.Double:
+		_, err = strconv.ParseFloat(value, 64)
+	case convert.Boolean:
+		switch strings.ToLower(value) {
+		case "true", "false", "1", "0", "yes", "no", "t", "f", "y", "n":
+		default:
+			return false
+		}
+	case convert.Duration:
+		if _, err = strconv.ParseInt(value, 10, 64); err != nil {
+			_, err = time.ParseDuration(value)
+		}
+	case convert.DateTime:
+		_, err = time.Parse(time.RFC3339, value)
+	}
+	return err == nil
+}
+
+var typeCodeNames = map[convert.TypeCode]string{
+	convert.String:   "string",
+	convert.Boolean:  "boolean",
+	convert.Integer:  "integer",
+	convert.Long:     "long",
+	convert.Float:    "float",
+	convert.Double:   "double",
+	convert.DateTime: "datetime",
+	convert.Duration: "duration",
+	convert.Enum:     "enum",
+}
+
+func typeCodeName(typ convert.TypeCode) string {
+	if name, ok := typeCodeNames[typ]; ok {
+		return name
+	}
+	return "unknown"
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Registry of config value providers indexed by their schemes.
+
+see
+IConfigValueProvider
+*/
+type ConfigValueProviders struct {
+	providers map[string]IConfigValueProvider
+}
+
+// Creates a new empty registry of config value providers.
+// Returns *ConfigValueProviders
+func NewConfigValueProviders() *ConfigValueProviders {
+	return &ConfigValueProviders{
+		providers: map[string]IConfigValueProvider{},
+	}
+}
+
+// Registers a provider for the scheme. Previously registered provider for the same scheme is replaced.
+// Parameters:
+//  - scheme string
+//  a scheme without trailing colon, for instance "vault".
+//  - provider IConfigValueProvider
+//  a provider to resolve values with the scheme.
+func (c *ConfigValueProviders) Register(scheme string, provider IConfigValueProvider) {
+	scheme = strings.TrimSuffix(scheme, ":")
+	if provider == nil {
+		delete(c.providers, scheme)
+		return
+	}
+	c.providers[scheme] = provider
+}
+
+// Checks if the value is a reference resolved by a registered provider.
+// Parameters:
+//  - value string
+//  a value to check.
+// Returns bool
+func (c *ConfigValueProviders) IsReference(value string) bool {
+	pos := strings.Index(value, ":")
+	if pos <= 0 {
+		return false
+	}
+	_, ok := c.providers[value[:pos]]
+	return ok
+}
+
+// Resolves a single value. Values without registered scheme are returned as is.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - value string
+//  a value to resolve.
+// Returns string, error
+// the resolved value and error.
+func (c *ConfigValueProviders) ResolveValue(correlationId string, value string) (string, error) {
+	pos := strings.Index(value, ":")
+	if pos <= 0 {
+		return value, nil
+	}
+
+	provider, ok := c.providers[value[:pos]]
+	if !ok {
+		return value, nil
+	}
+
+	return provider.Resolve(correlationId, value[pos+1:])
+}
+
+// Resolves all values in component configurations. Descriptors and types are never resolved.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - containerConfig ContainerConfig
+//  a container configuration to resolve.
+// Returns ContainerConfig, error
+// a copy of container configuration with resolved values and ConfigError when some value cannot be resolved.
+func (c *ConfigValueProviders) Resolve(correlationId string,
+	containerConfig ContainerConfig) (ContainerConfig, error) {
+	if len(c.providers) == 0 {
+		return containerConfig, nil
+	}
+
+	result := make([]*ComponentConfig, len(containerConfig))
+
+	for i, componentConfig := range containerConfig {
+		resolved := *componentConfig
+		result[i] = &resolved
+
+		if componentConfig.Config == nil {
+			continue
+		}
+
+		params := config.NewEmptyConfigParams()
+		for _, key := range componentConfig.Config.Keys() {
+			value := componentConfig.Config.GetAsString(key)
+
+			if key != "descriptor" && key != "type" {
+				resolvedValue, err := c.ResolveValue(correlationId, value)
+				if err != nil {
+					return nil, errors.NewConfigError(
+						correlationId, "CANNOT_RESOLVE_VALUE", "Failed to resolve configuration value "+key,
+					).WithDetails("key", key).WithCause(err)
+				}
+				value = resolvedValue
+			}
+
+			params.Put(key, value)
+		}
+		resolved.Config = params
+	}
+
+	return result, nil
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Options to read container configuration from Consul KV store.
+
+Address and token default to CONSUL_HTTP_ADDR and CONSUL_HTTP_TOKEN environment variables
+used by Consul tools, or to the local agent at "http://127.0.0.1:8500".
+
+Configuration can also be referenced by "consul://<host>:<port>/<key>?token=<token>&dc=<datacenter>&scheme=https"
+URI in place of a file path, for instance in --config argument of ProcessContainer.
+
+see
+TContainerConfigReader.ReadFromConsul
+*/
+type ConsulOptions struct {
+	// Address of Consul HTTP API
+	Address string
+	// ACL token
+	Token string
+	// Datacenter to read keys from, empty for the datacenter of the agent
+	Datacenter string
+	// Timeout of a single request (default: 10 seconds)
+	Timeout time.Duration
+	// Maximum time a blocking query waits for changes (default: 5 minutes)
+	WaitTime time.Duration
+}
+
+// Creates options with the address and token set by environment variables.
+// Returns *ConsulOptions
+func NewConsulOptions() *ConsulOptions {
+	address := os.Getenv("CONSUL_HTTP_ADDR")
+	if address == "" {
+		address = "http://127.0.0.1:8500"
+	} else if !isUrl(address) {
+		address = "http://" + address
+	}
+
+	return &ConsulOptions{
+		Address:  address,
+		Token:    os.Getenv("CONSUL_HTTP_TOKEN"),
+		Timeout:  10 * time.Second,
+		WaitTime: 5 * time.Minute,
+	}
+}
+
+// Checks if the path is consul:// URI.
+// Parameters:
+//  - path string
+//  a path to configuration.
+// Returns bool
+func IsConsulUri(path string) bool {
+	return strings.HasPrefix(strings.ToLower(path), "consul://")
+}
+
+// Parses consul://<host>:<port>/<key> URI into the key and options.
+// Parameters:
+//  - uri string
+//  the URI to parse.
+// Returns string, *ConsulOptions, error
+// the key, the options and ConfigError when the URI is malformed.
+func ParseConsulUri(uri string) (string, *ConsulOptions, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil || !IsConsulUri(uri) || strings.Trim(parsed.Path, "/") == "" {
+		return "", nil, errors.NewConfigError(
+			"", "BAD_CONSUL_URI", "Consul URI must be consul://<host>:<port>/<key>",
+		).WithDetails("uri", uri)
+	}
+
+	options := NewConsulOptions()
+	query := parsed.Query()
+	if parsed.Host != "" {
+		scheme := query.Get("scheme")
+		if scheme == "" {
+			scheme = "http"
+		}
+		options.Address = scheme + "://" + parsed.Host
+	}
+	if token := query.Get("token"); token != "" {
+		options.Token = token
+	}
+	options.Datacenter = query.Get("dc")
+
+	return strings.Trim(parsed.Path, "/"), options, nil
+}
+
+// Reads container configuration from Consul KV store.
+// The format is determined by extension of the key. Keys without known extensions are read as JSON.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - key string
+//  a key of the configuration, for instance "services/myservice/config.yml".
+//  - options *ConsulOptions
+//  address, token and timeouts or nil to use defaults.
+//  - parameters *config.ConfigParams
+//  values to parameters the configuration or nil to skip parameterization.
+// Returns ContainerConfig, error
+// the read container configuration and error when the key can't be read or parsed.
+func (c *TContainerConfigReader) ReadFromConsul(correlationId string, key string,
+	options *ConsulOptions, parameters *config.ConfigParams) (ContainerConfig, error) {
+	data, _, err := ReadConsulKey(context.Background(), correlationId, key, options, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	format := path.Ext(key)
+	if c.GetFormatReader(format) == nil {
+		format = ".json"
+	}
+	return c.ReadFromBytes(correlationId, data, format, parameters)
+}
+
+// Reads a value from Consul KV store. When index is set, it performs a blocking query
+// that waits until the value changes after the index or the wait time expires.
+// Parameters:
+//  - ctx context.Context
+//  a context to cancel the request.
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - key string
+//  a key to read.
+//  - options *ConsulOptions
+//  address, token and timeouts or nil to use defaults.
+//  - index uint64
+//  the index returned by previous read or 0 to read the value immediately.
+// Returns []byte, uint64, error
+// the value, its modify index and error when the key is not found or can't be read.
+func ReadConsulKey(ctx context.Context, correlationId string, key string,
+	options *ConsulOptions, index uint64) ([]byte, uint64, error) {
+	if options == nil {
+		options = NewConsulOptions()
+	}
+
+	query := url.Values{}
+	query.Set("raw", "")
+	if options.Datacenter != "" {
+		query.Set("dc", options.Datacenter)
+	}
+	timeout := options.Timeout
+	if index > 0 {
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", strconv.FormatInt(int64(options.WaitTime/time.Second), 10)+"s")
+		// Consul adds up to 1/16 of the wait time to blocking queries
+		timeout += options.WaitTime + options.WaitTime/16
+	}
+
+	keyUrl := strings.TrimRight(options.Address, "/") + "/v1/kv/" + strings.Trim(key, "/") + "?" + query.Encode()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, keyUrl, nil)
+	if err != nil {
+		return nil, 0, errors.NewConfigError(
+			correlationId, "BAD_CONSUL_ADDRESS", "Consul address "+options.Address+" is not valid",
+		).WithDetails("address", options.Address).WithCause(err)
+	}
+	if options.Token != "" {
+		request.Header.Set("X-Consul-Token", options.Token)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, 0, errors.NewConnectionError(
+			correlationId, "READ_FAILED", "Failed reading Consul key "+key+": "+err.Error(),
+		).WithDetails("key", key).WithCause(err)
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, 0, errors.NewConnectionError(
+			correlationId, "READ_FAILED", "Failed reading Consul key "+key+": "+err.Error(),
+		).WithDetails("key", key).WithCause(err)
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, 0, errors.NewNotFoundError(
+			correlationId, "KEY_NOT_FOUND", "Consul key "+key+" is not found",
+		).WithDetails("key", key)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, 0, errors.NewConnectionError(
+			correlationId, "READ_FAILED",
+			"Failed reading Consul key "+key+": status "+strconv.Itoa(response.StatusCode),
+		).WithDetails("key", key).WithDetails("status", response.StatusCode)
+	}
+
+	modifyIndex, _ := strconv.ParseUint(response.Header.Get("X-Consul-Index"), 10, 64)
+	return data, modifyIndex, nil
+}
@@ -1,13 +1,37 @@
 package config
 
 import (
+	"encoding/json"
 	"sort"
 
 	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"gopkg.in/yaml.v2"
 )
 
 /*
 Container configuration defined as a list of component configurations.
+
+The configuration can be defined as a plain list of components or as an object
+with "namespace", "templates" and "components" keys. Namespace prefixes name field of all descriptors
+defined in the configuration to avoid collisions when several configurations are merged together.
+Templates define reusable component blocks. A component refers to a template by "template" key
+and overrides selected keys of the template.
+
+Example
+  namespace: billing
+  templates:
+    queue_listener:
+      descriptor: "mygroup:listener:rabbitmq:default:1.0"
+      connection:
+        host: "{{RABBITMQ_HOST}}"
+        port: 5672
+  components:
+    - descriptor: "pip-services:cache:memory:default:1.0"
+    - template: queue_listener
+      descriptor: "mygroup:listener:rabbitmq:orders:1.0"
+      queue: orders
 */
 type ContainerConfig []*ComponentConfig
 
@@ -44,18 +68,295 @@ func ReadContainerConfigFromConfig(config *config.ConfigParams) (ContainerConfig
 		return []*ComponentConfig{}, nil
 	}
 
+	namespace := config.GetAsString("namespace")
+	templates := config.GetSection("templates")
+	if config.Contains("namespace") || len(templates.Keys()) > 0 {
+		config = config.GetSection("components")
+	}
+
 	names := config.GetSectionNames()
 	// Sort so components should come in a right order
 	sort.Strings(names)
-	result := make([]*ComponentConfig, len(names))
-	for i, v := range names {
-		c := config.GetSection(v)
-		componentConfig, err := ReadComponentConfigFromConfig(c)
+	result := make([]*ComponentConfig, 0, len(names))
+	violations := []*DescriptorViolation{}
+	for _, v := range names {
+		c, err := applyTemplate(config.GetSection(v), templates)
+		if err != nil {
+			return nil, err
+		}
+
+		// Report all malformed descriptors at once
+		if found := checkComponentDescriptors(c); len(found) > 0 {
+			violations = append(violations, found...)
+			continue
+		}
+		if len(violations) > 0 {
+			continue
+		}
+
+		instances, err := expandInstances(c)
 		if err != nil {
 			return nil, err
 		}
-		result[i] = componentConfig
+		for _, instance := range instances {
+			componentConfig, err := ReadComponentConfigFromConfig(instance)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, componentConfig)
+		}
+	}
+	if len(violations) > 0 {
+		return nil, newDescriptorsError("", violations)
+	}
+
+	if namespace != "" {
+		result = ContainerConfig(result).WithNamespace(namespace)
 	}
 
 	return result, nil
 }
+
+// Fills keys of the component configuration that are not set from the template it refers to.
+func applyTemplate(componentConfig *config.ConfigParams, templates *config.ConfigParams) (*config.ConfigParams, error) {
+	name := componentConfig.GetAsString("template")
+	if name == "" {
+		return componentConfig, nil
+	}
+
+	template := templates.GetSection(name)
+	if len(template.Keys()) == 0 {
+		return nil, errors.NewConfigError(
+			"", "UNKNOWN_TEMPLATE", "Component configuration refers to unknown template "+name,
+		).WithDetails("template", name)
+	}
+	return componentConfig.SetDefaults(template), nil
+}
+
+// Creates a copy of the configuration where name field of all component descriptors is prefixed with
+// the namespace. Wildcard names are left untouched.
+// Parameters:
+//  - namespace string
+//  a namespace to prefix descriptor names.
+// Returns ContainerConfig
+// a new configuration with prefixed descriptors.
+func (c ContainerConfig) WithNamespace(namespace string) ContainerConfig {
+	result := make([]*ComponentConfig, len(c))
+
+	for i, componentConfig := range c {
+		descriptor := componentConfig.Descriptor
+		if namespace == "" || descriptor == nil || descriptor.Name() == "*" || descriptor.Name() == "" {
+			result[i] = componentConfig
+			continue
+		}
+
+		descriptor = refer.NewDescriptor(descriptor.Group(), descriptor.Type(), descriptor.Kind(),
+			namespace+"."+descriptor.Name(), descriptor.Version())
+
+		params := config.NewEmptyConfigParams()
+		if componentConfig.Config != nil {
+			params = config.NewConfigParams(componentConfig.Config.Value())
+		}
+		params.Put("descriptor", descriptor.String())
+
+		namespaced := *componentConfig
+		namespaced.Descriptor = descriptor
+		namespaced.Config = params
+		result[i] = &namespaced
+	}
+
+	return result
+}
+
+// Serializes the container configuration into JSON as a list of components.
+// Returns []byte, error
+func (c ContainerConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]*ComponentConfig(c))
+}
+
+// Serializes the container configuration into YAML as a list of components.
+// Returns interface{}, error
+func (c ContainerConfig) MarshalYAML() (interface{}, error) {
+	return []*ComponentConfig(c), nil
+}
+
+// Deserializes container configuration from JSON.
+// Parameters:
+//  - data []byte
+//  JSON with a list of components or a namespaced configuration object.
+// Returns ContainerConfig, error
+func UnmarshalContainerConfigJson(data []byte) (ContainerConfig, error) {
+	var value interface{}
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return nil, errors.NewConfigError("", "BAD_CONFIG", "Failed to parse JSON configuration").WithCause(err)
+	}
+	return ReadContainerConfigFromConfig(config.NewConfigParamsFromValue(value))
+}
+
+// Deserializes container configuration from YAML.
+// Parameters:
+//  - data []byte
+//  YAML with a list of components or a namespaced configuration object.
+// Returns ContainerConfig, error
+func UnmarshalContainerConfigYaml(data []byte) (ContainerConfig, error) {
+	var value interface{}
+	err := yaml.Unmarshal(data, &value)
+	if err != nil {
+		return nil, errors.NewConfigError("", "BAD_CONFIG", "Failed to parse YAML configuration").WithCause(err)
+	}
+	return ReadContainerConfigFromConfig(config.NewConfigParamsFromValue(normalizeValue(value)))
+}
+
+// Sorts components so that every component comes after components it depends on.
+// The original order is preserved for components without dependencies between them.
+// Dependencies on components that are not defined in the configuration are ignored.
+// Returns ContainerConfig, error
+// the sorted configuration and ConfigError when dependencies are circular.
+func (c ContainerConfig) SortByDependencies() (ContainerConfig, error) {
+	count := len(c)
+	dependencies := make([][]int, count)
+	for i, componentConfig := range c {
+		for _, dependency := range componentConfig.DependsOn {
+			for j, other := range c {
+				if i != j && other.Descriptor != nil && dependency.Match(other.Descriptor) {
+					dependencies[i] = append(dependencies[i], j)
+				}
+			}
+		}
+	}
+
+	result := make([]*ComponentConfig, 0, count)
+	added := make([]bool, count)
+	for len(result) < count {
+		progress := false
+		for i, componentConfig := range c {
+			if added[i] {
+				continue
+			}
+
+			ready := true
+			for _, j := range dependencies[i] {
+				if !added[j] {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				result = append(result, componentConfig)
+				added[i] = true
+				progress = true
+				break
+			}
+		}
+
+		if !progress {
+			names := []string{}
+			for i, componentConfig := range c {
+				if !added[i] && componentConfig.Descriptor != nil {
+					names = append(names, componentConfig.Descriptor.String())
+				}
+			}
+			return nil, errors.NewConfigError(
+				"", "CIRCULAR_DEPENDENCY", "Components have circular dependencies",
+			).WithDetails("components", names)
+		}
+	}
+
+	return result, nil
+}
+
+// Splits components into two configurations: components with descriptors of the given types
+// and the rest of components. The original order is preserved in both configurations.
+// Parameters:
+//  - types ...string
+//  descriptor types of components to select, for instance "logger".
+// Returns ContainerConfig, ContainerConfig
+// the selected components and the rest of components.
+func (c ContainerConfig) SplitByTypes(types ...string) (ContainerConfig, ContainerConfig) {
+	selected := []*ComponentConfig{}
+	rest := []*ComponentConfig{}
+
+	for _, componentConfig := range c {
+		matched := false
+		if componentConfig.Descriptor != nil {
+			for _, typ := range types {
+				if componentConfig.Descriptor.Type() == typ {
+					matched = true
+					break
+				}
+			}
+		}
+
+		if matched {
+			selected = append(selected, componentConfig)
+		} else {
+			rest = append(rest, componentConfig)
+		}
+	}
+
+	return selected, rest
+}
+
+// Descriptor of the configuration section that holds options of the container itself.
+// The section is consumed by the container and no component is created for it.
+//
+// Example
+//   - descriptor: "pip-services:container:default:default:1.0"
+//     shutdown_timeout: 30000
+var ContainerOptionsDescriptor = refer.NewDescriptor("pip-services", "container", "default", "default", "1.0")
+
+// Checks that the configuration can be used to build components. Public functions that accept
+// the configuration validate it first to return an error instead of panicking on malformed input.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+// Returns error
+// ConfigError when a component configuration is nil, has neither descriptor nor type or has an empty descriptor.
+func (c ContainerConfig) Validate(correlationId string) error {
+	for index, componentConfig := range c {
+		if componentConfig == nil {
+			return errors.NewConfigError(
+				correlationId, "NO_COMPONENT_CONFIG", "Component configuration cannot be nil",
+			).WithDetails("index", index)
+		}
+
+		descriptor := componentConfig.Descriptor
+		if descriptor == nil && componentConfig.Type == nil {
+			return errors.NewConfigError(
+				correlationId, "BAD_CONFIG", "Component configuration must have descriptor or type",
+			).WithDetails("index", index)
+		}
+
+		if descriptor != nil && componentConfig.Type == nil && descriptor.Group() == "" && descriptor.Type() == "" &&
+			descriptor.Kind() == "" && descriptor.Name() == "" && descriptor.Version() == "" {
+			return errors.NewConfigError(
+				correlationId, "EMPTY_DESCRIPTOR", "Component descriptor cannot be empty",
+			).WithDetails("index", index)
+		}
+	}
+	return nil
+}
+
+// Separates container options from component configurations.
+// Options are taken from sections with ContainerOptionsDescriptor. When there are several such sections
+// their parameters are merged in the order of definition.
+// Returns *config.ConfigParams, ContainerConfig
+// the container options and configurations of components.
+func (c ContainerConfig) ExtractOptions() (*config.ConfigParams, ContainerConfig) {
+	options := config.NewEmptyConfigParams()
+	components := []*ComponentConfig{}
+
+	for _, componentConfig := range c {
+		if componentConfig.Descriptor != nil && ContainerOptionsDescriptor.Match(componentConfig.Descriptor) {
+			if componentConfig.Config != nil {
+				options = options.Override(componentConfig.Config)
+			}
+			continue
+		}
+		components = append(components, componentConfig)
+	}
+
+	return options, components
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+)
+
+/*
+Change of a component configuration that exists in both compared configurations.
+*/
+type ComponentConfigChange struct {
+	Current *ComponentConfig
+	Updated *ComponentConfig
+}
+
+/*
+Difference between the running and the updated container configurations.
+Components are matched by their descriptors or types. When several components share
+the same descriptor they are matched in the order of definition.
+
+see
+DiffContainerConfigs
+*/
+type ContainerConfigDiff struct {
+	Added   ContainerConfig
+	Removed ContainerConfig
+	Changed []*ComponentConfigChange
+}
+
+// Compares two container configurations.
+// Parameters:
+//  - current ContainerConfig
+//  the running configuration.
+//  - updated ContainerConfig
+//  the updated configuration.
+// Returns *ContainerConfigDiff
+// added, removed and changed components.
+func DiffContainerConfigs(current ContainerConfig, updated ContainerConfig) *ContainerConfigDiff {
+	diff := &ContainerConfigDiff{
+		Added:   []*ComponentConfig{},
+		Removed: []*ComponentConfig{},
+		Changed: []*ComponentConfigChange{},
+	}
+
+	currentKeys := keyComponentConfigs(current)
+	updatedKeys := keyComponentConfigs(updated)
+
+	currentByKey := map[string]*ComponentConfig{}
+	for index, key := range currentKeys {
+		currentByKey[key] = current[index]
+	}
+	updatedByKey := map[string]*ComponentConfig{}
+	for index, key := range updatedKeys {
+		updatedByKey[key] = updated[index]
+	}
+
+	for index, key := range currentKeys {
+		updatedConfig, ok := updatedByKey[key]
+		if !ok {
+			diff.Removed = append(diff.Removed, current[index])
+		} else if !equalComponentConfigs(current[index], updatedConfig) {
+			diff.Changed = append(diff.Changed, &ComponentConfigChange{
+				Current: current[index],
+				Updated: updatedConfig,
+			})
+		}
+	}
+
+	for index, key := range updatedKeys {
+		if _, ok := currentByKey[key]; !ok {
+			diff.Added = append(diff.Added, updated[index])
+		}
+	}
+
+	return diff
+}
+
+// Checks if the configurations are equal.
+// Returns bool
+// true if there are no added, removed or changed components.
+func (c *ContainerConfigDiff) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// Checks if the component configuration is removed or changed by the difference.
+// Parameters:
+//  - componentConfig *ComponentConfig
+//  a configuration of the running component.
+// Returns bool
+// true if the component is removed or changed.
+func (c *ContainerConfigDiff) IsAffected(componentConfig *ComponentConfig) bool {
+	for _, removed := range c.Removed {
+		if removed == componentConfig {
+			return true
+		}
+	}
+	for _, change := range c.Changed {
+		if change.Current == componentConfig {
+			return true
+		}
+	}
+	return false
+}
+
+// Assigns unique keys to components from their descriptor or type and occurrence number.
+func keyComponentConfigs(configs ContainerConfig) []string {
+	keys := make([]string, len(configs))
+	counts := map[string]int{}
+
+	for index, componentConfig := range configs {
+		key := ""
+		if componentConfig.Descriptor != nil {
+			key = "descriptor:" + componentConfig.Descriptor.String()
+		} else if componentConfig.Type != nil {
+			key = "type:" + componentConfig.Type.String()
+		}
+
+		counts[key]++
+		keys[index] = key + "#" + strconv.Itoa(counts[key])
+	}
+
+	return keys
+}
+
+func equalComponentConfigs(config1 *ComponentConfig, config2 *ComponentConfig) bool {
+	if config1.Config == nil || config2.Config == nil {
+		return config1.Config == config2.Config
+	}
+	return reflect.DeepEqual(config1.Config.Value(), config2.Config.Value())
+}
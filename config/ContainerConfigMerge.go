@@ -0,0 +1,75 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+// Strategy to resolve conflicts when merged configurations define the same component.
+type MergeStrategy int
+
+const (
+	// Parameters of the component are overridden by the merged configuration,
+	// keeping the ones it doesn't set. Lists are merged by element indexes.
+	MergeOverride MergeStrategy = iota
+	// The component of the merged configuration is added as another instance.
+	MergeAppend
+	// Merge fails with ConfigError.
+	MergeError
+)
+
+// Merges another configuration into this one, for instance to build layered configurations
+// like base, tenant and environment. Components are matched by descriptors, or by types when
+// they have no descriptors. Several components with the same descriptor are matched by their order.
+// Components that are not matched are added to the end. The original configurations are not modified.
+// Parameters:
+//  - other ContainerConfig
+//  the configuration to be merged.
+//  - strategy MergeStrategy
+//  a strategy to resolve conflicts of matched components.
+// Returns ContainerConfig, error
+// the merged configuration and ConfigError with DUPLICATE_COMPONENT code when components
+// conflict with MergeError strategy or a merged component is invalid.
+func (c ContainerConfig) Merge(other ContainerConfig, strategy MergeStrategy) (ContainerConfig, error) {
+	result := make(ContainerConfig, len(c))
+	copy(result, c)
+
+	positions := map[string]int{}
+	for index, key := range keyComponentConfigs(c) {
+		positions[key] = index
+	}
+
+	for index, key := range keyComponentConfigs(other) {
+		otherConfig := other[index]
+		position, ok := positions[key]
+		if !ok || strategy == MergeAppend {
+			result = append(result, otherConfig)
+			continue
+		}
+
+		if strategy == MergeError {
+			name := strings.SplitN(key, "#", 2)[0]
+			return nil, errors.NewConfigError(
+				"", "DUPLICATE_COMPONENT", "Component "+name+" is defined in both configurations",
+			).WithDetails("component", name)
+		}
+
+		params := config.NewEmptyConfigParams()
+		if result[position].Config != nil {
+			params = config.NewConfigParams(result[position].Config.Value())
+		}
+		if otherConfig.Config != nil {
+			params = params.Override(otherConfig.Config)
+		}
+
+		merged, err := ReadComponentConfigFromConfig(params)
+		if err != nil {
+			return nil, err
+		}
+		result[position] = merged
+	}
+
+	return result, nil
+}
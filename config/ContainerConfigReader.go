@@ -1,21 +1,46 @@
 package config
 
 import (
+	"io"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/pip-services3-go/pip-services3-commons-go/config"
 	"github.com/pip-services3-go/pip-services3-commons-go/errors"
-	cconfig "github.com/pip-services3-go/pip-services3-components-go/config"
 )
 
 /*
-Helper class that reads container configuration from JSON or YAML file.
+Helper class that reads container configuration from JSON, YAML or TOML file.
+Readers of other formats can be registered by file extension or MIME type (see RegisterFormat).
+During parameterization templates can call built-in functions: hostname(), uuid(), now(), env() and file().
+
+TOML configuration defines components as an array of tables, optionally with a namespace.
+
+Example
+  namespace = "billing"
+
+  [[components]]
+  descriptor = "pip-services:logger:console:default:1.0"
+  level = "{{LOG_LEVEL}}"
 */
-type TContainerConfigReader struct{}
+type TContainerConfigReader struct {
+	formats map[string]IConfigFormatReader
+	lock    sync.Mutex
+}
 
 var ContainerConfigReader = &TContainerConfigReader{}
 
-// Reads container configuration from JSON or YAML file. The type of the file is determined by file extension.
+// Reads container configuration from JSON, YAML, TOML file or a file in registered format.
+// The format of the file is determined by file extension. Files with unknown extensions are read as JSON.
+// HTTP(S) URLs are downloaded with default options (see ReadFromUrl)
+// consul:// URIs are read from Consul KV store (see ReadFromConsul)
+// and etcd:// URIs are read from etcd (see ReadFromEtcdWithOptions).
+// Directories are read file by file and merged in lexical order (see ReadFromDir)
+// and "-" path is read from the standard input as YAML or JSON (see ReadFromStdin).
+// When ENVIRONMENT parameter is set, the overlay file of the environment, like "config.prod.yml"
+// for "config.yml", is merged on top of the file (see ContainerConfig.MergeOverlay).
 // Parameters:
 //  - correlationId string
 //  transaction id to trace execution through call chain.
@@ -31,17 +56,105 @@ func (c *TContainerConfigReader) ReadFromFile(correlationId string,
 		return nil, errors.NewConfigError(correlationId, "NO_PATH", "Missing config file path")
 	}
 
-	ext := filepath.Ext(path)
+	if IsStdinPath(path) {
+		return c.ReadFromStdin(correlationId, "", parameters)
+	}
+
+	if isUrl(path) {
+		return c.ReadFromUrl(correlationId, path, nil, parameters)
+	}
+
+	if IsConsulUri(path) {
+		key, options, err := ParseConsulUri(path)
+		if err != nil {
+			return nil, err
+		}
+		return c.ReadFromConsul(correlationId, key, options, parameters)
+	}
+
+	if IsEtcdUri(path) {
+		endpoints, key, options, err := ParseEtcdUri(path)
+		if err != nil {
+			return nil, err
+		}
+		return c.ReadFromEtcdWithOptions(correlationId, endpoints, key, options, parameters)
+	}
 
-	if ext == ".json" {
-		return c.ReadFromJsonFile(correlationId, path, parameters)
+	if IsConfigDir(path) {
+		return c.ReadFromDir(correlationId, path, parameters)
 	}
 
-	if ext == ".yaml" || ext == ".yml" {
-		return c.ReadFromYamlFile(correlationId, path, parameters)
+	format := filepath.Ext(path)
+	if c.GetFormatReader(format) == nil {
+		format = ".json"
 	}
 
-	return c.ReadFromJsonFile(correlationId, path, parameters)
+	data, err := readFile(correlationId, path)
+	if err != nil {
+		return nil, err
+	}
+
+	containerConfig, err := c.readFromBytes(correlationId, path, data, format, parameters)
+	if err != nil {
+		return nil, err
+	}
+	return c.readOverlay(correlationId, path, containerConfig, parameters)
+}
+
+// Reads container configuration from the content, for instance embedded with go:embed directive.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - data []byte
+//  the configuration content.
+//  - format string
+//  a file extension or a MIME type of the content, for instance ".yml" or "application/json".
+//  - parameters *config.ConfigParams
+//  values to parameters the configuration or nil to skip parameterization.
+// Returns ContainerConfig, error
+// the read container configuration and ConfigError when the format is not supported
+// or descriptors are malformed (see CheckDescriptorString).
+func (c *TContainerConfigReader) ReadFromBytes(correlationId string, data []byte,
+	format string, parameters *config.ConfigParams) (ContainerConfig, error) {
+	return c.readFromBytes(correlationId, "", data, format, parameters)
+}
+
+// Reads container configuration from the content and reports malformed descriptors with their lines in the file.
+func (c *TContainerConfigReader) readFromBytes(correlationId string, path string, data []byte,
+	format string, parameters *config.ConfigParams) (ContainerConfig, error) {
+	config, err := c.ReadConfigParams(correlationId, data, format, parameters)
+	if err != nil {
+		return nil, err
+	}
+	containerConfig, err := ReadContainerConfigFromConfig(config)
+	if err != nil {
+		return nil, locateDescriptorViolations(correlationId, err, path, data)
+	}
+	return containerConfig, nil
+}
+
+// Reads container configuration from the stream, for instance an object downloaded from a storage.
+// The reader is read till the end and is not closed.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - reader io.Reader
+//  the stream with configuration content.
+//  - format string
+//  a file extension or a MIME type of the content, for instance ".yml" or "application/json".
+//  - parameters *config.ConfigParams
+//  values to parameters the configuration or nil to skip parameterization.
+// Returns ContainerConfig, error
+// the read container configuration and error when the stream can't be read or the format is not supported.
+func (c *TContainerConfigReader) ReadFromReader(correlationId string, reader io.Reader,
+	format string, parameters *config.ConfigParams) (ContainerConfig, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errors.NewFileError(
+			correlationId, "READ_FAILED", "Failed reading configuration: "+err.Error(),
+		).WithCause(err)
+	}
+	return c.ReadFromBytes(correlationId, data, format, parameters)
 }
 
 // Reads container configuration from JSON file.
@@ -56,11 +169,11 @@ func (c *TContainerConfigReader) ReadFromFile(correlationId string,
 // the read container configuration and error
 func (c *TContainerConfigReader) ReadFromJsonFile(correlationId string,
 	path string, parameters *config.ConfigParams) (ContainerConfig, error) {
-	config, err := cconfig.ReadJsonConfig(correlationId, path, parameters)
+	data, err := readFile(correlationId, path)
 	if err != nil {
 		return nil, err
 	}
-	return ReadContainerConfigFromConfig(config)
+	return c.readFromBytes(correlationId, path, data, ".json", parameters)
 }
 
 // Reads container configuration from YAML file.
@@ -75,9 +188,122 @@ func (c *TContainerConfigReader) ReadFromJsonFile(correlationId string,
 // the read container configuration and error
 func (c *TContainerConfigReader) ReadFromYamlFile(correlationId string,
 	path string, parameters *config.ConfigParams) (ContainerConfig, error) {
-	config, err := cconfig.ReadYamlConfig(correlationId, path, parameters)
+	data, err := readFile(correlationId, path)
+	if err != nil {
+		return nil, err
+	}
+	return c.readFromBytes(correlationId, path, data, ".yml", parameters)
+}
+
+// Reads container configuration from TOML file.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - path string
+//  a path to component configuration file.
+//  - parameters *config.ConfigParams
+//  values to parameters the configuration or null to skip parameterization.
+// Returns ContainerConfig, error
+// the read container configuration and error
+func (c *TContainerConfigReader) ReadFromTomlFile(correlationId string,
+	path string, parameters *config.ConfigParams) (ContainerConfig, error) {
+	data, err := readFile(correlationId, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.readFromBytes(correlationId, path, data, ".toml", parameters)
+}
+
+// Creates a new ContainerConfig object from TOML document. Components are defined
+// as "components" array of tables, optionally with "namespace" key.
+// Parameters:
+//  - content string
+//  a TOML document.
+// Returns ContainerConfig, error
+// a new ContainerConfig object and ConfigError when the document is malformed.
+func ReadContainerConfigFromToml(content string) (ContainerConfig, error) {
+	config, err := tomlFormatReader.ReadConfig("", []byte(content), nil)
 	if err != nil {
 		return nil, err
 	}
 	return ReadContainerConfigFromConfig(config)
 }
+
+// Scans configuration file for referenced parameters and checks which of them are set.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - path string
+//  a path to component configuration file.
+//  - parameters *config.ConfigParams
+//  values of parameters or nil if there are no parameters.
+// Returns []*ConfigParameter, error
+// referenced parameters and FileError when the file cannot be read.
+func (c *TContainerConfigReader) ScanParametersFromFile(correlationId string,
+	path string, parameters *config.ConfigParams) ([]*ConfigParameter, error) {
+	template, err := c.ReadTemplateFromFile(correlationId, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ScanConfigParameters(template, parameters), nil
+}
+
+// Reads configuration file as is, without parsing and parameterization.
+// Files of a configuration directory are joined in lexical order and "-" path is read from the standard input.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - path string
+//  a path to component configuration file.
+// Returns string, error
+// the configuration template and FileError when the file cannot be read.
+func (c *TContainerConfigReader) ReadTemplateFromFile(correlationId string, path string) (string, error) {
+	if path == "" {
+		return "", errors.NewConfigError(correlationId, "NO_PATH", "Missing config file path")
+	}
+
+	if IsStdinPath(path) {
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return "", errors.NewFileError(
+				correlationId, "READ_FAILED", "Failed reading configuration from stdin: "+err.Error(),
+			).WithCause(err)
+		}
+		return string(data), nil
+	}
+
+	if IsConfigDir(path) {
+		return c.readDirTemplate(correlationId, path)
+	}
+
+	data, err := readFile(correlationId, path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func readFile(correlationId string, path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewFileError(
+			correlationId, "READ_FAILED", "Failed reading configuration "+path+": "+err.Error(),
+		).WithDetails("path", path).WithCause(err)
+	}
+	return data, nil
+}
+
+// Reads TOML file as configuration parameters without parameterization.
+func readTomlConfig(correlationId string, path string) (*config.ConfigParams, error) {
+	data, err := readFile(correlationId, path)
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseToml(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return config.NewConfigParamsFromValue(value), nil
+}
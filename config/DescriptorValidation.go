@@ -0,0 +1,110 @@
+package config
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+// Malformed descriptor found in a container configuration.
+type DescriptorViolation struct {
+	Path    string `json:"path,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+// Gets the violation in "<path>:<line>: <key> "<value>": <message>" format.
+// The path and the line are omitted when they are unknown.
+// Returns string
+func (c *DescriptorViolation) String() string {
+	location := c.Path
+	if c.Line > 0 {
+		location += ":" + strconv.Itoa(c.Line)
+	}
+	if location != "" {
+		location += ": "
+	}
+	return location + c.Key + " \"" + c.Value + "\": " + c.Message
+}
+
+var descriptorFieldPattern = regexp.MustCompile(`^[A-Za-z0-9_.\-*]*$`)
+
+// Checks that the descriptor string has exactly five colon-separated fields
+// of letters, digits, "_", ".", "-" or "*" and non-empty group and type.
+// Parameters:
+//  - value string
+//  a descriptor string in "<group>:<type>:<kind>:<name>:<version>" format.
+// Returns string
+// a description of the problem or empty string when the descriptor is valid.
+func CheckDescriptorString(value string) string {
+	fields := strings.Split(value, ":")
+	if len(fields) != 5 {
+		return "Descriptor must have 5 fields separated by colons, but has " + strconv.Itoa(len(fields))
+	}
+
+	names := []string{"group", "type", "kind", "name", "version"}
+	for index, field := range fields {
+		if !descriptorFieldPattern.MatchString(field) {
+			return "Descriptor " + names[index] + " " + field + " contains invalid characters"
+		}
+	}
+
+	if fields[0] == "" || fields[1] == "" {
+		return "Descriptor group and type cannot be empty"
+	}
+	return ""
+}
+
+// Checks descriptors of the component and its "depends_on" list.
+func checkComponentDescriptors(componentConfig *config.ConfigParams) []*DescriptorViolation {
+	result := []*DescriptorViolation{}
+
+	if value := componentConfig.GetAsString("descriptor"); value != "" {
+		if message := CheckDescriptorString(value); message != "" {
+			result = append(result, &DescriptorViolation{Key: "descriptor", Value: value, Message: message})
+		}
+	}
+	for _, value := range readStringList(componentConfig, "depends_on") {
+		if message := CheckDescriptorString(value); message != "" {
+			result = append(result, &DescriptorViolation{Key: "depends_on", Value: value, Message: message})
+		}
+	}
+	return result
+}
+
+// Creates ConfigError that lists all malformed descriptors.
+func newDescriptorsError(correlationId string, violations []*DescriptorViolation) error {
+	messages := []string{}
+	for _, violation := range violations {
+		messages = append(messages, violation.String())
+	}
+	return errors.NewConfigError(
+		correlationId, "BAD_DESCRIPTORS", "Configuration has malformed descriptors: "+strings.Join(messages, "; "),
+	).WithDetails("violations", violations)
+}
+
+// Adds the path and lines in the configuration content to malformed descriptors reported by the error.
+func locateDescriptorViolations(correlationId string, err error, path string, data []byte) error {
+	appErr, ok := err.(*errors.ApplicationError)
+	if !ok || appErr.Code != "BAD_DESCRIPTORS" {
+		return err
+	}
+	violations, ok := appErr.Details["violations"].([]*DescriptorViolation)
+	if !ok {
+		return err
+	}
+
+	content := string(data)
+	for _, violation := range violations {
+		violation.Path = path
+		if index := strings.Index(content, violation.Value); index >= 0 && violation.Value != "" {
+			violation.Line = strings.Count(content[:index], "\n") + 1
+		}
+	}
+	return newDescriptorsError(correlationId, violations)
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+// Checks if the path points to a directory with configuration files.
+// Parameters:
+//  - path string
+//  a path to check.
+// Returns bool
+func IsConfigDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// Lists configuration files in the directory in lexical order. Only files with extensions
+// of registered formats are listed, hidden files and subdirectories are skipped.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - dir string
+//  a path to the directory.
+// Returns []string, error
+// paths to configuration files and FileError when the directory cannot be read.
+func (c *TContainerConfigReader) ListConfigFiles(correlationId string, dir string) ([]string, error) {
+	// ReadDir returns entries sorted by file name
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.NewFileError(
+			correlationId, "READ_FAILED", "Failed reading configuration directory "+dir+": "+err.Error(),
+		).WithDetails("path", dir).WithCause(err)
+	}
+
+	result := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if c.GetFormatReader(filepath.Ext(name)) == nil {
+			continue
+		}
+		result = append(result, filepath.Join(dir, name))
+	}
+	return result, nil
+}
+
+// Reads container configuration from all files in the directory and merges them in lexical order
+// of file names, so large configurations can be split per subsystem, for instance
+// "10-persistence.yml", "20-services.yml" and "30-controllers.json".
+// Each file is read in the format of its extension and may have its own namespace.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - dir string
+//  a path to the directory with configuration files.
+//  - parameters *config.ConfigParams
+//  values to parameters the configuration or nil to skip parameterization.
+// Returns ContainerConfig, error
+// the merged container configuration and error when some file cannot be read.
+func (c *TContainerConfigReader) ReadFromDir(correlationId string,
+	dir string, parameters *config.ConfigParams) (ContainerConfig, error) {
+	paths, err := c.ListConfigFiles(correlationId, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ContainerConfig{}
+	for _, path := range paths {
+		data, err := readFile(correlationId, path)
+		if err != nil {
+			return nil, err
+		}
+
+		config, err := c.readFromBytes(correlationId, path, data, filepath.Ext(path), parameters)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, config...)
+	}
+	return result, nil
+}
+
+// Reads all configuration files in the directory as one template.
+func (c *TContainerConfigReader) readDirTemplate(correlationId string, dir string) (string, error) {
+	paths, err := c.ListConfigFiles(correlationId, dir)
+	if err != nil {
+		return "", err
+	}
+
+	templates := []string{}
+	for _, path := range paths {
+		data, err := readFile(correlationId, path)
+		if err != nil {
+			return "", err
+		}
+		templates = append(templates, string(data))
+	}
+	return strings.Join(templates, "\n"), nil
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+// Reads parameters from a dotenv (.env) file with KEY=VALUE lines.
+// Empty lines and lines starting with # are skipped, keys may be prefixed with "export".
+// Values in double quotes support \n, \t, \" and \\ escapes, values in single quotes are taken as is
+// and unquoted values end at " #" comment.
+//
+// Example
+//   # Local development settings
+//   MONGO_URI=mongodb://localhost:27017/test
+//   export HTTP_PORT=8080
+//   GREETING="Hello,\nWorld" # a comment
+//
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - path string
+//  a path to the dotenv file.
+// Returns *config.ConfigParams, error
+// the read parameters, FileError when the file can't be read and ConfigError when a line is malformed.
+func (c *TContainerConfigReader) ReadDotenvFromFile(correlationId string, path string) (*config.ConfigParams, error) {
+	data, err := readFile(correlationId, path)
+	if err != nil {
+		return nil, err
+	}
+
+	result := config.NewEmptyConfigParams()
+	for index, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		separator := strings.Index(line, "=")
+		if separator <= 0 {
+			return nil, newDotenvError(correlationId, path, index+1, "Expected KEY=VALUE")
+		}
+		key := strings.TrimSpace(line[:separator])
+		value, ok := parseDotenvValue(strings.TrimSpace(line[separator+1:]))
+		if !ok {
+			return nil, newDotenvError(correlationId, path, index+1, "Value of "+key+" has unterminated quotes")
+		}
+		result.Put(key, value)
+	}
+	return result, nil
+}
+
+// Reads parameters to parameterize the configuration with precedence of environment variables
+// over values of the dotenv file over the defaults. When the dotenv file doesn't exist
+// only environment variables are applied, so the file can be used in local development only.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - path string
+//  a path to the dotenv file.
+//  - defaults *config.ConfigParams
+//  default values of parameters or nil when there are no defaults.
+// Returns *config.ConfigParams, error
+// the parameters and error when the dotenv file is malformed.
+func (c *TContainerConfigReader) ReadDotenvParameters(correlationId string, path string,
+	defaults *config.ConfigParams) (*config.ConfigParams, error) {
+	result := config.NewEmptyConfigParams()
+	if defaults != nil {
+		result = result.Override(defaults)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		dotenv, err := c.ReadDotenvFromFile(correlationId, path)
+		if err != nil {
+			return nil, err
+		}
+		result = result.Override(dotenv)
+	}
+
+	for _, e := range os.Environ() {
+		if index := strings.Index(e, "="); index > 0 {
+			result.Put(e[:index], e[index+1:])
+		}
+	}
+	return result, nil
+}
+
+// Strips quotes and comments from the value.
+func parseDotenvValue(value string) (string, bool) {
+	if strings.HasPrefix(value, "'") {
+		end := strings.Index(value[1:], "'")
+		if end < 0 {
+			return "", false
+		}
+		return value[1 : end+1], true
+	}
+
+	if strings.HasPrefix(value, "\"") {
+		for end := 1; end < len(value); end++ {
+			if value[end] == '\\' {
+				end++
+				continue
+			}
+			if value[end] == '"' {
+				return strings.NewReplacer(
+					`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`,
+				).Replace(value[1:end]), true
+			}
+		}
+		return "", false
+	}
+
+	if index := strings.Index(value, " #"); index >= 0 {
+		value = strings.TrimSpace(value[:index])
+	}
+	return value, true
+}
+
+func newDotenvError(correlationId string, path string, line int, message string) error {
+	return errors.NewConfigError(
+		correlationId, "BAD_DOTENV", "Failed to parse "+path+" at line "+strconv.Itoa(line)+": "+message,
+	).WithDetails("path", path).WithDetails("line", line)
+}
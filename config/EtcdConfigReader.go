@@ -0,0 +1,420 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Options to read container configuration from etcd v3 through its HTTP gateway.
+
+Configuration can also be referenced by "etcd://<user>:<password>@<host1>:<port>,<host2>:<port>/<key>?tls=true&ca=<file>&cert=<file>&key=<file>"
+URI in place of a file path, for instance in --config argument of ProcessContainer.
+
+see
+TContainerConfigReader.ReadFromEtcdWithOptions
+*/
+type EtcdOptions struct {
+	// User name for authentication
+	Username string
+	// Password for authentication
+	Password string
+	// TLS configuration or nil to connect without TLS
+	TLSConfig *tls.Config
+	// Timeout of a single request (default: 10 seconds)
+	Timeout time.Duration
+}
+
+// Creates options with default timeout and without authentication.
+// Returns *EtcdOptions
+func NewEtcdOptions() *EtcdOptions {
+	return &EtcdOptions{
+		Timeout: 10 * time.Second,
+	}
+}
+
+// Creates TLS configuration from PEM files.
+// Parameters:
+//  - caFile string
+//  a file with CA certificates to verify the server or empty string to use system CAs.
+//  - certFile string
+//  a file with client certificate or empty string to skip client authentication.
+//  - keyFile string
+//  a file with client private key.
+// Returns *tls.Config, error
+// the TLS configuration and ConfigError when the files can't be read.
+func NewEtcdTlsConfig(caFile string, certFile string, keyFile string) (*tls.Config, error) {
+	result := &tls.Config{}
+
+	if caFile != "" {
+		data, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, errors.NewConfigError(
+				"", "BAD_TLS_CONFIG", "Failed reading CA file "+caFile+": "+err.Error(),
+			).WithDetails("file", caFile).WithCause(err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.NewConfigError(
+				"", "BAD_TLS_CONFIG", "CA file "+caFile+" has no certificates",
+			).WithDetails("file", caFile)
+		}
+		result.RootCAs = pool
+	}
+
+	if certFile != "" {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errors.NewConfigError(
+				"", "BAD_TLS_CONFIG", "Failed loading client certificate "+certFile+": "+err.Error(),
+			).WithDetails("file", certFile).WithCause(err)
+		}
+		result.Certificates = []tls.Certificate{certificate}
+	}
+
+	return result, nil
+}
+
+// Checks if the path is etcd:// URI.
+// Parameters:
+//  - path string
+//  a path to configuration.
+// Returns bool
+func IsEtcdUri(path string) bool {
+	return strings.HasPrefix(strings.ToLower(path), "etcd://")
+}
+
+// Parses etcd://<user>:<password>@<host1>:<port>,<host2>:<port>/<key> URI into endpoints, key and options.
+// Parameters:
+//  - uri string
+//  the URI to parse.
+// Returns []string, string, *EtcdOptions, error
+// the endpoints, the key, the options and ConfigError when the URI is malformed.
+func ParseEtcdUri(uri string) ([]string, string, *EtcdOptions, error) {
+	badUri := errors.NewConfigError(
+		"", "BAD_ETCD_URI", "etcd URI must be etcd://<host>:<port>[,<host>:<port>]/<key>",
+	).WithDetails("uri", uri)
+	if !IsEtcdUri(uri) {
+		return nil, "", nil, badUri
+	}
+
+	// Several hosts can't be parsed by url package, so the authority is split manually
+	rest := uri[len("etcd://"):]
+	query := url.Values{}
+	if index := strings.Index(rest, "?"); index >= 0 {
+		query, _ = url.ParseQuery(rest[index+1:])
+		rest = rest[:index]
+	}
+	index := strings.Index(rest, "/")
+	if index < 0 || strings.Trim(rest[index:], "/") == "" {
+		return nil, "", nil, badUri
+	}
+	authority, key := rest[:index], strings.Trim(rest[index:], "/")
+
+	options := NewEtcdOptions()
+	if index := strings.LastIndex(authority, "@"); index >= 0 {
+		credentials := strings.SplitN(authority[:index], ":", 2)
+		options.Username, _ = url.PathUnescape(credentials[0])
+		if len(credentials) > 1 {
+			options.Password, _ = url.PathUnescape(credentials[1])
+		}
+		authority = authority[index+1:]
+	}
+
+	if query.Get("tls") == "true" || query.Get("ca") != "" || query.Get("cert") != "" {
+		tlsConfig, err := NewEtcdTlsConfig(query.Get("ca"), query.Get("cert"), query.Get("key"))
+		if err != nil {
+			return nil, "", nil, err
+		}
+		options.TLSConfig = tlsConfig
+	}
+
+	endpoints := []string{}
+	for _, host := range strings.Split(authority, ",") {
+		if host != "" {
+			endpoints = append(endpoints, host)
+		}
+	}
+	if len(endpoints) == 0 {
+		endpoints = append(endpoints, "127.0.0.1:2379")
+	}
+
+	return endpoints, key, options, nil
+}
+
+// Reads container configuration from etcd without authentication and TLS.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - endpoints []string
+//  addresses of etcd cluster members, for instance "etcd1:2379". They are tried in order until one responds.
+//  - key string
+//  a key of the configuration, for instance "/services/myservice/config.yml".
+//  - parameters *config.ConfigParams
+//  values to parameters the configuration or nil to skip parameterization.
+// Returns ContainerConfig, error
+// the read container configuration and error when the key can't be read or parsed.
+func (c *TContainerConfigReader) ReadFromEtcd(correlationId string, endpoints []string,
+	key string, parameters *config.ConfigParams) (ContainerConfig, error) {
+	return c.ReadFromEtcdWithOptions(correlationId, endpoints, key, nil, parameters)
+}
+
+// Reads container configuration from etcd.
+// The format is determined by extension of the key. Keys without known extensions are read as JSON.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - endpoints []string
+//  addresses of etcd cluster members. They are tried in order until one responds.
+//  - key string
+//  a key of the configuration.
+//  - options *EtcdOptions
+//  credentials, TLS and timeout or nil to use defaults.
+//  - parameters *config.ConfigParams
+//  values to parameters the configuration or nil to skip parameterization.
+// Returns ContainerConfig, error
+// the read container configuration and error when the key can't be read or parsed.
+func (c *TContainerConfigReader) ReadFromEtcdWithOptions(correlationId string, endpoints []string,
+	key string, options *EtcdOptions, parameters *config.ConfigParams) (ContainerConfig, error) {
+	data, err := ReadEtcdKey(context.Background(), correlationId, endpoints, key, options)
+	if err != nil {
+		return nil, err
+	}
+
+	format := path.Ext(key)
+	if c.GetFormatReader(format) == nil {
+		format = ".json"
+	}
+	return c.ReadFromBytes(correlationId, data, format, parameters)
+}
+
+// Reads a value from etcd.
+// Parameters:
+//  - ctx context.Context
+//  a context to cancel the request.
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - endpoints []string
+//  addresses of etcd cluster members.
+//  - key string
+//  a key to read.
+//  - options *EtcdOptions
+//  credentials, TLS and timeout or nil to use defaults.
+// Returns []byte, error
+// the value and error when the key is not found or no member responds.
+func ReadEtcdKey(ctx context.Context, correlationId string, endpoints []string,
+	key string, options *EtcdOptions) ([]byte, error) {
+	var result struct {
+		Kvs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+
+	request := map[string]interface{}{"key": base64.StdEncoding.EncodeToString([]byte(key))}
+	err := callEtcd(ctx, correlationId, endpoints, options, "/v3/kv/range", request, options.timeout(), &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Kvs) == 0 {
+		return nil, errors.NewNotFoundError(
+			correlationId, "KEY_NOT_FOUND", "etcd key "+key+" is not found",
+		).WithDetails("key", key)
+	}
+
+	value, err := base64.StdEncoding.DecodeString(result.Kvs[0].Value)
+	if err != nil {
+		return nil, errors.NewInvalidStateError(
+			correlationId, "BAD_RESPONSE", "etcd returned malformed value of key "+key,
+		).WithDetails("key", key).WithCause(err)
+	}
+	return value, nil
+}
+
+// Waits until the key is changed in etcd. It returns nil after the first change
+// and an error when the watch can't be established or is interrupted.
+// Parameters:
+//  - ctx context.Context
+//  a context to cancel the watch.
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - endpoints []string
+//  addresses of etcd cluster members.
+//  - key string
+//  a key to watch.
+//  - options *EtcdOptions
+//  credentials, TLS and timeout or nil to use defaults.
+// Returns error
+func WatchEtcdKey(ctx context.Context, correlationId string, endpoints []string,
+	key string, options *EtcdOptions) error {
+	request := map[string]interface{}{
+		"create_request": map[string]interface{}{"key": base64.StdEncoding.EncodeToString([]byte(key))},
+	}
+
+	// Watch has no timeout, it lasts until the key changes or the context is cancelled
+	return streamEtcd(ctx, correlationId, endpoints, options, "/v3/watch", request, 0,
+		func(decoder *json.Decoder) (bool, error) {
+			var response struct {
+				Result struct {
+					Canceled bool          `json:"canceled"`
+					Events   []interface{} `json:"events"`
+				} `json:"result"`
+			}
+			if err := decoder.Decode(&response); err != nil {
+				return false, err
+			}
+			if response.Result.Canceled {
+				return false, errors.NewInvalidStateError(
+					correlationId, "WATCH_CANCELED", "Watch of etcd key "+key+" was canceled",
+				).WithDetails("key", key)
+			}
+			return len(response.Result.Events) > 0, nil
+		})
+}
+
+func (c *EtcdOptions) timeout() time.Duration {
+	if c == nil || c.Timeout <= 0 {
+		return 10 * time.Second
+	}
+	return c.Timeout
+}
+
+func (c *EtcdOptions) newClient(timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if c != nil && c.TLSConfig != nil {
+		client.Transport = &http.Transport{TLSClientConfig: c.TLSConfig}
+	}
+	return client
+}
+
+// Gets the base URL of the member. Addresses without scheme use https when TLS is configured.
+func (c *EtcdOptions) endpointUrl(endpoint string) string {
+	if isUrl(endpoint) {
+		return strings.TrimRight(endpoint, "/")
+	}
+	if c != nil && c.TLSConfig != nil {
+		return "https://" + endpoint
+	}
+	return "http://" + endpoint
+}
+
+// Calls etcd gateway trying members in order until one responds.
+func callEtcd(ctx context.Context, correlationId string, endpoints []string, options *EtcdOptions,
+	method string, request interface{}, timeout time.Duration, result interface{}) error {
+	return streamEtcd(ctx, correlationId, endpoints, options, method, request, timeout,
+		func(decoder *json.Decoder) (bool, error) {
+			return true, decoder.Decode(result)
+		})
+}
+
+// Sends the request to etcd gateway and reads the response with the handler
+// until it returns true. Members are tried in order until one responds.
+func streamEtcd(ctx context.Context, correlationId string, endpoints []string, options *EtcdOptions,
+	method string, request interface{}, timeout time.Duration,
+	handle func(decoder *json.Decoder) (bool, error)) error {
+	if len(endpoints) == 0 {
+		return errors.NewConfigError(correlationId, "NO_ENDPOINTS", "etcd endpoints are not set")
+	}
+
+	body, _ := json.Marshal(request)
+	client := options.newClient(timeout)
+
+	var lastErr error
+	for _, endpoint := range endpoints {
+		baseUrl := options.endpointUrl(endpoint)
+
+		token, err := authenticateEtcd(ctx, correlationId, baseUrl, options)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, baseUrl+method, bytes.NewReader(body))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		httpRequest.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			httpRequest.Header.Set("Authorization", token)
+		}
+
+		response, err := client.Do(httpRequest)
+		if err != nil {
+			lastErr = errors.NewConnectionError(
+				correlationId, "READ_FAILED", "Failed calling etcd at "+baseUrl+": "+err.Error(),
+			).WithDetails("endpoint", endpoint).WithCause(err)
+			continue
+		}
+
+		err = func() error {
+			defer response.Body.Close()
+			if response.StatusCode != http.StatusOK {
+				return errors.NewConnectionError(
+					correlationId, "READ_FAILED",
+					"Failed calling etcd at "+baseUrl+": status "+strconv.Itoa(response.StatusCode),
+				).WithDetails("endpoint", endpoint).WithDetails("status", response.StatusCode)
+			}
+
+			decoder := json.NewDecoder(response.Body)
+			for {
+				done, err := handle(decoder)
+				if err != nil || done {
+					return err
+				}
+			}
+		}()
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		lastErr = err
+	}
+	return lastErr
+}
+
+// Gets authentication token from the member or empty string when credentials are not set.
+func authenticateEtcd(ctx context.Context, correlationId string, baseUrl string,
+	options *EtcdOptions) (string, error) {
+	if options == nil || options.Username == "" {
+		return "", nil
+	}
+
+	body, _ := json.Marshal(map[string]string{"name": options.Username, "password": options.Password})
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, baseUrl+"/v3/auth/authenticate", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := options.newClient(options.timeout()).Do(request)
+	if err != nil {
+		return "", errors.NewConnectionError(
+			correlationId, "AUTH_FAILED", "Failed authenticating in etcd at "+baseUrl+": "+err.Error(),
+		).WithCause(err)
+	}
+	defer response.Body.Close()
+
+	var result struct {
+		Token string `json:"token"`
+	}
+	if response.StatusCode != http.StatusOK || json.NewDecoder(response.Body).Decode(&result) != nil {
+		return "", errors.NewUnauthorizedError(
+			correlationId, "AUTH_FAILED", "Failed authenticating in etcd at "+baseUrl+" as "+options.Username,
+		).WithDetails("status", response.StatusCode)
+	}
+	return result.Token, nil
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Boolean expression over configuration parameters used to activate profiles and components.
+
+Expressions support string comparisons with == and !=, logical &&, || and ! operators and parentheses.
+Operands are quoted strings, bare words or {{NAME}} references to parameters.
+When the configuration was already parameterized, references are replaced by their values
+and become bare words, so "{{ENV}} == 'prod'" and "prod == 'prod'" are equivalent.
+A single operand is true when it is not empty and not "false", "0" or "no".
+
+Example
+  {{ENV}} == 'prod' && ({{REGION}} != 'cn' || {{FORCE}})
+*/
+type expressionParser struct {
+	expression string
+	tokens     []string
+	position   int
+	parameters *config.ConfigParams
+}
+
+// Evaluates a boolean expression over parameters.
+// Parameters:
+//   - expression string
+//   an expression to evaluate.
+//   - parameters *config.ConfigParams
+//   values of {{NAME}} references or nil when there are no parameters.
+// Returns bool, error
+// the result of the expression and ConfigError when the expression is malformed.
+func EvaluateExpression(expression string, parameters *config.ConfigParams) (bool, error) {
+	parser := &expressionParser{
+		expression: expression,
+		tokens:     tokenizeExpression(expression),
+		parameters: parameters,
+	}
+
+	result, err := parser.parseOr()
+	if err == nil && parser.position < len(parser.tokens) {
+		err = parser.newError("Unexpected " + parser.tokens[parser.position])
+	}
+	return result, err
+}
+
+func tokenizeExpression(expression string) []string {
+	tokens := []string{}
+	for index := 0; index < len(expression); {
+		ch := expression[index]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+			index++
+		case ch == '(' || ch == ')':
+			tokens = append(tokens, string(ch))
+			index++
+		case strings.HasPrefix(expression[index:], "&&"), strings.HasPrefix(expression[index:], "||"),
+			strings.HasPrefix(expression[index:], "=="), strings.HasPrefix(expression[index:], "!="):
+			tokens = append(tokens, expression[index:index+2])
+			index += 2
+		case ch == '!':
+			tokens = append(tokens, "!")
+			index++
+		case ch == '\'' || ch == '"':
+			end := strings.IndexByte(expression[index+1:], ch)
+			if end < 0 {
+				tokens = append(tokens, expression[index:])
+				index = len(expression)
+			} else {
+				tokens = append(tokens, expression[index:index+end+2])
+				index += end + 2
+			}
+		default:
+			start := index
+			for index < len(expression) && !strings.ContainsRune(" \t\r\n()'\"", rune(expression[index])) &&
+				!isOperatorAt(expression, index) {
+				index++
+			}
+			tokens = append(tokens, expression[start:index])
+		}
+	}
+	return tokens
+}
+
+func isOperatorAt(expression string, index int) bool {
+	rest := expression[index:]
+	return strings.HasPrefix(rest, "&&") || strings.HasPrefix(rest, "||") ||
+		strings.HasPrefix(rest, "==") || strings.HasPrefix(rest, "!=")
+}
+
+func (c *expressionParser) newError(message string) error {
+	return errors.NewConfigError(
+		"", "BAD_EXPRESSION", message+" in expression "+c.expression,
+	).WithDetails("expression", c.expression)
+}
+
+func (c *expressionParser) peek() string {
+	if c.position < len(c.tokens) {
+		return c.tokens[c.position]
+	}
+	return ""
+}
+
+func (c *expressionParser) parseOr() (bool, error) {
+	result, err := c.parseAnd()
+	for err == nil && c.peek() == "||" {
+		c.position++
+		var right bool
+		right, err = c.parseAnd()
+		result = result || right
+	}
+	return result, err
+}
+
+func (c *expressionParser) parseAnd() (bool, error) {
+	result, err := c.parseUnary()
+	for err == nil && c.peek() == "&&" {
+		c.position++
+		var right bool
+		right, err = c.parseUnary()
+		result = result && right
+	}
+	return result, err
+}
+
+func (c *expressionParser) parseUnary() (bool, error) {
+	switch c.peek() {
+	case "!":
+		c.position++
+		result, err := c.parseUnary()
+		return !result, err
+	case "(":
+		c.position++
+		result, err := c.parseOr()
+		if err == nil && c.peek() != ")" {
+			err = c.newError("Missing )")
+		}
+		c.position++
+		return result, err
+	default:
+		return c.parseComparison()
+	}
+}
+
+func (c *expressionParser) parseComparison() (bool, error) {
+	left := c.parseOperand()
+
+	operator := c.peek()
+	if operator != "==" && operator != "!=" {
+		value := strings.ToLower(left)
+		return value != "" && value != "false" && value != "0" && value != "no", nil
+	}
+	c.position++
+
+	right := c.parseOperand()
+	if operator == "==" {
+		return left == right, nil
+	}
+	return left != right, nil
+}
+
+// Reads an operand. Missing operands, for instance parameters substituted
+// by empty values, are treated as empty strings.
+func (c *expressionParser) parseOperand() string {
+	token := c.peek()
+	switch token {
+	case "", "(", ")", "!", "&&", "||", "==", "!=":
+		return ""
+	}
+	c.position++
+
+	if len(token) >= 2 && (token[0] == '\'' || token[0] == '"') && token[len(token)-1] == token[0] {
+		return token[1 : len(token)-1]
+	}
+	if strings.HasPrefix(token, "{{") && strings.HasSuffix(token, "}}") {
+		name := strings.TrimSpace(token[2 : len(token)-2])
+		if c.parameters == nil {
+			return ""
+		}
+		return c.parameters.GetAsString(name)
+	}
+	return token
+}
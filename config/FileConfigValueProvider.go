@@ -0,0 +1,44 @@
+package config
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Config value provider that reads values from files. It is usually registered under "file" scheme
+to read secrets mounted as files by orchestrators.
+
+Example
+  providers.Register("file", NewFileConfigValueProvider())
+
+  - descriptor: "pip-services:connection:default:default:1.0"
+    password: "file:/run/secrets/db_password"
+*/
+type FileConfigValueProvider struct{}
+
+// Creates a new instance of the provider.
+// Returns *FileConfigValueProvider
+func NewFileConfigValueProvider() *FileConfigValueProvider {
+	return &FileConfigValueProvider{}
+}
+
+// Reads the value from the file. Trailing line breaks are removed.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - value string
+//  a path to the file.
+// Returns string, error
+// the file content and FileError when the file cannot be read.
+func (c *FileConfigValueProvider) Resolve(correlationId string, value string) (string, error) {
+	data, err := ioutil.ReadFile(value)
+	if err != nil {
+		return "", errors.NewFileError(
+			correlationId, "READ_FAILED", "Failed to read file "+value,
+		).WithCause(err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+/*
+Interface for readers of custom configuration formats, for instance encrypted configurations.
+Readers are registered in ContainerConfigReader for file extensions or MIME types.
+
+Example
+  config.ContainerConfigReader.RegisterFormat(".enc", config.ConfigFormatReaderFunc(
+      func(correlationId string, data []byte, parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, error) {
+          decrypted, err := Decrypt(data)
+          if err != nil {
+              return nil, err
+          }
+          return config.ContainerConfigReader.ReadConfigParams(correlationId, decrypted, ".yaml", parameters)
+      },
+  ))
+
+see
+TContainerConfigReader.RegisterFormat
+*/
+type IConfigFormatReader interface {
+	// Reads configuration parameters from the content.
+	// Parameters:
+	//  - correlationId string
+	//  transaction id to trace execution through call chain.
+	//  - data []byte
+	//  the configuration content.
+	//  - parameters *config.ConfigParams
+	//  values to parameters the configuration or nil to skip parameterization.
+	// Returns *config.ConfigParams, error
+	// the read configuration and error.
+	ReadConfig(correlationId string, data []byte, parameters *config.ConfigParams) (*config.ConfigParams, error)
+}
+
+// Function adapter that allows to use ordinary functions as config format readers.
+type ConfigFormatReaderFunc func(correlationId string, data []byte, parameters *config.ConfigParams) (*config.ConfigParams, error)
+
+// Reads configuration parameters by calling the function.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - data []byte
+//  the configuration content.
+//  - parameters *config.ConfigParams
+//  values to parameters the configuration or nil to skip parameterization.
+// Returns *config.ConfigParams, error
+// the read configuration and error.
+func (c ConfigFormatReaderFunc) ReadConfig(correlationId string, data []byte,
+	parameters *config.ConfigParams) (*config.ConfigParams, error) {
+	return c(correlationId, data, parameters)
+}
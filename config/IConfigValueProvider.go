@@ -0,0 +1,42 @@
+package config
+
+/*
+Interface for providers that resolve configuration values from external sources
+such as secret vaults, files or command outputs.
+
+Providers are registered for a scheme. Every configuration value that starts with "<scheme>:"
+is passed to the provider without the scheme prefix and replaced with the resolved value.
+
+Example
+  - descriptor: "pip-services:connection:default:default:1.0"
+    password: "vault:secret/data/db#password"
+
+see
+ConfigValueProviders
+*/
+type IConfigValueProvider interface {
+	// Resolves the configuration value.
+	// Parameters:
+	//  - correlationId string
+	//  transaction id to trace execution through call chain.
+	//  - value string
+	//  a value without the scheme prefix.
+	// Returns string, error
+	// the resolved value and error.
+	Resolve(correlationId string, value string) (string, error)
+}
+
+// Function adapter that allows to use ordinary functions as config value providers.
+type ConfigValueProviderFunc func(correlationId string, value string) (string, error)
+
+// Resolves the configuration value by calling the function.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - value string
+//  a value without the scheme prefix.
+// Returns string, error
+// the resolved value and error.
+func (c ConfigValueProviderFunc) Resolve(correlationId string, value string) (string, error) {
+	return c(correlationId, value)
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Network endpoint a component declares to listen on.
+
+Endpoints are collected from "connection" section of endpoints, services and controllers,
+that is components with "endpoint" descriptor type or the type ending with "service" or "controller".
+The port is taken from "connection.port" or from the port of "connection.uri".
+
+see
+ContainerConfig.GetListeners
+*/
+type ListenerEndpoint struct {
+	Component string `json:"component"`
+	Protocol  string `json:"protocol"`
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+}
+
+// Gets the transport of the endpoint: "udp" for UDP endpoints and "tcp" for the rest.
+// Returns string
+func (c *ListenerEndpoint) Transport() string {
+	if strings.ToLower(c.Protocol) == "udp" {
+		return "udp"
+	}
+	return "tcp"
+}
+
+// Checks if the endpoint listens on all network interfaces.
+// Returns bool
+func (c *ListenerEndpoint) IsWildcard() bool {
+	return c.Host == "" || c.Host == "0.0.0.0" || c.Host == "::" || c.Host == "[::]"
+}
+
+// Checks if two endpoints can't listen at the same time because they use the same port
+// on the same or on all network interfaces.
+// Parameters:
+//   - other *ListenerEndpoint
+//   an endpoint to compare with.
+// Returns bool
+func (c *ListenerEndpoint) ConflictsWith(other *ListenerEndpoint) bool {
+	if c.Port == 0 || c.Port != other.Port || c.Transport() != other.Transport() {
+		return false
+	}
+	return c.IsWildcard() || other.IsWildcard() || strings.EqualFold(c.Host, other.Host)
+}
+
+// Gets the endpoint address in host:port format.
+// Returns string
+func (c *ListenerEndpoint) String() string {
+	return c.Protocol + "://" + net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+// Checks if components of the descriptor type accept incoming connections.
+func isListenerType(typ string) bool {
+	return typ == "endpoint" || strings.HasSuffix(typ, "service") || strings.HasSuffix(typ, "controller")
+}
+
+// Reads the endpoint from "connection" section of component configuration.
+func readListenerEndpoint(component string, params *config.ConfigParams) *ListenerEndpoint {
+	connection := params.GetSection("connection")
+	endpoint := &ListenerEndpoint{
+		Component: component,
+		Protocol:  connection.GetAsStringWithDefault("protocol", "http"),
+		Host:      connection.GetAsStringWithDefault("host", connection.GetAsString("ip")),
+		Port:      connection.GetAsIntegerWithDefault("port", 0),
+	}
+
+	if uri := connection.GetAsString("uri"); uri != "" {
+		if parsed, err := url.Parse(uri); err == nil {
+			if parsed.Scheme != "" {
+				endpoint.Protocol = parsed.Scheme
+			}
+			if endpoint.Host == "" {
+				endpoint.Host = parsed.Hostname()
+			}
+			if port, err := strconv.Atoi(parsed.Port()); err == nil && endpoint.Port == 0 {
+				endpoint.Port = port
+			}
+		}
+	}
+
+	if endpoint.Port == 0 {
+		return nil
+	}
+	return endpoint
+}
+
+// Gets endpoints that components of the configuration declare to listen on.
+// Returns []*ListenerEndpoint
+// the declared endpoints.
+func (c ContainerConfig) GetListeners() []*ListenerEndpoint {
+	result := []*ListenerEndpoint{}
+	for _, componentConfig := range c {
+		if componentConfig == nil || componentConfig.Descriptor == nil || componentConfig.Config == nil {
+			continue
+		}
+		if !isListenerType(componentConfig.Descriptor.Type()) {
+			continue
+		}
+
+		endpoint := readListenerEndpoint(componentConfig.Descriptor.String(), componentConfig.Config)
+		if endpoint != nil {
+			result = append(result, endpoint)
+		}
+	}
+	return result
+}
+
+// Checks that components of the configuration don't declare to listen on the same ports.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+// Returns error
+// ConfigError with PORT_CONFLICT code when two components listen on the same port.
+func (c ContainerConfig) CheckListeners(correlationId string) error {
+	listeners := c.GetListeners()
+	for index, listener := range listeners {
+		for _, other := range listeners[index+1:] {
+			if listener.ConflictsWith(other) {
+				return errors.NewConfigError(
+					correlationId, "PORT_CONFLICT",
+					"Components "+listener.Component+" and "+other.Component+
+						" listen on the same port "+strconv.Itoa(listener.Port),
+				).WithDetails("port", listener.Port).
+					WithDetails("component1", listener.Component).
+					WithDetails("component2", other.Component)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+/*
+Parameters with default values keep configurations working when parameters,
+like environment variables, are not set. Defaults are defined as {{NAME|default}}
+or in shell style as ${NAME:-default}. The default is used when the parameter is missing or empty.
+
+Example
+  - descriptor: "pip-services:logger:console:default:1.0"
+    level: "{{LOG_LEVEL|info}}"
+
+  - descriptor: "mygroup:persistence:mongodb:default:1.0"
+    connection:
+      uri: "${MONGO_URI:-mongodb://localhost:27017/test}"
+*/
+
+var defaultParameterPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.\-]*)\s*\|([^{}]*)\}\}`)
+
+var shellDefaultParameterPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.\-]*):-([^{}]*)\}`)
+
+// Replaces parameters with default values by values of the parameters or by the defaults.
+func applyParameterDefaults(content string, parameters *config.ConfigParams) string {
+	replace := func(pattern *regexp.Regexp) {
+		content = pattern.ReplaceAllStringFunc(content, func(match string) string {
+			groups := pattern.FindStringSubmatch(match)
+			if parameters != nil {
+				if value := parameters.GetAsString(groups[1]); value != "" {
+					return value
+				}
+			}
+			return strings.TrimSpace(groups[2])
+		})
+	}
+
+	replace(defaultParameterPattern)
+	replace(shellDefaultParameterPattern)
+	return content
+}
+
+// Finds names of parameters referenced in shell style as ${NAME:-default}.
+func scanShellDefaultParameters(template string) []string {
+	names := []string{}
+	for _, match := range shellDefaultParameterPattern.FindAllStringSubmatch(template, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+	cconfig "github.com/pip-services3-go/pip-services3-components-go/config"
+)
+
+// Name of the parameter set which values are applied to all other sets.
+const DefaultParameterSet = "default"
+
+// Reads a named set of parameters from JSON, YAML or TOML file. The file contains
+// top-level sections named after parameter sets. Values from "default" section
+// are applied to every set and are overridden by values of the selected set.
+// It allows to configure many instances of the same service, for instance regional deployments,
+// with one parameters file.
+//
+// Example
+//   default:
+//     HTTP_PORT: 8080
+//   eu-west:
+//     REGION: eu-west-1
+//   us-east:
+//     REGION: us-east-1
+//     HTTP_PORT: 8081
+//
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - path string
+//  a path to the parameters file.
+//  - name string
+//  a name of the parameter set.
+// Returns *config.ConfigParams, error
+// values of the parameter set and ConfigError when the set is not defined in the file.
+func (c *TContainerConfigReader) ReadParameterSetFromFile(correlationId string,
+	path string, name string) (*config.ConfigParams, error) {
+	if path == "" {
+		return nil, errors.NewConfigError(correlationId, "NO_PATH", "Missing parameters file path")
+	}
+
+	var sets *config.ConfigParams
+	var err error
+	ext := filepath.Ext(path)
+	if ext == ".yaml" || ext == ".yml" {
+		sets, err = cconfig.ReadYamlConfig(correlationId, path, nil)
+	} else if ext == ".toml" {
+		sets, err = readTomlConfig(correlationId, path)
+	} else {
+		sets, err = cconfig.ReadJsonConfig(correlationId, path, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := sets.GetSectionNames()
+	found := false
+	for _, setName := range names {
+		if setName == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.NewConfigError(
+			correlationId, "PARAM_SET_NOT_FOUND", "Parameter set "+name+" is not defined in "+path,
+		).WithDetails("path", path).WithDetails("name", name).WithDetails("sets", strings.Join(names, ","))
+	}
+
+	result := config.NewEmptyConfigParams()
+	if name != DefaultParameterSet {
+		result = result.Override(sets.GetSection(DefaultParameterSet))
+	}
+	return result.Override(sets.GetSection(name)), nil
+}
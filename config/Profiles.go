@@ -0,0 +1,94 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+/*
+Profiles select sets of components (dev, staging, prod, feature-X) from a single configuration.
+Components are assigned to profiles by "profile" parameter. Components without profiles are always created.
+
+A profile is active when it is listed in "active_profiles" option of the container
+or when its "active_when" expression evaluates to true (see EvaluateExpression).
+
+Example
+  - descriptor: "pip-services:container:default:default:1.0"
+    active_profiles: "{{PROFILES}}"
+    profiles:
+      prod:
+        active_when: "{{ENV}} == 'prod' && {{REGION}} != 'cn'"
+
+  - descriptor: "mygroup:exporter:default:default:1.0"
+    profile: prod
+*/
+
+// Gets names of active profiles from container options.
+// Parameters:
+//   - options *config.ConfigParams
+//   the container options.
+//   - parameters *config.ConfigParams
+//   values of parameters referenced in "active_when" expressions.
+// Returns []string, error
+// sorted names of active profiles and ConfigError when an expression is malformed.
+func GetActiveProfiles(options *config.ConfigParams, parameters *config.ConfigParams) ([]string, error) {
+	active := map[string]bool{}
+
+	for _, name := range readStringList(options, "active_profiles") {
+		active[name] = true
+	}
+
+	profiles := options.GetSection("profiles")
+	for _, name := range profiles.GetSectionNames() {
+		expression := profiles.GetSection(name).GetAsString("active_when")
+		if expression == "" || active[name] {
+			continue
+		}
+
+		ok, err := EvaluateExpression(expression, parameters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			active[name] = true
+		}
+	}
+
+	result := []string{}
+	for name := range active {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// Selects components that belong to active profiles or have no profiles.
+// Parameters:
+//   - profiles []string
+//   names of active profiles.
+// Returns ContainerConfig
+// configurations of selected components.
+func (c ContainerConfig) FilterByProfiles(profiles []string) ContainerConfig {
+	result := []*ComponentConfig{}
+
+	for _, componentConfig := range c {
+		names := []string{}
+		if componentConfig.Config != nil {
+			names = readStringList(componentConfig.Config, "profile")
+		}
+
+		selected := len(names) == 0
+		for _, name := range names {
+			for _, profile := range profiles {
+				selected = selected || name == profile
+			}
+		}
+
+		if selected {
+			result = append(result, componentConfig)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+// Path that reads container configuration from the standard input,
+// so orchestration tools can pipe generated configurations without temporary files.
+const StdinPath = "-"
+
+// Checks if the path refers to the standard input.
+// Parameters:
+//  - path string
+//  a path to check.
+// Returns bool
+func IsStdinPath(path string) bool {
+	return path == StdinPath
+}
+
+// Reads container configuration from the standard input till the end.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - format string
+//  a file extension or a MIME type of the content or empty string for YAML, which also accepts JSON.
+//  - parameters *config.ConfigParams
+//  values to parameters the configuration or nil to skip parameterization.
+// Returns ContainerConfig, error
+// the read container configuration and error when the input can't be read or parsed.
+func (c *TContainerConfigReader) ReadFromStdin(correlationId string,
+	format string, parameters *config.ConfigParams) (ContainerConfig, error) {
+	if format == "" {
+		format = ".yml"
+	}
+	return c.ReadFromReader(correlationId, os.Stdin, format, parameters)
+}
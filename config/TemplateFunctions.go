@@ -0,0 +1,202 @@
+package config
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Built-in functions evaluated while the configuration is parameterized,
+so values don't have to be precomputed into environment variables by wrapper scripts.
+Functions are called as {{name()}} or {{name("argument")}}.
+Results are substituted as they are, without evaluating templates inside them, like "{{" in a file.
+Inside quoted strings they are escaped for the quotes, and multi-line results
+outside of quotes are written as double-quoted strings, so a file can hold a certificate or a key.
+
+Functions
+  - hostname(): the name of the host
+  - uuid(): a random UUID (version 4)
+  - now(): the current UTC time in RFC3339 format or in Go layout of the argument, like now("2006-01-02")
+  - env("NAME"): the value of the environment variable or empty string when it is not set
+  - file("path"): the content of the file without trailing line breaks
+
+Example
+  - descriptor: "pip-services:context-info:default:default:1.0"
+    name: "orders-{{hostname()}}"
+    properties:
+      instance_id: "{{uuid()}}"
+      started: "{{now()}}"
+
+  - descriptor: "mygroup:persistence:mongodb:default:1.0"
+    credential:
+      password: "{{file(\"/run/secrets/mongo_password\")}}"
+*/
+
+var templateFunctionPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\(\s*("(?:[^"\\]|\\.)*"|'[^']*')?\s*\)\s*\}\}`)
+
+var templateFunctions = map[string]func(argument string) (string, error){
+	"hostname": func(argument string) (string, error) {
+		return os.Hostname()
+	},
+	"uuid": func(argument string) (string, error) {
+		return newUuid()
+	},
+	"now": func(argument string) (string, error) {
+		if argument == "" {
+			argument = time.RFC3339
+		}
+		return time.Now().UTC().Format(argument), nil
+	},
+	"env": func(argument string) (string, error) {
+		return os.Getenv(argument), nil
+	},
+	"file": func(argument string) (string, error) {
+		data, err := ioutil.ReadFile(argument)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	},
+}
+
+// Replaces calls of built-in functions by references to generated parameters and returns values of the parameters.
+// The values are substituted by mustache together with the rest of parameters, so they are never evaluated as templates.
+func applyTemplateFunctions(content string) (string, map[string]string, error) {
+	matches := templateFunctionPattern.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content, nil, nil
+	}
+
+	values := map[string]string{}
+	builder := strings.Builder{}
+	last := 0
+	for _, match := range matches {
+		name := content[match[2]:match[3]]
+		argument := ""
+		if match[4] >= 0 {
+			argument = content[match[4]:match[5]]
+		}
+
+		function, ok := templateFunctions[name]
+		if !ok {
+			return "", nil, errors.NewConfigError(
+				"", "UNKNOWN_FUNCTION", "Unknown configuration template function "+name,
+			).WithDetails("function", name)
+		}
+		value, err := function(unquoteArgument(argument))
+		if err != nil {
+			return "", nil, errors.NewConfigError(
+				"", "FUNCTION_FAILED", "Configuration template function "+name+" failed: "+err.Error(),
+			).WithDetails("function", name).WithCause(err)
+		}
+
+		line := content[strings.LastIndex(content[:match[0]], "\n")+1 : match[0]]
+		parameter := fmt.Sprintf("template_function_%d", len(values))
+		values[parameter] = quoteFunctionValue(value, enclosingQuote(line))
+
+		builder.WriteString(content[last:match[0]])
+		builder.WriteString("{{" + parameter + "}}")
+		last = match[1]
+	}
+	builder.WriteString(content[last:])
+	return builder.String(), values, nil
+}
+
+// Finds the quote of the string that is opened at the end of the line or returns 0 when there is none.
+// Single quotes open a string only at the start of a value, so apostrophes in plain text are skipped.
+func enclosingQuote(line string) byte {
+	var quote byte
+	for index := 0; index < len(line); index++ {
+		ch := line[index]
+		switch {
+		case quote == '"' && ch == '\\':
+			index++
+		case quote != 0 && ch == quote:
+			quote = 0
+		case quote == 0 && ch == '"':
+			quote = ch
+		case quote == 0 && ch == '\'':
+			previous := strings.TrimRight(line[:index], " \t")
+			if previous == "" || strings.ContainsAny(previous[len(previous)-1:], ":=[{,-") {
+				quote = ch
+			}
+		}
+	}
+	return quote
+}
+
+// Escapes the result of a function for the quotes it is written in.
+func quoteFunctionValue(value string, quote byte) string {
+	switch quote {
+	case '"':
+		return escapeFunctionValue(value)
+	case '\'':
+		return strings.ReplaceAll(value, "'", "''")
+	default:
+		if strings.ContainsAny(value, "\r\n") {
+			return "\"" + escapeFunctionValue(value) + "\""
+		}
+		return value
+	}
+}
+
+// Escapes backslashes, quotes and control characters with escape sequences
+// that are shared by JSON, YAML and TOML double-quoted strings.
+func escapeFunctionValue(value string) string {
+	builder := strings.Builder{}
+	for _, ch := range value {
+		switch ch {
+		case '\\':
+			builder.WriteString(`\\`)
+		case '"':
+			builder.WriteString(`\"`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			if ch < 0x20 {
+				builder.WriteString(fmt.Sprintf("\\u%04x", ch))
+			} else {
+				builder.WriteRune(ch)
+			}
+		}
+	}
+	return builder.String()
+}
+
+// Checks if the template tag is a call of a function.
+func isTemplateFunctionCall(tag string) bool {
+	return templateFunctionPattern.MatchString("{{" + tag + "}}")
+}
+
+// Strips quotes from the function argument.
+func unquoteArgument(argument string) string {
+	if len(argument) < 2 {
+		return argument
+	}
+	if argument[0] == '\'' {
+		return argument[1 : len(argument)-1]
+	}
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(argument[1 : len(argument)-1])
+}
+
+// Generates a random UUID of version 4.
+func newUuid() (string, error) {
+	value := make([]byte, 16)
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+	value[6] = (value[6] & 0x0f) | 0x40
+	value[8] = (value[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", value[0:4], value[4:6], value[6:8], value[8:10], value[10:16]), nil
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+)
+
+/*
+Replacement of component implementation used in tests.
+Components which descriptors match Descriptor are created with ReplaceWith descriptor instead.
+Wildcard "*" fields of ReplaceWith keep values of the original descriptor,
+so one override can swap implementations of several named components.
+
+see
+TestOverrides
+*/
+type TestOverride struct {
+	Descriptor  *refer.Descriptor
+	ReplaceWith *refer.Descriptor
+}
+
+/*
+Overlay that swaps component implementations, for instance persistences and clients, with
+in-memory fakes, so integration tests can run the full container with production configurations.
+
+The overlay is usually kept in test_overrides.yml file and loaded by ProcessContainer in test mode.
+
+Example
+  overrides:
+    - descriptor: "mygroup:persistence:mongodb:*:1.0"
+      replace_with: "mygroup:persistence:memory:*:1.0"
+    - descriptor: "mygroup:client:http:*:1.0"
+      replace_with: "mygroup:client:mock:*:1.0"
+
+see
+ContainerConfig.ApplyTestOverrides
+*/
+type TestOverrides []*TestOverride
+
+// Reads test overrides from "overrides" section of configuration parameters.
+// Parameters:
+//  - config *config.ConfigParams
+//  configuration parameters of the overlay.
+// Returns TestOverrides, error
+// the read overrides and ConfigError when an override is malformed.
+func ReadTestOverridesFromConfig(config *config.ConfigParams) (TestOverrides, error) {
+	result := TestOverrides{}
+	if config == nil {
+		return result, nil
+	}
+
+	overrides := config.GetSection("overrides")
+	// Section names are array indexes, so they are sorted as numbers
+	for index := 0; ; index++ {
+		section := overrides.GetSection(strconv.Itoa(index))
+		if section.Len() == 0 {
+			break
+		}
+
+		descriptor, err := refer.ParseDescriptorFromString(section.GetAsString("descriptor"))
+		if err != nil {
+			return nil, err
+		}
+		replaceWith, err := refer.ParseDescriptorFromString(section.GetAsString("replace_with"))
+		if err != nil {
+			return nil, err
+		}
+		if descriptor == nil || replaceWith == nil {
+			return nil, errors.NewConfigError(
+				"", "BAD_TEST_OVERRIDE", "Test override must have descriptor and replace_with",
+			).WithDetails("index", index)
+		}
+
+		result = append(result, &TestOverride{Descriptor: descriptor, ReplaceWith: replaceWith})
+	}
+	return result, nil
+}
+
+// Reads test overrides from JSON, YAML, TOML file or a file in registered format.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - path string
+//  a path to the overlay file.
+// Returns TestOverrides, error
+// the read overrides and error when the file can't be read or is malformed.
+func (c *TContainerConfigReader) ReadTestOverridesFromFile(correlationId string,
+	path string) (TestOverrides, error) {
+	if path == "" {
+		return nil, errors.NewConfigError(correlationId, "NO_PATH", "Missing test overrides file path")
+	}
+
+	var overlay *config.ConfigParams
+	var err error
+	format := filepath.Ext(path)
+	if format == ".toml" {
+		// TOML reader returns components, so the document is parsed as is
+		overlay, err = readTomlConfig(correlationId, path)
+	} else {
+		if c.GetFormatReader(format) == nil {
+			format = ".json"
+		}
+		var data []byte
+		data, err = readFile(correlationId, path)
+		if err == nil {
+			overlay, err = c.ReadConfigParams(correlationId, data, format, nil)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadTestOverridesFromConfig(overlay)
+}
+
+// Gets the replacement descriptor for the component or nil when no override matches it.
+// Parameters:
+//  - descriptor *refer.Descriptor
+//  the component descriptor.
+// Returns *refer.Descriptor
+func (c TestOverrides) Find(descriptor *refer.Descriptor) *refer.Descriptor {
+	if descriptor == nil {
+		return nil
+	}
+
+	for _, override := range c {
+		if override.Descriptor.Match(descriptor) {
+			return refer.NewDescriptor(
+				replaceWildcard(override.ReplaceWith.Group(), descriptor.Group()),
+				replaceWildcard(override.ReplaceWith.Type(), descriptor.Type()),
+				replaceWildcard(override.ReplaceWith.Kind(), descriptor.Kind()),
+				replaceWildcard(override.ReplaceWith.Name(), descriptor.Name()),
+				replaceWildcard(override.ReplaceWith.Version(), descriptor.Version()),
+			)
+		}
+	}
+	return nil
+}
+
+func replaceWildcard(value string, original string) string {
+	if value == "" || value == "*" {
+		return original
+	}
+	return value
+}
+
+// Swaps implementations of components according to test overrides.
+// Components created by type and components without matching overrides are kept unchanged.
+// Parameters:
+//  - overrides TestOverrides
+//  the overrides to apply.
+// Returns ContainerConfig
+// a new configuration with replaced component descriptors.
+func (c ContainerConfig) ApplyTestOverrides(overrides TestOverrides) ContainerConfig {
+	result := make(ContainerConfig, 0, len(c))
+	for _, componentConfig := range c {
+		descriptor := overrides.Find(componentConfig.Descriptor)
+		if descriptor == nil {
+			result = append(result, componentConfig)
+			continue
+		}
+
+		replaced := *componentConfig
+		replaced.Descriptor = descriptor
+		if componentConfig.Config != nil {
+			replaced.Config = componentConfig.Config.Override(
+				config.NewConfigParamsFromTuples("descriptor", descriptor.String()),
+			)
+		}
+		result = append(result, &replaced)
+	}
+	return result
+}
@@ -0,0 +1,517 @@
+package config
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Parser of TOML documents into a tree of maps and arrays, the same structure
+JSON and YAML decoders produce for container configurations.
+
+It supports tables, arrays of tables, dotted and quoted keys, basic, literal and multi-line strings,
+integers, floats, booleans, arrays and inline tables. Dates and times are kept as strings.
+Numbers follow TOML rules, so leading zeros, misplaced underscores and trailing garbage are rejected
+instead of being read with Go number syntax. Tables can't be defined more than once.
+*/
+type tomlParser struct {
+	content  string
+	position int
+	line     int
+	root     map[string]interface{}
+	current  map[string]interface{}
+	path     string
+	// Paths of tables defined by [table] headers
+	headers map[string]bool
+	// Paths of tables defined by dotted keys
+	dotted map[string]bool
+	// Paths of values that can't be extended: inline tables and static arrays
+	sealed map[string]bool
+}
+
+var (
+	tomlDecimalPattern = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)$`)
+	tomlHexPattern     = regexp.MustCompile(`^0x[0-9A-Fa-f](_?[0-9A-Fa-f])*$`)
+	tomlOctalPattern   = regexp.MustCompile(`^0o[0-7](_?[0-7])*$`)
+	tomlBinaryPattern  = regexp.MustCompile(`^0b[01](_?[01])*$`)
+	tomlFloatPattern   = regexp.MustCompile(
+		`^[+-]?(0|[1-9](_?[0-9])*)(\.[0-9](_?[0-9])*)?([eE][+-]?[0-9](_?[0-9])*)?$`)
+	tomlDateTimePattern = regexp.MustCompile(
+		`^[0-9]{4}-[0-9]{2}-[0-9]{2}([Tt ][0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?([Zz]|[+-][0-9]{2}:[0-9]{2})?)?$`)
+	tomlTimePattern = regexp.MustCompile(`^[0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?$`)
+)
+
+// Parses TOML document.
+// Parameters:
+//   - content string
+//   a TOML document.
+// Returns map[string]interface{}, error
+// the document root table and ConfigError when the document is malformed.
+func parseToml(content string) (map[string]interface{}, error) {
+	parser := &tomlParser{
+		content: content,
+		line:    1,
+		root:    map[string]interface{}{},
+		headers: map[string]bool{},
+		dotted:  map[string]bool{},
+		sealed:  map[string]bool{},
+	}
+	parser.current = parser.root
+
+	for {
+		parser.skipSpaceAndComments(true)
+		if parser.eof() {
+			break
+		}
+
+		var err error
+		if parser.peek() == '[' {
+			err = parser.parseTableHeader()
+		} else {
+			err = parser.parseKeyValue(parser.current, parser.path, true)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		parser.skipSpaceAndComments(false)
+		if !parser.eof() && parser.peek() != '\n' && parser.peek() != '\r' {
+			return nil, parser.newError("Expected end of line")
+		}
+	}
+
+	return parser.root, nil
+}
+
+func (c *tomlParser) newError(message string) error {
+	return errors.NewConfigError(
+		"", "BAD_TOML", message+" at line "+strconv.Itoa(c.line),
+	).WithDetails("line", c.line)
+}
+
+// Appends a key to the path of a table. Keys are quoted, so dots in quoted keys don't clash.
+func joinTomlPath(path string, key string) string {
+	return path + "." + strconv.Quote(key)
+}
+
+func (c *tomlParser) eof() bool {
+	return c.position >= len(c.content)
+}
+
+func (c *tomlParser) peek() byte {
+	if c.eof() {
+		return 0
+	}
+	return c.content[c.position]
+}
+
+func (c *tomlParser) next() byte {
+	ch := c.peek()
+	c.position++
+	if ch == '\n' {
+		c.line++
+	}
+	return ch
+}
+
+// Skips spaces and comments, including line breaks when multiline is set.
+func (c *tomlParser) skipSpaceAndComments(multiline bool) {
+	for !c.eof() {
+		ch := c.peek()
+		switch {
+		case ch == ' ' || ch == '\t':
+			c.next()
+		case multiline && (ch == '\n' || ch == '\r'):
+			c.next()
+		case ch == '#':
+			for !c.eof() && c.peek() != '\n' {
+				c.next()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (c *tomlParser) parseTableHeader() error {
+	c.next()
+	isArray := c.peek() == '['
+	if isArray {
+		c.next()
+	}
+
+	keys, err := c.parseKey()
+	if err != nil {
+		return err
+	}
+
+	c.skipSpaceAndComments(false)
+	if c.next() != ']' || (isArray && c.next() != ']') {
+		return c.newError("Expected ] after table name")
+	}
+
+	table := c.root
+	path := ""
+	for index, key := range keys {
+		last := index == len(keys)-1
+		value, ok := table[key]
+		path = joinTomlPath(path, key)
+		if c.sealed[path] {
+			return c.newError("Key " + key + " is already defined")
+		}
+
+		if last && isArray {
+			array, _ := value.([]interface{})
+			if ok && array == nil {
+				return c.newError("Key " + key + " is not an array of tables")
+			}
+			item := map[string]interface{}{}
+			table[key] = append(array, item)
+			table = item
+			path += "[" + strconv.Itoa(len(array)) + "]"
+			break
+		}
+
+		if !ok {
+			child := map[string]interface{}{}
+			table[key] = child
+			table = child
+			continue
+		}
+
+		switch child := value.(type) {
+		case map[string]interface{}:
+			table = child
+		case []interface{}:
+			// Nested tables refer to the last element of array of tables
+			item, ok := child[len(child)-1].(map[string]interface{})
+			if !ok || last {
+				return c.newError("Key " + key + " is already defined")
+			}
+			table = item
+			path += "[" + strconv.Itoa(len(child)-1) + "]"
+		default:
+			return c.newError("Key " + key + " is already defined")
+		}
+	}
+
+	if !isArray {
+		if c.headers[path] || c.dotted[path] {
+			return c.newError("Table " + strings.Join(keys, ".") + " is already defined")
+		}
+		c.headers[path] = true
+	}
+
+	c.current = table
+	c.path = path
+	return nil
+}
+
+// Parses a key/value pair into the table. Definitions are tracked only for tables
+// that can be referred by headers, that is outside of inline tables.
+func (c *tomlParser) parseKeyValue(table map[string]interface{}, path string, tracked bool) error {
+	keys, err := c.parseKey()
+	if err != nil {
+		return err
+	}
+
+	c.skipSpaceAndComments(false)
+	if c.next() != '=' {
+		return c.newError("Expected = after key")
+	}
+	c.skipSpaceAndComments(false)
+
+	value, err := c.parseValue()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys[:len(keys)-1] {
+		path = joinTomlPath(path, key)
+		if tracked && (c.headers[path] || c.sealed[path]) {
+			return c.newError("Key " + key + " is already defined")
+		}
+		child, ok := table[key]
+		if !ok {
+			child = map[string]interface{}{}
+			table[key] = child
+		}
+		if table, ok = child.(map[string]interface{}); !ok {
+			return c.newError("Key " + key + " is already defined")
+		}
+		if tracked {
+			c.dotted[path] = true
+		}
+	}
+
+	key := keys[len(keys)-1]
+	if _, ok := table[key]; ok {
+		return c.newError("Key " + key + " is already defined")
+	}
+	table[key] = value
+	if tracked {
+		c.sealed[joinTomlPath(path, key)] = true
+	}
+	return nil
+}
+
+// Parses a dotted key where each part is bare or quoted.
+func (c *tomlParser) parseKey() ([]string, error) {
+	keys := []string{}
+	for {
+		c.skipSpaceAndComments(false)
+
+		var key string
+		var err error
+		switch ch := c.peek(); {
+		case ch == '"' || ch == '\'':
+			key, err = c.parseString()
+			if err != nil {
+				return nil, err
+			}
+		default:
+			start := c.position
+			for !c.eof() && isBareKeyChar(c.peek()) {
+				c.next()
+			}
+			key = c.content[start:c.position]
+			if key == "" {
+				return nil, c.newError("Expected key")
+			}
+		}
+		keys = append(keys, key)
+
+		c.skipSpaceAndComments(false)
+		if c.peek() != '.' {
+			return keys, nil
+		}
+		c.next()
+	}
+}
+
+func isBareKeyChar(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '_' || ch == '-'
+}
+
+func (c *tomlParser) parseValue() (interface{}, error) {
+	switch ch := c.peek(); {
+	case ch == '"' || ch == '\'':
+		return c.parseString()
+	case ch == '[':
+		return c.parseArray()
+	case ch == '{':
+		return c.parseInlineTable()
+	}
+
+	start := c.position
+	for !c.eof() && !strings.ContainsRune(",]}#\r\n", rune(c.peek())) {
+		c.next()
+	}
+	token := strings.TrimSpace(c.content[start:c.position])
+	if token == "" {
+		return nil, c.newError("Expected value")
+	}
+	return c.parseScalar(token)
+}
+
+func (c *tomlParser) parseScalar(token string) (interface{}, error) {
+	switch token {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf", "-inf", "nan", "+nan", "-nan":
+		value, _ := strconv.ParseFloat(strings.Replace(token, "inf", "Inf", 1), 64)
+		return value, nil
+	}
+
+	number := strings.ReplaceAll(token, "_", "")
+	switch {
+	case tomlDecimalPattern.MatchString(token):
+		return c.parseInteger(token, number, 10)
+	case tomlHexPattern.MatchString(token):
+		return c.parseInteger(token, number[2:], 16)
+	case tomlOctalPattern.MatchString(token):
+		return c.parseInteger(token, number[2:], 8)
+	case tomlBinaryPattern.MatchString(token):
+		return c.parseInteger(token, number[2:], 2)
+	case tomlFloatPattern.MatchString(token):
+		value, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return nil, c.newError("Invalid float " + token)
+		}
+		return value, nil
+	case tomlDateTimePattern.MatchString(token) || tomlTimePattern.MatchString(token):
+		// Dates and times are kept as strings
+		return token, nil
+	}
+
+	return nil, c.newError("Invalid value " + token)
+}
+
+func (c *tomlParser) parseInteger(token string, digits string, base int) (interface{}, error) {
+	value, err := strconv.ParseInt(digits, base, 64)
+	if err != nil {
+		return nil, c.newError("Integer " + token + " is out of range")
+	}
+	return value, nil
+}
+
+func (c *tomlParser) parseString() (string, error) {
+	quote := c.next()
+	multiline := strings.HasPrefix(c.content[c.position:], string([]byte{quote, quote}))
+	if multiline {
+		c.next()
+		c.next()
+		// A line break right after the opening delimiter is trimmed
+		if c.peek() == '\r' {
+			c.next()
+		}
+		if c.peek() == '\n' {
+			c.next()
+		}
+	}
+
+	var builder strings.Builder
+	for {
+		if c.eof() {
+			return "", c.newError("Unterminated string")
+		}
+
+		ch := c.next()
+		if ch == quote {
+			if !multiline {
+				return builder.String(), nil
+			}
+			// Up to two quotes may precede the closing delimiter
+			count := 1
+			for c.peek() == quote && count < 5 {
+				c.next()
+				count++
+			}
+			if count >= 3 {
+				builder.WriteString(strings.Repeat(string(quote), count-3))
+				return builder.String(), nil
+			}
+			builder.WriteString(strings.Repeat(string(quote), count))
+			continue
+		}
+
+		if ch == '\n' && !multiline {
+			return "", c.newError("Unterminated string")
+		}
+
+		if ch != '\\' || quote == '\'' {
+			builder.WriteByte(ch)
+			continue
+		}
+
+		err := c.parseEscape(&builder, multiline)
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+func (c *tomlParser) parseEscape(builder *strings.Builder, multiline bool) error {
+	ch := c.next()
+	switch ch {
+	case 'b':
+		builder.WriteByte('\b')
+	case 't':
+		builder.WriteByte('\t')
+	case 'n':
+		builder.WriteByte('\n')
+	case 'f':
+		builder.WriteByte('\f')
+	case 'r':
+		builder.WriteByte('\r')
+	case '"':
+		builder.WriteByte('"')
+	case '\\':
+		builder.WriteByte('\\')
+	case 'u', 'U':
+		size := 4
+		if ch == 'U' {
+			size = 8
+		}
+		if c.position+size > len(c.content) {
+			return c.newError("Invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(c.content[c.position:c.position+size], 16, 32)
+		if err != nil {
+			return c.newError("Invalid unicode escape")
+		}
+		builder.WriteRune(rune(code))
+		c.position += size
+	default:
+		// Line ending backslash trims the line break and following whitespace
+		if multiline && (ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n') {
+			c.position--
+			for !c.eof() && strings.ContainsRune(" \t\r\n", rune(c.peek())) {
+				c.next()
+			}
+			return nil
+		}
+		return c.newError("Invalid escape sequence \\" + string(ch))
+	}
+	return nil
+}
+
+func (c *tomlParser) parseArray() ([]interface{}, error) {
+	c.next()
+	result := []interface{}{}
+
+	for {
+		c.skipSpaceAndComments(true)
+		if c.peek() == ']' {
+			c.next()
+			return result, nil
+		}
+
+		value, err := c.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+
+		c.skipSpaceAndComments(true)
+		switch c.next() {
+		case ',':
+		case ']':
+			return result, nil
+		default:
+			return nil, c.newError("Expected , or ] in array")
+		}
+	}
+}
+
+func (c *tomlParser) parseInlineTable() (map[string]interface{}, error) {
+	c.next()
+	result := map[string]interface{}{}
+
+	c.skipSpaceAndComments(false)
+	if c.peek() == '}' {
+		c.next()
+		return result, nil
+	}
+
+	for {
+		err := c.parseKeyValue(result, "", false)
+		if err != nil {
+			return nil, err
+		}
+
+		c.skipSpaceAndComments(false)
+		switch c.next() {
+		case ',':
+		case '}':
+			return result, nil
+		default:
+			return nil, c.newError("Expected , or } in inline table")
+		}
+	}
+}
@@ -0,0 +1,408 @@
+package container
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+)
+
+// Names of metrics that alert rules can be declared on.
+const (
+	// Number of restarts of a component within the window.
+	AlertMetricRestarts = "restarts"
+	// Number of failures to open or close a component within the window.
+	AlertMetricFailures = "failures"
+	// Number of configuration reloads within the window.
+	AlertMetricReloads = "reloads"
+	// Duration of the last container open in milliseconds.
+	AlertMetricOpenDuration = "open_duration"
+	// Degradation score of the container from 0 to 1 (see DegradationScore).
+	AlertMetricDegradation = "degradation"
+	// Memory used by the process in bytes.
+	AlertMetricMemoryBytes = "memory_bytes"
+)
+
+var alertMetrics = map[string]bool{
+	AlertMetricRestarts:     true,
+	AlertMetricFailures:     true,
+	AlertMetricReloads:      true,
+	AlertMetricOpenDuration: true,
+	AlertMetricDegradation:  true,
+	AlertMetricMemoryBytes:  true,
+}
+
+// Rule that raises an alert when the metric crosses the threshold.
+type AlertRule struct {
+	Name      string
+	Metric    string
+	Operator  string
+	Threshold float64
+	Window    time.Duration
+}
+
+// Checks if the metric value violates the rule.
+// Parameters:
+//   - value float64
+//   the metric value.
+// Returns bool
+func (c *AlertRule) IsViolated(value float64) bool {
+	switch c.Operator {
+	case ">=":
+		return value >= c.Threshold
+	case "<":
+		return value < c.Threshold
+	case "<=":
+		return value <= c.Threshold
+	default:
+		return value > c.Threshold
+	}
+}
+
+// Alert raised when a metric of the container or its component violates a rule.
+type AlertViolation struct {
+	Rule      string    `json:"rule"`
+	Metric    string    `json:"metric"`
+	Component string    `json:"component,omitempty"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Time      time.Time `json:"time"`
+}
+
+/*
+Evaluates declarative alert rules over container metrics and raises alerts without external monitors.
+Metrics of components, like restarts and failures, are evaluated for each component separately.
+An alert is raised once when the rule becomes violated and resolved when the metric returns within the threshold.
+The container logs raised alerts at error level and notifies components with "alert" and "alert_resolved" events.
+
+Metrics
+  - restarts: number of restarts of a component within the window
+  - failures: number of failures to open or close a component within the window
+  - reloads: number of configuration reloads within the window
+  - open_duration: duration of the last container open in milliseconds
+  - degradation: degradation score of the container from 0 to 1
+  - memory_bytes: memory used by the process in bytes
+
+Configuration parameters
+  - alerts:
+    - interval: time in milliseconds between evaluations of rules (default: 5000)
+    - rules: list of rules
+      - name: a rule name used in alerts
+      - metric: a metric name
+      - operator: comparison with the threshold: >, >=, <, <= (default: >)
+      - threshold: a threshold value
+      - window: time in milliseconds to count events in, 0 for all kept events (default: 0)
+
+Counted events are taken from the container history, so the window can't exceed the events it keeps
+(see LifecycleHistory).
+
+Example
+  - descriptor: "pip-services:container:default:default:1.0"
+    alerts:
+      rules:
+        - name: restart_storm
+          metric: restarts
+          threshold: 3
+          window: 600000
+        - name: slow_open
+          metric: open_duration
+          threshold: 30000
+
+see
+Container.GetActiveAlerts
+*/
+type AlertMonitor struct {
+	logger   log.ILogger
+	rules    []*AlertRule
+	interval time.Duration
+	active   map[string]*AlertViolation
+	stop     chan struct{}
+	lock     sync.Mutex
+}
+
+// Creates a new instance of the alert monitor.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to report invalid rules.
+// Returns *AlertMonitor
+func NewAlertMonitor(logger log.ILogger) *AlertMonitor {
+	return &AlertMonitor{
+		logger:   logger,
+		rules:    []*AlertRule{},
+		interval: 5 * time.Second,
+		active:   map[string]*AlertViolation{},
+	}
+}
+
+// Sets the logger used to report invalid rules.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to be set.
+func (c *AlertMonitor) SetLogger(logger log.ILogger) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.logger = logger
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *AlertMonitor) Configure(config *cconfig.ConfigParams) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	interval := config.GetAsLongWithDefault("alerts.interval", int64(c.interval/time.Millisecond))
+	c.interval = time.Duration(interval) * time.Millisecond
+
+	rules := []*AlertRule{}
+	section := config.GetSection("alerts.rules")
+	// Rules are read by indexes, since section names are sorted as strings
+	for index := 0; ; index++ {
+		ruleConfig := section.GetSection(strconv.Itoa(index))
+		if len(ruleConfig.Keys()) == 0 {
+			break
+		}
+
+		rule := &AlertRule{
+			Name:      ruleConfig.GetAsString("name"),
+			Metric:    ruleConfig.GetAsString("metric"),
+			Operator:  ruleConfig.GetAsStringWithDefault("operator", ">"),
+			Threshold: ruleConfig.GetAsDoubleWithDefault("threshold", 0),
+			Window:    time.Duration(ruleConfig.GetAsLong("window")) * time.Millisecond,
+		}
+		if rule.Name == "" {
+			rule.Name = rule.Metric
+		}
+		if !alertMetrics[rule.Metric] {
+			c.logger.Error("", nil, "Alert rule %s has unknown metric %s", rule.Name, rule.Metric)
+			continue
+		}
+		if rule.Operator != ">" && rule.Operator != ">=" && rule.Operator != "<" && rule.Operator != "<=" {
+			c.logger.Error("", nil, "Alert rule %s has unknown operator %s", rule.Name, rule.Operator)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	c.rules = rules
+}
+
+// Adds a rule to the monitor.
+// Parameters:
+//   - rule *AlertRule
+//   a rule to be added.
+func (c *AlertMonitor) AddRule(rule *AlertRule) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rules = append(c.rules, rule)
+}
+
+// Evaluates rules and gets alerts that were raised or resolved since the previous evaluation.
+// Parameters:
+//   - metric func(metric string, window time.Duration) map[string]float64
+//   a function that gets values of the metric indexed by component names,
+//   or by empty string for metrics of the container.
+// Returns []*AlertViolation, []*AlertViolation
+// raised and resolved alerts.
+func (c *AlertMonitor) Check(metric func(metric string, window time.Duration) map[string]float64) ([]*AlertViolation, []*AlertViolation) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now().UTC()
+	raised := []*AlertViolation{}
+	resolved := []*AlertViolation{}
+	violated := map[string]bool{}
+
+	for _, rule := range c.rules {
+		values := metric(rule.Metric, rule.Window)
+		components := []string{}
+		for component := range values {
+			components = append(components, component)
+		}
+		sort.Strings(components)
+
+		for _, component := range components {
+			value := values[component]
+			if !rule.IsViolated(value) {
+				continue
+			}
+
+			key := rule.Name + "/" + component
+			violated[key] = true
+			if _, ok := c.active[key]; ok {
+				continue
+			}
+
+			violation := &AlertViolation{
+				Rule:      rule.Name,
+				Metric:    rule.Metric,
+				Component: component,
+				Value:     value,
+				Threshold: rule.Threshold,
+				Time:      now,
+			}
+			c.active[key] = violation
+			raised = append(raised, violation)
+		}
+	}
+
+	for key, violation := range c.active {
+		if !violated[key] {
+			delete(c.active, key)
+			resolved = append(resolved, violation)
+		}
+	}
+
+	return raised, resolved
+}
+
+// Gets alerts that are currently raised.
+// Returns []*AlertViolation
+func (c *AlertMonitor) GetActive() []*AlertViolation {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result := []*AlertViolation{}
+	for _, violation := range c.active {
+		result = append(result, violation)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result
+}
+
+// Starts periodic evaluation of rules when there are any.
+// Parameters:
+//   - metric func(metric string, window time.Duration) map[string]float64
+//   a function that gets values of the metric.
+//   - handle func(raised []*AlertViolation, resolved []*AlertViolation)
+//   a function called when alerts are raised or resolved.
+func (c *AlertMonitor) Start(metric func(metric string, window time.Duration) map[string]float64,
+	handle func(raised []*AlertViolation, resolved []*AlertViolation)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.rules) == 0 || c.interval <= 0 || c.stop != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	c.stop = stop
+	interval := c.interval
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				raised, resolved := c.Check(metric)
+				if len(raised) > 0 || len(resolved) > 0 {
+					handle(raised, resolved)
+				}
+			}
+		}
+	}()
+}
+
+// Stops evaluation of rules and clears raised alerts.
+func (c *AlertMonitor) Stop() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+	c.active = map[string]*AlertViolation{}
+}
+
+// Gets alerts that are currently raised by alert rules.
+// Returns []*AlertViolation
+func (c *Container) GetActiveAlerts() []*AlertViolation {
+	return c.alerts.GetActive()
+}
+
+// Gets values of the alert metric from the container history and state.
+func (c *Container) getAlertMetric(metric string, window time.Duration) map[string]float64 {
+	result := map[string]float64{}
+	since := time.Time{}
+	if window > 0 {
+		since = time.Now().Add(-window)
+	}
+
+	switch metric {
+	case AlertMetricRestarts, AlertMetricFailures:
+		event := HistoryComponentRestarted
+		if metric == AlertMetricFailures {
+			event = HistoryComponentFailed
+		}
+		for _, entry := range c.history.GetEntries(since) {
+			if entry.Event == event {
+				result[entry.Component]++
+			}
+		}
+	case AlertMetricReloads:
+		result[""] = 0
+		for _, entry := range c.history.GetEntries(since) {
+			if entry.Event == EventReloaded {
+				result[""]++
+			}
+		}
+	case AlertMetricOpenDuration:
+		var opening time.Time
+		for _, entry := range c.history.GetEntries(time.Time{}) {
+			if entry.Event == EventOpening {
+				opening = entry.Time
+			} else if entry.Event == EventOpened && !opening.IsZero() {
+				result[""] = float64(entry.Time.Sub(opening) / time.Millisecond)
+			}
+		}
+	case AlertMetricDegradation:
+		if degradation := c.GetDegradation(""); degradation != nil {
+			result[""] = degradation.Score
+		}
+	case AlertMetricMemoryBytes:
+		if report := c.GetResourceUsage(); report != nil {
+			result[""] = float64(report.MemoryBytes)
+		}
+	}
+	return result
+}
+
+// Logs raised alerts at error level and notifies components about raised and resolved alerts.
+func (c *Container) handleAlerts(correlationId string, raised []*AlertViolation, resolved []*AlertViolation) {
+	for _, violation := range raised {
+		c.logger.Error(correlationId, nil, "Alert %s is raised: %s of %s is %v, threshold %v",
+			violation.Rule, violation.Metric, c.getAlertSubject(violation), violation.Value, violation.Threshold)
+		c.notifyAlert(correlationId, EventAlert, violation)
+	}
+	for _, violation := range resolved {
+		c.logger.Info(correlationId, "Alert %s is resolved for %s", violation.Rule, c.getAlertSubject(violation))
+		c.notifyAlert(correlationId, EventAlertResolved, violation)
+	}
+}
+
+func (c *Container) getAlertSubject(violation *AlertViolation) string {
+	if violation.Component != "" {
+		return violation.Component
+	}
+	return "container " + c.info.Name
+}
+
+func (c *Container) notifyAlert(correlationId string, event string, violation *AlertViolation) {
+	c.notify(correlationId, event,
+		"rule", violation.Rule,
+		"metric", violation.Metric,
+		"component", violation.Component,
+		"value", violation.Value,
+		"threshold", violation.Threshold,
+	)
+}
@@ -0,0 +1,218 @@
+package container
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+/*
+Reads cloud instance metadata (AWS, GCP or Azure) to add placement information
+into ContextInfo properties, so logs and metrics automatically carry it.
+
+Metadata services are probed in parallel with a short timeout. When none of them
+responds the properties are left untouched.
+
+Configuration parameters
+  - cloud_metadata:
+    - enabled: true to read cloud metadata (default: false)
+    - timeout: probe timeout in milliseconds (default: 1000)
+    - endpoints:
+      - aws: base URL of AWS metadata service (default: http://169.254.169.254/latest)
+      - gcp: base URL of GCP metadata service (default: http://metadata.google.internal/computeMetadata/v1/instance)
+      - azure: URL of Azure metadata service (default: http://169.254.169.254/metadata/instance?api-version=2021-02-01)
+
+Resulting properties
+  - cloud.provider: aws, gcp or azure
+  - cloud.instance_id: id of the instance
+  - cloud.zone: availability zone
+  - cloud.instance_type: instance type (machine size)
+*/
+type CloudMetadata struct {
+	enabled   bool
+	timeout   time.Duration
+	endpoints map[string]string
+	client    *http.Client
+}
+
+var cloudMetadataEndpoints = map[string]string{
+	"aws":   "http://169.254.169.254/latest",
+	"gcp":   "http://metadata.google.internal/computeMetadata/v1/instance",
+	"azure": "http://169.254.169.254/metadata/instance?api-version=2021-02-01",
+}
+
+// Creates a new instance of cloud metadata reader.
+// Returns *CloudMetadata
+func NewCloudMetadata() *CloudMetadata {
+	endpoints := map[string]string{}
+	for provider, url := range cloudMetadataEndpoints {
+		endpoints[provider] = url
+	}
+	return &CloudMetadata{
+		enabled:   false,
+		timeout:   time.Second,
+		endpoints: endpoints,
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *CloudMetadata) Configure(config *cconfig.ConfigParams) {
+	c.enabled = config.GetAsBooleanWithDefault("cloud_metadata.enabled", c.enabled)
+	timeout := config.GetAsLongWithDefault("cloud_metadata.timeout", int64(c.timeout/time.Millisecond))
+	c.timeout = time.Duration(timeout) * time.Millisecond
+	for provider := range c.endpoints {
+		c.endpoints[provider] = config.GetAsStringWithDefault("cloud_metadata.endpoints."+provider, c.endpoints[provider])
+	}
+}
+
+// Checks if reading of cloud metadata is enabled.
+// Returns bool
+func (c *CloudMetadata) IsEnabled() bool {
+	return c.enabled
+}
+
+// Reads metadata of the cloud instance the process runs on.
+// Returns map[string]string
+// properties with cloud metadata or empty map if the process doesn't run in a supported cloud.
+func (c *CloudMetadata) Read() map[string]string {
+	c.client = &http.Client{Timeout: c.timeout}
+	results := make(chan map[string]string, len(c.endpoints))
+
+	for provider := range c.endpoints {
+		go func(provider string) {
+			var properties map[string]string
+			switch provider {
+			case "aws":
+				properties = c.readAws()
+			case "gcp":
+				properties = c.readGcp()
+			case "azure":
+				properties = c.readAzure()
+			}
+			results <- properties
+		}(provider)
+	}
+
+	for range c.endpoints {
+		properties := <-results
+		if properties != nil {
+			return properties
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (c *CloudMetadata) get(url string, headers map[string]string) (string, bool) {
+	return c.request(http.MethodGet, url, headers)
+}
+
+func (c *CloudMetadata) request(method string, url string, headers map[string]string) (string, bool) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return "", false
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", false
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+func (c *CloudMetadata) readAws() map[string]string {
+	baseUrl := c.endpoints["aws"]
+	headers := map[string]string{}
+
+	// Use IMDSv2 token when it is available
+	token, ok := c.request(http.MethodPut, baseUrl+"/api/token",
+		map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+	if ok {
+		headers["X-aws-ec2-metadata-token"] = token
+	}
+
+	instanceId, ok := c.get(baseUrl+"/meta-data/instance-id", headers)
+	if !ok {
+		return nil
+	}
+	zone, _ := c.get(baseUrl+"/meta-data/placement/availability-zone", headers)
+	instanceType, _ := c.get(baseUrl+"/meta-data/instance-type", headers)
+
+	return map[string]string{
+		"cloud.provider":      "aws",
+		"cloud.instance_id":   instanceId,
+		"cloud.zone":          zone,
+		"cloud.instance_type": instanceType,
+	}
+}
+
+func (c *CloudMetadata) readGcp() map[string]string {
+	baseUrl := c.endpoints["gcp"]
+	headers := map[string]string{"Metadata-Flavor": "Google"}
+
+	instanceId, ok := c.get(baseUrl+"/id", headers)
+	if !ok {
+		return nil
+	}
+	zone, _ := c.get(baseUrl+"/zone", headers)
+	machineType, _ := c.get(baseUrl+"/machine-type", headers)
+
+	// Zone and machine type are returned as resource paths
+	return map[string]string{
+		"cloud.provider":      "gcp",
+		"cloud.instance_id":   instanceId,
+		"cloud.zone":          zone[strings.LastIndex(zone, "/")+1:],
+		"cloud.instance_type": machineType[strings.LastIndex(machineType, "/")+1:],
+	}
+}
+
+func (c *CloudMetadata) readAzure() map[string]string {
+	data, ok := c.get(c.endpoints["azure"], map[string]string{"Metadata": "true"})
+	if !ok {
+		return nil
+	}
+
+	var metadata struct {
+		Compute struct {
+			VmId     string `json:"vmId"`
+			Location string `json:"location"`
+			Zone     string `json:"zone"`
+			VmSize   string `json:"vmSize"`
+		} `json:"compute"`
+	}
+	if err := json.Unmarshal([]byte(data), &metadata); err != nil || metadata.Compute.VmId == "" {
+		return nil
+	}
+
+	zone := metadata.Compute.Location
+	if metadata.Compute.Zone != "" {
+		zone = zone + "-" + metadata.Compute.Zone
+	}
+
+	return map[string]string{
+		"cloud.provider":      "azure",
+		"cloud.instance_id":   metadata.Compute.VmId,
+		"cloud.zone":          zone,
+		"cloud.instance_type": metadata.Compute.VmSize,
+	}
+}
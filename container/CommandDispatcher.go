@@ -0,0 +1,297 @@
+package container
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-commons-go/run"
+)
+
+/*
+Maps named commands to components that implement IExecutable interface.
+Commands can be executed in an opened container or from command line using
+"exec <command> --param <key>=<value>" arguments of ProcessContainer.
+
+Each execution can be limited in time. When the timeout is exceeded or the execution is cancelled,
+components that implement IContextExecutable receive the cancelled context, and the dispatcher stops
+waiting for other components and returns an error, so a hung execution can't block the caller forever.
+Executions above the concurrency limit wait in a queue. An abandoned execution keeps its slot until
+the component returns, so hung components can't exceed the concurrency limit.
+
+Configuration parameters
+  - commands:
+    - <name>: descriptor of IExecutable component to run the command
+  - execution:
+    - timeout: time in milliseconds to wait for a single execution (default: 0, wait indefinitely)
+    - max_concurrency: maximum number of executions running at the same time (default: 0, unlimited)
+    - max_queue: maximum number of executions waiting for a free slot (default: 0, unlimited)
+Example
+  - descriptor: "pip-services:container:default:default:1.0"
+    commands:
+      migrate: "mygroup:migrator:default:default:1.0"
+      report: "mygroup:reporter:default:default:1.0"
+    execution:
+      timeout: 600000
+      max_concurrency: 2
+
+see
+Container.ExecuteCommand
+*/
+type CommandDispatcher struct {
+	commands map[string]*crefer.Descriptor
+	timeout  time.Duration
+	slots    chan struct{}
+	maxQueue int
+	queued   int
+	lock     sync.Mutex
+}
+
+// Creates a new instance of the dispatcher.
+// Returns *CommandDispatcher
+func NewCommandDispatcher() *CommandDispatcher {
+	return &CommandDispatcher{
+		commands: map[string]*crefer.Descriptor{},
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *CommandDispatcher) Configure(config *cconfig.ConfigParams) {
+	commands := config.GetSection("commands")
+	for _, name := range commands.Keys() {
+		descriptor, err := crefer.ParseDescriptorFromString(commands.GetAsString(name))
+		if err == nil && descriptor != nil {
+			c.commands[name] = descriptor
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	timeout := config.GetAsLongWithDefault("execution.timeout", c.timeout.Milliseconds())
+	c.timeout = time.Duration(timeout) * time.Millisecond
+	c.maxQueue = config.GetAsIntegerWithDefault("execution.max_queue", c.maxQueue)
+	maxConcurrency := config.GetAsIntegerWithDefault("execution.max_concurrency", cap(c.slots))
+	if maxConcurrency != cap(c.slots) {
+		c.slots = nil
+		if maxConcurrency > 0 {
+			c.slots = make(chan struct{}, maxConcurrency)
+		}
+	}
+}
+
+// Sets limits of command executions.
+// Parameters:
+//   - timeout time.Duration
+//   the time to wait for a single execution or 0 to wait indefinitely.
+//   - maxConcurrency int
+//   the maximum number of executions running at the same time or 0 for unlimited.
+//   - maxQueue int
+//   the maximum number of executions waiting for a free slot or 0 for unlimited.
+func (c *CommandDispatcher) SetLimits(timeout time.Duration, maxConcurrency int, maxQueue int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.timeout = timeout
+	c.maxQueue = maxQueue
+	c.slots = nil
+	if maxConcurrency > 0 {
+		c.slots = make(chan struct{}, maxConcurrency)
+	}
+}
+
+// Registers a command.
+// Parameters:
+//   - name string
+//   a name of the command.
+//   - descriptor *crefer.Descriptor
+//   a descriptor of IExecutable component to run the command.
+func (c *CommandDispatcher) Register(name string, descriptor *crefer.Descriptor) {
+	c.commands[name] = descriptor
+}
+
+// Gets names of all registered commands.
+// Returns []string
+func (c *CommandDispatcher) GetCommandNames() []string {
+	names := []string{}
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Gets descriptor of the component that runs the command.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - name string
+//   a name of the command.
+// Returns *crefer.Descriptor, error
+// the component descriptor and NotFoundError when the command is not registered.
+func (c *CommandDispatcher) GetDescriptor(correlationId string, name string) (*crefer.Descriptor, error) {
+	descriptor, ok := c.commands[name]
+	if !ok {
+		return nil, cerr.NewNotFoundError(
+			correlationId, "COMMAND_NOT_FOUND", "Command "+name+" is not found",
+		).WithDetails("command", name)
+	}
+	return descriptor, nil
+}
+
+// Executes the command.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - references crefer.IReferences
+//   references to locate the component that runs the command.
+//   - name string
+//   a name of the command.
+//   - args *run.Parameters
+//   command parameters.
+// Returns interface{}, error
+// the command result or an error when the command is not found or failed.
+func (c *CommandDispatcher) Execute(correlationId string, references crefer.IReferences,
+	name string, args *run.Parameters) (interface{}, error) {
+	return c.ExecuteWithContext(context.Background(), correlationId, references, name, args)
+}
+
+// Executes the command with the configured timeout and concurrency limit.
+// Parameters:
+//   - ctx context.Context
+//   a context to cancel the execution.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - references crefer.IReferences
+//   references to locate the component that runs the command.
+//   - name string
+//   a name of the command.
+//   - args *run.Parameters
+//   command parameters.
+// Returns interface{}, error
+// the command result or an error when the command is not found, failed, timed out or was cancelled.
+func (c *CommandDispatcher) ExecuteWithContext(ctx context.Context, correlationId string,
+	references crefer.IReferences, name string, args *run.Parameters) (interface{}, error) {
+	descriptor, err := c.GetDescriptor(correlationId, name)
+	if err != nil {
+		return nil, err
+	}
+
+	component, err := references.GetOneRequired(descriptor)
+	if err != nil {
+		return nil, err
+	}
+
+	_, contextual := component.(IContextExecutable)
+	if _, ok := component.(run.IExecutable); !ok && !contextual {
+		return nil, cerr.NewInvalidStateError(
+			correlationId, "NOT_EXECUTABLE", "Component for command "+name+" is not executable",
+		).WithDetails("command", name).WithDetails("descriptor", descriptor.String())
+	}
+
+	release, err := c.acquire(ctx, correlationId, name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	timeout := c.timeout
+	c.lock.Unlock()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result, err := c.execute(ctx, correlationId, component, args, release)
+	if ctx.Err() != nil && (err == nil || err == ctx.Err()) {
+		return nil, c.newInterruptedError(ctx, correlationId, name, timeout)
+	}
+	return result, err
+}
+
+// Waits for a free execution slot. The returned function releases the slot.
+func (c *CommandDispatcher) acquire(ctx context.Context, correlationId string, name string) (func(), error) {
+	c.lock.Lock()
+	slots := c.slots
+	if slots == nil {
+		c.lock.Unlock()
+		return func() {}, nil
+	}
+	if c.maxQueue > 0 && c.queued >= c.maxQueue && len(slots) == cap(slots) {
+		c.lock.Unlock()
+		return nil, cerr.NewConflictError(
+			correlationId, "EXECUTION_QUEUE_FULL", "Too many executions of command "+name+" are waiting",
+		).WithDetails("command", name).WithDetails("max_queue", c.maxQueue)
+	}
+	c.queued++
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		c.queued--
+		c.lock.Unlock()
+	}()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, c.newInterruptedError(ctx, correlationId, name, 0)
+	}
+}
+
+// Runs the component and releases the execution slot when it returns. Components that don't accept
+// a context are executed in a separate goroutine which is abandoned when the context is done.
+func (c *CommandDispatcher) execute(ctx context.Context, correlationId string,
+	component interface{}, args *run.Parameters, release func()) (interface{}, error) {
+	if executable, ok := component.(IContextExecutable); ok {
+		defer release()
+		return executable.ExecuteWithContext(ctx, correlationId, args)
+	}
+
+	executable := component.(run.IExecutable)
+	if ctx.Done() == nil {
+		defer release()
+		return executable.Execute(correlationId, args)
+	}
+
+	type execution struct {
+		result interface{}
+		err    error
+	}
+	done := make(chan execution, 1)
+	go func() {
+		// The slot is held until the component returns, even when nobody waits for it
+		defer release()
+		result, err := executable.Execute(correlationId, args)
+		done <- execution{result: result, err: err}
+	}()
+
+	select {
+	case execution := <-done:
+		return execution.result, execution.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *CommandDispatcher) newInterruptedError(ctx context.Context, correlationId string,
+	name string, timeout time.Duration) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return cerr.NewInternalError(
+			correlationId, "EXECUTION_TIMEOUT", "Command "+name+" was not executed in time",
+		).WithDetails("command", name).WithDetails("timeout", timeout.Milliseconds())
+	}
+	return cerr.NewInternalError(
+		correlationId, "EXECUTION_CANCELLED", "Execution of command "+name+" was cancelled",
+	).WithDetails("command", name)
+}
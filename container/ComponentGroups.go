@@ -0,0 +1,258 @@
+package container
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+/*
+Keeps groups of components that stay dormant until they are needed.
+Components are assigned to a group by "group" parameter in their configuration.
+They are excluded from automatic opening and opened on the first Container.EnsureGroupOpen call.
+When idle timeout is set for the group, it is closed again after that time passes since the last call.
+Components are opened and closed through the runner of the container references (see RunReferencesDecorator).
+
+Configuration parameters
+  - groups:
+    - <name>:
+      - idle_timeout: time in milliseconds after which the group is closed (default: 0, never)
+Example
+  - descriptor: "pip-services:container:default:default:1.0"
+    groups:
+      reporting:
+        idle_timeout: 300000
+
+  - descriptor: "mygroup:report-builder:default:default:1.0"
+    group: reporting
+
+see
+Container.EnsureGroupOpen
+*/
+type ComponentGroups struct {
+	logger       log.ILogger
+	runner       *refer.RunReferencesDecorator
+	idleTimeouts map[string]time.Duration
+	groups       map[string]*componentGroup
+	lock         sync.Mutex
+}
+
+type componentGroup struct {
+	name       string
+	components []interface{}
+	opened     bool
+	timer      *time.Timer
+	generation int
+	// Serializes opening and closing of the group without blocking other groups
+	transition sync.Mutex
+}
+
+// Creates a new instance of component groups.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to trace opening and closing of groups.
+// Returns *ComponentGroups
+func NewComponentGroups(logger log.ILogger) *ComponentGroups {
+	return &ComponentGroups{
+		logger:       logger,
+		idleTimeouts: map[string]time.Duration{},
+		groups:       map[string]*componentGroup{},
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *ComponentGroups) Configure(config *cconfig.ConfigParams) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	groups := config.GetSection("groups")
+	for _, name := range groups.GetSectionNames() {
+		timeout := groups.GetSection(name).GetAsLongWithDefault("idle_timeout", 0)
+		c.idleTimeouts[name] = time.Duration(timeout) * time.Millisecond
+	}
+}
+
+// Sets the logger used to trace opening and closing of groups.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to be set.
+func (c *ComponentGroups) SetLogger(logger log.ILogger) {
+	c.logger = logger
+}
+
+// Sets idle timeout of the group.
+// Parameters:
+//   - name string
+//   a name of the group.
+//   - timeout time.Duration
+//   time after which the group is closed or 0 to keep it opened.
+func (c *ComponentGroups) SetIdleTimeout(name string, timeout time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.idleTimeouts[name] = timeout
+}
+
+// Finds grouped components in the container references and excludes them from automatic opening.
+// Parameters:
+//   - references *refer.ContainerReferences
+//   the container references.
+func (c *ComponentGroups) Register(references *refer.ContainerReferences) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.groups = map[string]*componentGroup{}
+	c.runner = references.Runner
+
+	for _, component := range references.GetAll() {
+		componentConfig := references.GetComponentConfig(component)
+		if componentConfig == nil || componentConfig.Config == nil {
+			continue
+		}
+
+		name := componentConfig.Config.GetAsString("group")
+		if name == "" {
+			continue
+		}
+
+		group, ok := c.groups[name]
+		if !ok {
+			group = &componentGroup{name: name}
+			c.groups[name] = group
+		}
+		group.components = append(group.components, component)
+
+		references.Runner.Exclude(component)
+	}
+}
+
+// Opens the group if it is not opened yet and restarts its idle timeout.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - name string
+//   a name of the group.
+// Returns error
+// NotFoundError when the group is not declared or an error of the component that failed to open.
+func (c *ComponentGroups) EnsureOpen(correlationId string, name string) error {
+	c.lock.Lock()
+	group, ok := c.groups[name]
+	runner := c.runner
+	c.lock.Unlock()
+
+	if !ok {
+		return cerr.NewNotFoundError(
+			correlationId, "GROUP_NOT_FOUND", "Component group "+name+" is not found",
+		).WithDetails("group", name)
+	}
+
+	group.transition.Lock()
+	defer group.transition.Unlock()
+
+	c.lock.Lock()
+	opened := group.opened
+	c.lock.Unlock()
+
+	if !opened {
+		c.logger.Info(correlationId, "Opening component group %s", name)
+
+		for index, component := range group.components {
+			err := runner.OpenComponent(context.Background(), correlationId, component)
+			if err != nil {
+				c.closeComponents(correlationId, runner, group.components[:index])
+				c.logger.Error(correlationId, err, "Failed to open component group %s", name)
+				return err
+			}
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	group.opened = true
+	if timeout := c.idleTimeouts[name]; timeout > 0 {
+		if group.timer != nil {
+			group.timer.Stop()
+		}
+		group.generation++
+		generation := group.generation
+		group.timer = time.AfterFunc(timeout, func() {
+			c.closeIdle(correlationId, runner, group, generation)
+		})
+	}
+
+	return nil
+}
+
+func (c *ComponentGroups) closeIdle(correlationId string, runner *refer.RunReferencesDecorator,
+	group *componentGroup, generation int) {
+	group.transition.Lock()
+	defer group.transition.Unlock()
+
+	// The group was used, closed or replaced since the timer was set
+	c.lock.Lock()
+	idle := group.opened && group.generation == generation && c.groups[group.name] == group
+	if idle {
+		group.opened = false
+		group.timer = nil
+	}
+	c.lock.Unlock()
+	if !idle {
+		return
+	}
+
+	c.logger.Info(correlationId, "Closing idle component group %s", group.name)
+	err := c.closeComponents(correlationId, runner, group.components)
+	if err != nil {
+		c.logger.Error(correlationId, err, "Failed to close component group %s", group.name)
+	}
+}
+
+// Closes components in reverse order, so dependencies are closed last.
+func (c *ComponentGroups) closeComponents(correlationId string, runner *refer.RunReferencesDecorator,
+	components []interface{}) error {
+	var result error
+	for index := len(components) - 1; index >= 0; index-- {
+		err := runner.CloseComponent(context.Background(), correlationId, components[index])
+		if err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// Checks if the group is opened.
+// Parameters:
+//   - name string
+//   a name of the group.
+// Returns bool
+func (c *ComponentGroups) IsOpen(name string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	group, ok := c.groups[name]
+	return ok && group.opened
+}
+
+// Stops idle timeouts of all groups. Components of opened groups
+// are closed together with the rest of the container.
+func (c *ComponentGroups) Stop() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, group := range c.groups {
+		if group.timer != nil {
+			group.timer.Stop()
+			group.timer = nil
+		}
+		group.opened = false
+	}
+}
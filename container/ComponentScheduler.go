@@ -0,0 +1,224 @@
+package container
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+/*
+Opens and closes components according to their schedules while the container stays up.
+Schedules are defined in "schedule" section of the component configuration.
+Components are opened and closed through the runner of the container references (see RunReferencesDecorator).
+
+Configuration parameters
+  - schedule:
+    - open: cron expression when the component shall be opened
+    - close: cron expression when the component shall be closed
+Example
+  - descriptor: "mygroup:exporter:default:default:1.0"
+    schedule:
+      open: "0 22 * * *"
+      close: "0 6 * * *"
+
+see
+CronExpression
+*/
+type ComponentScheduler struct {
+	logger  log.ILogger
+	runner  *refer.RunReferencesDecorator
+	entries []*scheduledComponent
+	lock    sync.Mutex
+	stop    chan bool
+}
+
+type scheduledComponent struct {
+	locator   interface{}
+	component interface{}
+	open      *CronExpression
+	close     *CronExpression
+	opened    bool
+}
+
+const scheduleLookback = 7 * 24 * time.Hour
+
+// Creates a new instance of the scheduler.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to trace scheduled transitions.
+// Returns *ComponentScheduler
+func NewComponentScheduler(logger log.ILogger) *ComponentScheduler {
+	return &ComponentScheduler{
+		logger:  logger,
+		entries: []*scheduledComponent{},
+	}
+}
+
+// Finds components with schedules in the container references and excludes
+// components that are outside of their windows from automatic opening.
+// Parameters:
+//   - references *refer.ContainerReferences
+//   the container references.
+// Returns error
+// ConfigError when schedule expressions are invalid.
+func (c *ComponentScheduler) Register(references *refer.ContainerReferences) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.runner = references.Runner
+	c.entries = []*scheduledComponent{}
+
+	now := time.Now()
+	locators := references.GetAllLocators()
+
+	for index, component := range references.GetAll() {
+		componentConfig := references.GetComponentConfig(component)
+		if componentConfig == nil || componentConfig.Config == nil {
+			continue
+		}
+
+		schedule := componentConfig.Config.GetSection("schedule")
+		openExpr := schedule.GetAsString("open")
+		closeExpr := schedule.GetAsString("close")
+		if openExpr == "" && closeExpr == "" {
+			continue
+		}
+
+		entry := &scheduledComponent{component: component}
+		if index < len(locators) {
+			entry.locator = locators[index]
+		}
+
+		var err error
+		if openExpr != "" {
+			if entry.open, err = ParseCronExpression(openExpr); err != nil {
+				return err
+			}
+		}
+		if closeExpr != "" {
+			if entry.close, err = ParseCronExpression(closeExpr); err != nil {
+				return err
+			}
+		}
+
+		entry.opened = entry.isInWindow(now)
+		if !entry.opened {
+			references.Runner.Exclude(component)
+		}
+
+		c.entries = append(c.entries, entry)
+	}
+
+	return nil
+}
+
+func (c *scheduledComponent) isInWindow(now time.Time) bool {
+	if c.open == nil {
+		return true
+	}
+
+	lastOpen, opened := c.open.Previous(now, scheduleLookback)
+	if !opened {
+		return false
+	}
+	if c.close == nil {
+		return true
+	}
+
+	lastClose, closed := c.close.Previous(now, scheduleLookback)
+	return !closed || lastOpen.After(lastClose)
+}
+
+// Starts checking schedules every minute.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+func (c *ComponentScheduler) Start(correlationId string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.stop != nil || len(c.entries) == 0 {
+		return
+	}
+
+	c.stop = make(chan bool)
+	go c.run(correlationId, c.stop)
+}
+
+func (c *ComponentScheduler) run(correlationId string, stop chan bool) {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	lastMinute := time.Now().Truncate(time.Minute)
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			minute := now.Truncate(time.Minute)
+			if minute.After(lastMinute) {
+				lastMinute = minute
+				c.check(correlationId, minute)
+			}
+		}
+	}
+}
+
+func (c *ComponentScheduler) check(correlationId string, now time.Time) {
+	// Collect due transitions under the lock, so slow components do not block the scheduler
+	c.lock.Lock()
+	runner := c.runner
+	opening := []*scheduledComponent{}
+	closing := []*scheduledComponent{}
+	for _, entry := range c.entries {
+		if !entry.opened && entry.open != nil && entry.open.Match(now) {
+			opening = append(opening, entry)
+		} else if entry.opened && entry.close != nil && entry.close.Match(now) {
+			closing = append(closing, entry)
+		}
+	}
+	c.lock.Unlock()
+
+	for _, entry := range opening {
+		name := cconv.StringConverter.ToString(entry.locator)
+		err := runner.OpenComponent(context.Background(), correlationId, entry.component)
+		if err != nil {
+			c.logger.Error(correlationId, err, "Failed to open scheduled component %s", name)
+			continue
+		}
+		c.setOpened(entry, true)
+		c.logger.Info(correlationId, "Opened scheduled component %s", name)
+	}
+
+	for _, entry := range closing {
+		name := cconv.StringConverter.ToString(entry.locator)
+		err := runner.CloseComponent(context.Background(), correlationId, entry.component)
+		if err != nil {
+			c.logger.Error(correlationId, err, "Failed to close scheduled component %s", name)
+		}
+		c.setOpened(entry, false)
+		c.logger.Info(correlationId, "Closed scheduled component %s", name)
+	}
+}
+
+func (c *ComponentScheduler) setOpened(entry *scheduledComponent, opened bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry.opened = opened
+}
+
+// Stops checking schedules. Components are not closed, that is done by the container.
+func (c *ComponentScheduler) Stop() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+}
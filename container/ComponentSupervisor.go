@@ -0,0 +1,319 @@
+package container
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+/*
+Restarts container components and protects the container from crash-looping components.
+
+When a component is restarted more than max_restarts times within the window it is quarantined:
+further restarts are refused, the container is marked as degraded and an error is logged.
+After the underlying issue is fixed the component can be released with Unquarantine.
+
+To prevent restart storms when many components fail at once (e.g. network partition) restarts are
+rate-limited container-wide by a global budget. Restarts above the budget wait until it frees up.
+Concurrent restart requests for the same component are coalesced into a single restart.
+
+Components are closed and opened through the runner of the container references (see RunReferencesDecorator),
+so lifecycle listeners, timeouts, retry policies, environments and shared components are respected.
+
+Configuration parameters
+  - restarts:
+    - max_restarts: maximum number of restarts within the window (default: 5)
+    - window: time window in milliseconds (default: 600000)
+    - budget: maximum number of restarts of all components within the budget window, 0 to disable (default: 10)
+    - budget_window: budget time window in milliseconds (default: 1000)
+
+see
+Container.RestartComponent
+*/
+type ComponentSupervisor struct {
+	logger      log.ILogger
+	runner      *refer.RunReferencesDecorator
+	maxRestarts int
+	window      time.Duration
+	restarts    map[string][]time.Time
+	quarantined map[string]time.Time
+	budget      int
+	budgetWin   time.Duration
+	budgetUsed  []time.Time
+	inflight    map[string]*pendingRestart
+	lock        sync.Mutex
+}
+
+type pendingRestart struct {
+	done chan struct{}
+	err  error
+}
+
+// Creates a new instance of the supervisor.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to report restarts and quarantines.
+// Returns *ComponentSupervisor
+func NewComponentSupervisor(logger log.ILogger) *ComponentSupervisor {
+	return &ComponentSupervisor{
+		logger:      logger,
+		runner:      refer.NewRunReferencesDecorator(crefer.NewEmptyReferences(), nil),
+		maxRestarts: 5,
+		window:      10 * time.Minute,
+		restarts:    map[string][]time.Time{},
+		quarantined: map[string]time.Time{},
+		budget:      10,
+		budgetWin:   time.Second,
+		budgetUsed:  []time.Time{},
+		inflight:    map[string]*pendingRestart{},
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *ComponentSupervisor) Configure(config *cconfig.ConfigParams) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.maxRestarts = config.GetAsIntegerWithDefault("restarts.max_restarts", c.maxRestarts)
+	window := config.GetAsLongWithDefault("restarts.window", int64(c.window/time.Millisecond))
+	c.window = time.Duration(window) * time.Millisecond
+	c.budget = config.GetAsIntegerWithDefault("restarts.budget", c.budget)
+	budgetWindow := config.GetAsLongWithDefault("restarts.budget_window", int64(c.budgetWin/time.Millisecond))
+	c.budgetWin = time.Duration(budgetWindow) * time.Millisecond
+}
+
+// Sets the logger used to report restarts and quarantines.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to be set.
+func (c *ComponentSupervisor) SetLogger(logger log.ILogger) {
+	c.logger = logger
+}
+
+// Sets the runner used to close and open restarted components.
+// Parameters:
+//   - runner *refer.RunReferencesDecorator
+//   a runner of the container references.
+func (c *ComponentSupervisor) SetRunner(runner *refer.RunReferencesDecorator) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.runner = runner
+}
+
+// Sets restart limits for components.
+// Parameters:
+//   - maxRestarts int
+//   maximum number of restarts within the window.
+//   - window time.Duration
+//   a time window to count restarts.
+func (c *ComponentSupervisor) SetRestartLimits(maxRestarts int, window time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.maxRestarts = maxRestarts
+	c.window = window
+}
+
+// Sets the container-wide restart budget.
+// Parameters:
+//   - budget int
+//   maximum number of restarts of all components within the window, 0 to disable.
+//   - window time.Duration
+//   a time window to count restarts.
+func (c *ComponentSupervisor) SetRestartBudget(budget int, window time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.budget = budget
+	c.budgetWin = window
+}
+
+// Restarts the component by closing and opening it again.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - name string
+//   a name (locator) of the component used to account restarts.
+//   - component interface{}
+//   the component to restart.
+// Returns error
+// InvalidStateError when the component is quarantined or an error returned by the component.
+func (c *ComponentSupervisor) Restart(correlationId string, name string, component interface{}) error {
+	// Coalesce with the restart that is already in progress
+	c.lock.Lock()
+	if pending, ok := c.inflight[name]; ok {
+		c.lock.Unlock()
+		<-pending.done
+		return pending.err
+	}
+	pending := &pendingRestart{done: make(chan struct{})}
+	c.inflight[name] = pending
+	c.lock.Unlock()
+
+	pending.err = c.restart(correlationId, name, component)
+
+	c.lock.Lock()
+	delete(c.inflight, name)
+	c.lock.Unlock()
+	close(pending.done)
+
+	return pending.err
+}
+
+func (c *ComponentSupervisor) restart(correlationId string, name string, component interface{}) error {
+	if err := c.registerRestart(correlationId, name); err != nil {
+		return err
+	}
+
+	c.acquireBudget(correlationId, name)
+
+	c.logger.Info(correlationId, "Restarting component %s", name)
+
+	c.lock.Lock()
+	runner := c.runner
+	c.lock.Unlock()
+
+	err := runner.CloseComponent(context.Background(), correlationId, component)
+	if err != nil {
+		c.logger.Warn(correlationId, "Failed to close component %s before restart: %v", name, err)
+	}
+
+	err = runner.OpenComponent(context.Background(), correlationId, component)
+	if err != nil {
+		c.logger.Error(correlationId, err, "Failed to restart component %s", name)
+	}
+	return err
+}
+
+func (c *ComponentSupervisor) registerRestart(correlationId string, name string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.quarantined[name]; ok {
+		return cerr.NewInvalidStateError(
+			correlationId, "COMPONENT_QUARANTINED", "Component "+name+" is quarantined",
+		).WithDetails("descriptor", name)
+	}
+
+	now := time.Now()
+	restarts := []time.Time{}
+	for _, restart := range c.restarts[name] {
+		if now.Sub(restart) < c.window {
+			restarts = append(restarts, restart)
+		}
+	}
+	restarts = append(restarts, now)
+	c.restarts[name] = restarts
+
+	if c.maxRestarts > 0 && len(restarts) > c.maxRestarts {
+		c.quarantined[name] = now
+		delete(c.restarts, name)
+
+		err := cerr.NewInvalidStateError(
+			correlationId, "COMPONENT_QUARANTINED",
+			"Component "+name+" was restarted too many times and was quarantined",
+		).WithDetails("descriptor", name).WithDetails("restarts", len(restarts))
+		c.logger.Error(correlationId, err, "Component %s is quarantined", name)
+		return err
+	}
+
+	return nil
+}
+
+// Waits until the container-wide restart budget allows one more restart.
+func (c *ComponentSupervisor) acquireBudget(correlationId string, name string) {
+	for {
+		c.lock.Lock()
+		if c.budget <= 0 {
+			c.lock.Unlock()
+			return
+		}
+
+		now := time.Now()
+		used := []time.Time{}
+		for _, restart := range c.budgetUsed {
+			if now.Sub(restart) < c.budgetWin {
+				used = append(used, restart)
+			}
+		}
+		c.budgetUsed = used
+
+		if len(used) < c.budget {
+			c.budgetUsed = append(c.budgetUsed, now)
+			c.lock.Unlock()
+			return
+		}
+
+		delay := c.budgetWin - now.Sub(used[0])
+		c.lock.Unlock()
+
+		c.logger.Debug(correlationId, "Restart budget is exhausted, delaying restart of %s for %v", name, delay)
+		time.Sleep(delay)
+	}
+}
+
+// Releases the component from quarantine so it can be restarted again.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - name string
+//   a name (locator) of the component.
+// Returns bool
+// true if the component was quarantined and false otherwise.
+func (c *ComponentSupervisor) Unquarantine(correlationId string, name string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.quarantined[name]; !ok {
+		return false
+	}
+
+	delete(c.quarantined, name)
+	c.logger.Info(correlationId, "Component %s is released from quarantine", name)
+	return true
+}
+
+// Checks if the component is quarantined.
+// Parameters:
+//   - name string
+//   a name (locator) of the component.
+// Returns bool
+func (c *ComponentSupervisor) IsQuarantined(name string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, ok := c.quarantined[name]
+	return ok
+}
+
+// Gets names of all quarantined components.
+// Returns []string
+func (c *ComponentSupervisor) GetQuarantined() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	result := []string{}
+	for name := range c.quarantined {
+		result = append(result, name)
+	}
+	return result
+}
+
+// Checks if the container is degraded because some of its components are quarantined.
+// Returns bool
+func (c *ComponentSupervisor) IsDegraded() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.quarantined) > 0
+}
@@ -0,0 +1,130 @@
+package container
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/info"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+// Configuration file that failed to build in a dry run.
+type DryRunFailure struct {
+	Path  string
+	Error error
+}
+
+// Builds the container configuration without opening it: reads and validates the file,
+// selects components of active profiles, checks listeners and schemas, and creates
+// and configures all components with registered factories. Created components are discarded.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - path string
+//   a path to the configuration file.
+//   - parameters *cconfig.ConfigParams
+//   values to parameterize the configuration or nil to skip parameterization.
+// Returns error
+// the error that would fail opening of the container with the configuration.
+func (c *Container) DryRunConfig(correlationId string, path string, parameters *cconfig.ConfigParams) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = cerr.NewUnknownError(correlationId, "DRY_RUN_FAILED", "Building configuration panicked").
+				WithDetails("path", path).WithDetails("panic", r)
+		}
+	}()
+
+	containerConfig, err := config.ContainerConfigReader.ReadFromFile(correlationId, path, parameters)
+	if err != nil {
+		return err
+	}
+	err = containerConfig.Validate(correlationId)
+	if err != nil {
+		return err
+	}
+
+	options, components := containerConfig.ExtractOptions()
+	components, err = c.selectComponents(correlationId, options, components)
+	if err != nil {
+		return err
+	}
+	err = components.CheckListeners(correlationId)
+	if err != nil {
+		return err
+	}
+	err = c.validateSchemas(correlationId, components)
+	if err != nil {
+		return err
+	}
+
+	references := refer.NewContainerReferences()
+	references.SetLogger(c.logger)
+	references.SetLenient(options.GetAsBooleanWithDefault("lenient", c.lenient))
+	references.Put(crefer.NewDescriptor("pip-services", "context-info", "default", "default", "1.0"),
+		info.NewContextInfo())
+	references.Put(crefer.NewDescriptor("pip-services", "factory", "container", "default", "1.0"), c.factories)
+
+	// Release created components, so shared components are not held by the dry run
+	defer func() {
+		for _, component := range references.GetAll() {
+			references.RemoveComponent(component)
+		}
+	}()
+
+	err = references.PutFromConfig(components)
+	if err != nil {
+		return err
+	}
+	return references.ValidateDependencies(correlationId)
+}
+
+// Builds all configuration files in the directory without opening them (see DryRunConfig),
+// so per-tenant or per-deployment configurations can be validated in one test.
+// Files are checked in lexical order, files in unknown formats and subdirectories are skipped.
+//
+// Example
+//   failures, err := c.DryRunConfigDir("123", "./config/tenants", nil)
+//   assert.Nil(t, err)
+//   for _, failure := range failures {
+//       t.Errorf("%s: %v", failure.Path, failure.Error)
+//   }
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - dir string
+//   a path to the directory with configuration files.
+//   - parameters *cconfig.ConfigParams
+//   values to parameterize configurations or nil to skip parameterization.
+// Returns []*DryRunFailure, error
+// configurations that failed to build and FileError when the directory can't be read.
+func (c *Container) DryRunConfigDir(correlationId string, dir string,
+	parameters *cconfig.ConfigParams) ([]*DryRunFailure, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, cerr.NewFileError(
+			correlationId, "READ_FAILED", "Failed reading configuration directory "+dir+": "+err.Error(),
+		).WithDetails("path", dir).WithCause(err)
+	}
+
+	paths := []string{}
+	for _, file := range files {
+		if file.IsDir() || config.ContainerConfigReader.GetFormatReader(filepath.Ext(file.Name())) == nil {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, file.Name()))
+	}
+	sort.Strings(paths)
+
+	failures := []*DryRunFailure{}
+	for _, path := range paths {
+		if err := c.DryRunConfig(correlationId, path, parameters); err != nil {
+			failures = append(failures, &DryRunFailure{Path: path, Error: err})
+		}
+	}
+	return failures, nil
+}
@@ -0,0 +1,227 @@
+package container
+
+import (
+	"sort"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cbuild "github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+// Severity of lint findings.
+type LintSeverity string
+
+const (
+	// The configuration is broken and the container will fail to open.
+	LintError LintSeverity = "error"
+	// The configuration works but likely contains a mistake.
+	LintWarning LintSeverity = "warning"
+	// The configuration can be improved.
+	LintInfo LintSeverity = "info"
+)
+
+var lintSeverityOrder = map[LintSeverity]int{LintError: 0, LintWarning: 1, LintInfo: 2}
+
+/*
+Problem found in container configuration by a lint rule.
+*/
+type LintFinding struct {
+	Rule      string       `json:"rule"`
+	Severity  LintSeverity `json:"severity"`
+	Component string       `json:"component,omitempty"`
+	Message   string       `json:"message"`
+}
+
+// Gets the finding in "<severity> [<rule>] <component>: <message>" format.
+// Returns string
+func (c *LintFinding) String() string {
+	result := string(c.Severity) + " [" + c.Rule + "]"
+	if c.Component != "" {
+		result += " " + c.Component
+	}
+	return result + ": " + c.Message
+}
+
+/*
+Configuration checked by lint rules.
+*/
+type LintContext struct {
+	// Configuration template as written in the file
+	Template string
+	// Components of the configuration parameterized with Parameters, without container options
+	Config config.ContainerConfig
+	// Options of the container itself
+	Options *cconfig.ConfigParams
+	// Parameters passed to the configuration explicitly
+	Parameters *cconfig.ConfigParams
+	// Factory used by the container to create components
+	Factory cbuild.IFactory
+	// Providers of configuration values referenced by schemes
+	ValueProviders *config.ConfigValueProviders
+}
+
+/*
+Interface for rules that check container configurations.
+
+see
+ConfigLinter
+*/
+type ILintRule interface {
+	// Gets the rule name used in findings, for instance "duplicate-descriptor".
+	Name() string
+
+	// Checks the configuration.
+	// Parameters:
+	//   - context *LintContext
+	//   the configuration to check.
+	// Returns []*LintFinding
+	// found problems or empty list.
+	Check(context *LintContext) []*LintFinding
+}
+
+/*
+Checks container configurations with built-in and custom rules and produces findings
+suitable for CI: each finding has a severity, and the check fails when errors are found.
+
+Built-in rules
+  - unknown-descriptor: no registered factory can create the component (error)
+  - duplicate-descriptor: several components have the same descriptor (warning)
+  - unused-parameter: a parameter is passed but not referenced in the configuration (warning)
+  - missing-logger: the configuration has no logger component (warning)
+  - plaintext-secret: a password, token or key is written in the configuration instead of a parameter
+    or a value provider reference (error)
+
+see
+Container.LintConfig
+*/
+type ConfigLinter struct {
+	rules []ILintRule
+}
+
+// Creates a new instance of the linter with built-in rules.
+// Returns *ConfigLinter
+func NewConfigLinter() *ConfigLinter {
+	return &ConfigLinter{
+		rules: []ILintRule{
+			&unknownDescriptorRule{},
+			&duplicateDescriptorRule{},
+			&unusedParameterRule{},
+			&missingLoggerRule{},
+			&plaintextSecretRule{},
+		},
+	}
+}
+
+// Adds a custom rule. A rule with the same name as a registered one replaces it.
+// Parameters:
+//   - rule ILintRule
+//   a rule to add.
+func (c *ConfigLinter) AddRule(rule ILintRule) {
+	for index, existing := range c.rules {
+		if existing.Name() == rule.Name() {
+			c.rules[index] = rule
+			return
+		}
+	}
+	c.rules = append(c.rules, rule)
+}
+
+// Gets names of registered rules.
+// Returns []string
+func (c *ConfigLinter) GetRuleNames() []string {
+	names := []string{}
+	for _, rule := range c.rules {
+		names = append(names, rule.Name())
+	}
+	return names
+}
+
+// Checks the configuration with all registered rules.
+// Parameters:
+//   - context *LintContext
+//   the configuration to check.
+// Returns []*LintFinding
+// findings sorted by severity, errors first.
+func (c *ConfigLinter) Lint(context *LintContext) []*LintFinding {
+	result := []*LintFinding{}
+	for _, rule := range c.rules {
+		for _, finding := range rule.Check(context) {
+			if finding.Rule == "" {
+				finding.Rule = rule.Name()
+			}
+			result = append(result, finding)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return lintSeverityOrder[result[i].Severity] < lintSeverityOrder[result[j].Severity]
+	})
+	return result
+}
+
+// Checks if findings contain errors.
+// Parameters:
+//   - findings []*LintFinding
+//   findings to check.
+// Returns bool
+func HasLintErrors(findings []*LintFinding) bool {
+	for _, finding := range findings {
+		if finding.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
+
+// Adds a custom rule to check configurations with LintConfig.
+// A rule with the same name as a registered one replaces it.
+// Parameters:
+//   - rule ILintRule
+//   a rule to add.
+func (c *Container) AddLintRule(rule ILintRule) {
+	c.linter.AddRule(rule)
+}
+
+// Checks the configuration file with built-in and custom lint rules without creating components.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - path string
+//   a path to configuration file.
+//   - parameters *cconfig.ConfigParams
+//   values passed explicitly to parameterize the configuration or nil when there are none.
+// Returns []*LintFinding, error
+// found problems sorted by severity and error when the configuration cannot be read.
+func (c *Container) LintConfig(correlationId string, path string,
+	parameters *cconfig.ConfigParams) ([]*LintFinding, error) {
+	template, err := config.ContainerConfigReader.ReadTemplateFromFile(correlationId, path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := parameters
+	if values != nil {
+		values = values.SetDefaults(ResolveHostInfo().GetParameters())
+	}
+	var containerConfig config.ContainerConfig
+	if config.IsStdinPath(path) {
+		// The standard input can be read only once
+		containerConfig, err = config.ContainerConfigReader.ReadFromBytes(correlationId, []byte(template), ".yml", values)
+	} else {
+		containerConfig, err = config.ContainerConfigReader.ReadFromFile(correlationId, path, values)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	options, components := containerConfig.ExtractOptions()
+	context := &LintContext{
+		Template:       template,
+		Config:         components,
+		Options:        options,
+		Parameters:     parameters,
+		Factory:        c.factories,
+		ValueProviders: c.valueProviders,
+	}
+	return c.linter.Lint(context), nil
+}
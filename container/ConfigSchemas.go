@@ -0,0 +1,77 @@
+package container
+
+import (
+	"strings"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+/*
+Interface for factories that describe configurations of components they create.
+The container validates configurations of all components against their schemas
+before it creates any component.
+
+see
+config.ConfigSchema
+*/
+type IConfigSchemaProvider interface {
+	// Gets the configuration schema of the component created by the locator.
+	// Parameters:
+	//   - locator interface{}
+	//   a locator of the component.
+	// Returns *config.ConfigSchema
+	// the schema or nil when the factory doesn't describe the component.
+	GetConfigSchema(locator interface{}) *config.ConfigSchema
+}
+
+// Validates configurations of components against schemas provided by registered factories
+// that implement IConfigSchemaProvider. Components without schemas are not validated.
+// Parameters:
+//   - containerConfig config.ContainerConfig
+//   the configuration to validate.
+// Returns []*config.SchemaViolation
+// all found violations or empty list when configurations are valid.
+func (c *Container) GetSchemaViolations(containerConfig config.ContainerConfig) []*config.SchemaViolation {
+	result := []*config.SchemaViolation{}
+
+	providers := []IConfigSchemaProvider{}
+	for _, factory := range c.factoryList {
+		if provider, ok := factory.(IConfigSchemaProvider); ok {
+			providers = append(providers, provider)
+		}
+	}
+	if len(providers) == 0 {
+		return result
+	}
+
+	for _, componentConfig := range containerConfig {
+		if componentConfig.Descriptor == nil {
+			continue
+		}
+		for _, provider := range providers {
+			if schema := provider.GetConfigSchema(componentConfig.Descriptor); schema != nil {
+				result = append(result, schema.Validate(componentConfig)...)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// Validates configurations of components against their schemas and returns all violations in one error.
+func (c *Container) validateSchemas(correlationId string, containerConfig config.ContainerConfig) error {
+	violations := c.GetSchemaViolations(containerConfig)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := []string{}
+	for _, violation := range violations {
+		messages = append(messages, violation.String())
+	}
+	return cerr.NewConfigError(
+		correlationId, "SCHEMA_VIOLATION", "Configuration violates component schemas: "+strings.Join(messages, "; "),
+	).WithDetails("violations", violations)
+}
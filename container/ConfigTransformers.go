@@ -0,0 +1,62 @@
+package container
+
+import (
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+// Function that transforms the container configuration before components are created,
+// for instance to inject, rewrite or prune components.
+// It receives the configuration without container options, filtered by active profiles,
+// with test overrides applied and values of providers not resolved yet.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - containerConfig config.ContainerConfig
+//   the configuration to be transformed. It shall not be modified in place.
+// Returns config.ContainerConfig, error
+// the transformed configuration and error to stop opening or reloading the container.
+type ConfigTransformer func(correlationId string, containerConfig config.ContainerConfig) (config.ContainerConfig, error)
+
+// Adds the configuration transformer. Transformers are called in the order they were added
+// each time the container opens or reloads configuration.
+// Parameters:
+//   - transformer ConfigTransformer
+//   a transformer to be added.
+//
+// Example
+//   container.AddConfigTransformer(func(correlationId string, containerConfig config.ContainerConfig) (config.ContainerConfig, error) {
+//       _, result := containerConfig.SplitByTypes("debug")
+//       return result, nil
+//   })
+func (c *Container) AddConfigTransformer(transformer ConfigTransformer) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.transformers = append(c.transformers, transformer)
+}
+
+// Applies configuration transformers and validates the result.
+func (c *Container) transformConfig(correlationId string,
+	containerConfig config.ContainerConfig) (config.ContainerConfig, error) {
+	c.lock.Lock()
+	transformers := c.transformers
+	c.lock.Unlock()
+
+	if len(transformers) == 0 {
+		return containerConfig, nil
+	}
+
+	for _, transformer := range transformers {
+		transformed, err := transformer(correlationId, containerConfig)
+		if err != nil {
+			return nil, err
+		}
+		containerConfig = transformed
+	}
+
+	if err := containerConfig.Validate(correlationId); err != nil {
+		return nil, err
+	}
+	c.logger.Debug(correlationId, "Applied %d configuration transformers", len(transformers))
+	return containerConfig, nil
+}
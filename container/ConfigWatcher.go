@@ -0,0 +1,248 @@
+package container
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+/*
+Watches the container configuration file or directory and triggers reload when it changes.
+The file is polled by its modification time and size, so it works on any file system
+including mounted config maps. Configurations read from Consul KV store by consul:// URI
+and from etcd by etcd:// URI are watched with blocking queries and watch streams,
+so changes are applied as soon as the key is updated.
+
+Configuration parameters
+  - config_watch:
+    - enabled: true to reload configuration when the file changes (default: false)
+    - interval: polling interval in milliseconds, or delay before retry when Consul is not available (default: 1000)
+
+see
+Container.ReloadConfig
+*/
+type ConfigWatcher struct {
+	enabled  bool
+	interval time.Duration
+	overlay  string
+	stop     chan struct{}
+	lock     sync.Mutex
+}
+
+// Creates a new instance of configuration watcher.
+// Returns *ConfigWatcher
+func NewConfigWatcher() *ConfigWatcher {
+	return &ConfigWatcher{
+		enabled:  false,
+		interval: time.Second,
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *ConfigWatcher) Configure(config *cconfig.ConfigParams) {
+	c.enabled = config.GetAsBooleanWithDefault("config_watch.enabled", c.enabled)
+	interval := config.GetAsLongWithDefault("config_watch.interval", int64(c.interval/time.Millisecond))
+	c.interval = time.Duration(interval) * time.Millisecond
+}
+
+// Checks if watching of the configuration file is enabled.
+// Returns bool
+func (c *ConfigWatcher) IsEnabled() bool {
+	return c.enabled
+}
+
+// Sets a path to the environment overlay file watched together with the configuration file.
+// Parameters:
+//   - path string
+//   a path to the overlay file or empty string when there is none.
+func (c *ConfigWatcher) SetOverlayPath(path string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.overlay = path
+}
+
+// Starts watching the file. Previous watch is stopped.
+// Parameters:
+//   - path string
+//   a path to the configuration file.
+//   - changed func()
+//   a function called when the file changes.
+func (c *ConfigWatcher) Start(path string, changed func()) {
+	c.Stop()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stop := make(chan struct{})
+	c.stop = stop
+	interval := c.interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	if config.IsConsulUri(path) {
+		go c.watchConsul(path, interval, stop, changed)
+		return
+	}
+
+	if config.IsEtcdUri(path) {
+		go c.watchEtcd(path, interval, stop, changed)
+		return
+	}
+
+	overlay := c.overlay
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := configVersion(path, overlay)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				version := configVersion(path, overlay)
+				if version != "" && version != last {
+					last = version
+					changed()
+				}
+			}
+		}
+	}()
+}
+
+// Watches Consul key with blocking queries until the watch is stopped.
+func (c *ConfigWatcher) watchConsul(uri string, interval time.Duration, stop chan struct{}, changed func()) {
+	key, options, err := config.ParseConsulUri(uri)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var last uint64
+	for {
+		_, index, err := config.ReadConsulKey(ctx, "", key, options, last)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil && last != 0 && index != last {
+			changed()
+		}
+
+		// Back off when Consul is not available or doesn't support blocking queries
+		if err != nil || index == 0 {
+			select {
+			case <-stop:
+				return
+			case <-time.After(interval):
+			}
+			continue
+		}
+		last = index
+	}
+}
+
+// Watches etcd key with watch streams until the watch is stopped.
+func (c *ConfigWatcher) watchEtcd(uri string, interval time.Duration, stop chan struct{}, changed func()) {
+	endpoints, key, options, err := config.ParseEtcdUri(uri)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		err := config.WatchEtcdKey(ctx, "", endpoints, key, options)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			changed()
+			continue
+		}
+
+		// Back off when etcd is not available
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Stops watching the file.
+func (c *ConfigWatcher) Stop() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+}
+
+// Gets a version of the configuration file and its overlay. A missing overlay doesn't
+// make the version empty, since overlays are optional.
+func configVersion(path string, overlay string) string {
+	version := fileVersion(path)
+	if version == "" || overlay == "" {
+		return version
+	}
+	return version + ";" + overlay + "@" + fileVersion(overlay)
+}
+
+// Gets a version of the file from its modification time and size
+// or empty string when the file is not accessible, for instance while it is being replaced.
+// A version of a configuration directory combines versions of its files, so adding,
+// removing or changing any of them is detected.
+func fileVersion(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	if !info.IsDir() {
+		return info.ModTime().String() + "/" + strconv.FormatInt(info.Size(), 10)
+	}
+
+	paths, err := config.ContainerConfigReader.ListConfigFiles("", path)
+	if err != nil {
+		return ""
+	}
+	versions := []string{}
+	for _, file := range paths {
+		version := fileVersion(file)
+		if version == "" {
+			return ""
+		}
+		versions = append(versions, file+"@"+version)
+	}
+	return strings.Join(versions, ";")
+}
@@ -1,12 +1,21 @@
 package container
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 
 	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
 	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
 	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
 	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-commons-go/run"
 	cbuild "github.com/pip-services3-go/pip-services3-components-go/build"
 	"github.com/pip-services3-go/pip-services3-components-go/info"
 	"github.com/pip-services3-go/pip-services3-components-go/log"
@@ -25,6 +34,7 @@ On container start it performs the following actions:
 Creates components using their types or calls registered factories to create components using their locators
 Configures components that implement IConfigurable interface and passes them their configuration parameters
 Sets references to components that implement IReferenceable interface and passes them references of all components in the container
+Opens and runs components that implement IMigration interface one by one
 Opens components that implement IOpenable interface
 On container stop actions are performed in reversed order:
 
@@ -35,6 +45,15 @@ The component configuration can be parameterized by dynamic values. That allows
 
 The container automatically creates a ContextInfo component that carries detail information about the container and makes it available for other components.
 
+Components that implement INotifiable interface are notified about container events
+(opened, degraded, reloaded, closing, alert, alert_resolved) with the event name in "event" parameter.
+Applications can receive the same events together with opening, failed and closed
+state transitions over a channel registered by Subscribe, or receive transitions
+of the container and every component by listeners registered by AddLifecycleListener.
+
+Failures to open and close components are counted in "container.open_failures.<category>" and
+"container.close_failures.<category>" counters, with and without the component descriptor appended.
+
 see
 IConfigurable (in the PipServices "Commons" package)
 
@@ -49,6 +68,52 @@ name: the context (container or process) name
 description: human-readable description of the context
 properties: entire section of additional descriptive properties
  - ...
+
+Options of the container itself are defined in a section with "pip-services:container:default:default:1.0" descriptor
+restarts: restart limits of components (see ComponentSupervisor)
+markers: lifecycle marker files (see LifecycleMarkers)
+cloud_metadata: cloud instance metadata in context properties (see CloudMetadata)
+groups: component groups opened on demand (see ComponentGroups)
+state: store to persist state of IStateful components across restarts (see StateStore)
+commands: named commands executed by IExecutable components (see CommandDispatcher)
+execution: timeout, concurrency and queue limits of command executions (see CommandDispatcher)
+versions: checks of library versions (see VersionChecker)
+active_profiles, profiles: profiles to select components (see GetActiveProfiles in config package)
+correlation_ids:
+  - strategy: correlation ids of container operations: static, uuid or traceparent (see ICorrelationIdStrategy)
+strict_config: "warn" to log or "fail" to stop opening on configuration keys that components
+  don't consume, like mistyped "conection.host" (default: off, see IConfigKeysProvider)
+lenient: true to skip all components that no registered factory can create, like components
+  marked with "optional" parameter, instead of failing to open (default: false)
+fail_fast: stops opening when a component fails, false to skip failing components that are not marked
+  as "critical", record their errors and run in degraded mode (default: true, see GetOpenFailures)
+open:
+  - concurrency: maximum number of components opened in parallel respecting their declared
+    dependencies ("depends_on" and "dependencies"), 1 to open components one by one (default: 1)
+  - budget: time in milliseconds to open all components, split between batches in proportion to their size,
+    so opening fails with OPEN_BUDGET_EXCEEDED code before an orchestrator kills the process (default: 0, no budget)
+  - batch_size: number of components opened in a batch with its own deadline (default: 0, one batch)
+standby, leader_election: components kept closed until the container acquires the leadership,
+  either all of them in standby mode or marked with "leader_only" parameter (see LeaderElection)
+shedding: degradation score to shed traffic (see DegradationScore)
+references:
+  - log_mutations: true to log locators added and removed when references are mutated after the initial build
+    by reload, restart or MutateReferences (default: false, see EventReferencesChanged)
+wiring_report:
+  - path: file to save the report of resolved component dependencies after open (see GetWiringReport)
+config_watch: reload of configuration when the file changes (see ConfigWatcher and ReloadConfig)
+resources: sampling of CPU and memory usage estimated per component group (see ResourceMonitor)
+history: size of the in-memory history of lifecycle events (see LifecycleHistory and GetHistory)
+alerts: rules that raise alerts on container metrics, like restarts and open duration (see AlertMonitor)
+introspection: read-only server of component states for sidecar observers (see IntrospectionServer)
+runtime: GOMAXPROCS, GC percent and memory limit of the Go runtime applied on open (see RuntimeSettings)
+telemetry:
+  - flush_timeout: timeout in milliseconds to flush buffered loggers and counters on close (default: 5000)
+shutdown_timeout: timeout in milliseconds to close components, after that components still closing
+  are abandoned and Close returns an error with SHUTDOWN_TIMEOUT code (default: 0, wait indefinitely)
+shutdown_hooks:
+  - timeout: timeout in milliseconds to execute each hook registered by AddShutdownHook (default: 5000)
+
 Example
   ======= config.yml ========
   - descriptor: mygroup:mycomponent1:default:default:1.0
@@ -77,18 +142,93 @@ type Container struct {
 	factories       *cbuild.CompositeFactory
 	info            *info.ContextInfo
 	config          config.ContainerConfig
+	configErr       error
 	references      *refer.ContainerReferences
 	referenceable   crefer.IReferenceable
 	unreferenceable crefer.IUnreferenceable
+	inheritedOpened bool
+	scheduler       *ComponentScheduler
+	supervisor      *ComponentSupervisor
+	closeReport     *refer.CloseReport
+	lock            *sync.Mutex
+	state           ContainerState
+	stateChanged    chan struct{}
+	valueProviders  *config.ConfigValueProviders
+	parameters      *cconfig.ConfigParams
+	factoryNames    []string
+	factoryList     []interface{}
+	markers         *LifecycleMarkers
+	cloudMetadata   *CloudMetadata
+	groups          *ComponentGroups
+	leadership      *LeaderElection
+	stateStore      *StateStore
+	dispatcher      *CommandDispatcher
+	versions        *VersionChecker
+	flushTimeout    time.Duration
+	shutdownTimeout time.Duration
+	host            *HostInfo
+	configPath      string
+	watcher         *ConfigWatcher
+	resources       *ResourceMonitor
+	linter          *ConfigLinter
+	history         *LifecycleHistory
+	alerts          *AlertMonitor
+	introspection   *IntrospectionServer
+	runtime         *RuntimeSettings
+	correlationIds  ICorrelationIdStrategy
+
+	sheddingThreshold  float64
+	sheddingRetryAfter int
+	openConcurrency    int
+	openBudget         time.Duration
+	openBatchSize      int
+	wiringReportPath   string
+	failFast           bool
+	strictConfig       string
+	lenient            bool
+	logMutations       bool
+	testOverrides      config.TestOverrides
+	command            string
+	subscribers        []chan<- ContainerEvent
+	transformers       []ConfigTransformer
+	listeners          []IContainerListener
+	shutdownHooks      []ShutdownHook
+	hookTimeout        time.Duration
 }
 
 // Creates a new empty instance of the container.
 // Returns *Container
 func NewEmptyContainer() *Container {
+	logger := log.NewNullLogger()
 	return &Container{
-		logger:    log.NewNullLogger(),
-		factories: build.NewDefaultContainerFactory(),
-		info:      info.NewContextInfo(),
+		logger:         logger,
+		factories:      build.NewDefaultContainerFactory(),
+		info:           info.NewContextInfo(),
+		supervisor:     NewComponentSupervisor(logger),
+		lock:           &sync.Mutex{},
+		state:          StateCreated,
+		valueProviders: config.NewConfigValueProviders(),
+		factoryNames:   []string{"pip-services:factory:container:default:1.0"},
+		markers:        NewLifecycleMarkers(),
+		cloudMetadata:  NewCloudMetadata(),
+		groups:         NewComponentGroups(logger),
+		leadership:     NewLeaderElection(logger),
+		stateStore:     NewStateStore(logger),
+		dispatcher:     NewCommandDispatcher(),
+		versions:       NewVersionChecker(logger),
+		flushTimeout:   5 * time.Second,
+		watcher:        NewConfigWatcher(),
+		resources:      NewResourceMonitor(),
+		linter:         NewConfigLinter(),
+		history:        NewLifecycleHistory(1000),
+		alerts:         NewAlertMonitor(logger),
+		introspection:  NewIntrospectionServer(logger),
+		runtime:        NewRuntimeSettings(logger),
+		correlationIds: &StaticCorrelationIds{},
+
+		sheddingRetryAfter: 5,
+		failFast:           true,
+		hookTimeout:        5 * time.Second,
 	}
 }
 
@@ -109,6 +249,9 @@ func NewContainer(name string, description string) *Container {
 }
 
 // Creates a new instance of the container inherit from reference.
+// Besides receiving references, the inheriting object can implement optional lifecycle interfaces:
+// IOpenable to be opened after all components are opened, IClosable to be closed
+// before components are closed and INotifiable to receive container events.
 // Parameters:
 //   - name string
 //   a container name (accessible via ContextInfo)
@@ -129,31 +272,161 @@ func InheritContainer(name string, description string,
 	return c
 }
 
+// Used to recognize objects that embed the container, since their Open and Close
+// methods are promoted from the container itself.
+type iContainer interface {
+	container() *Container
+}
+
+func (c *Container) container() *Container {
+	return c
+}
+
+// Gets the inheriting object to be involved in the container lifecycle
+// or nil when there is none or it embeds this container.
+func (c *Container) inherited() interface{} {
+	if c.referenceable == nil {
+		return nil
+	}
+	if embedding, ok := c.referenceable.(iContainer); ok && embedding.container() == c {
+		return nil
+	}
+	return c.referenceable
+}
+
 // Configures component by passing configuration parameters.
 // Parameters:
 //   - config  *cconfig.ConfigParams
 //   configuration parameters to be set.
+// Invalid configuration is reported by the next call to Open.
 func (c *Container) Configure(conf *cconfig.ConfigParams) {
-	c.config, _ = config.ReadContainerConfigFromConfig(conf)
+	c.config, c.configErr = config.ReadContainerConfigFromConfig(conf)
 }
 
 // Reads container configuration from JSON or YAML file and parameterizes it with given values.
+// Parameters HOST_NAME, HOST_FQDN, HOST_IP and HOST_IPS are added with resolved host addresses
+// unless they are set explicitly (see HostInfo).
 // Parameters:
 //   - correlationId string
 //   transaction id to trace execution through call chain.
 //   - path string
-//   a path to configuration file
+//   a path to configuration file, a directory with configuration files merged in lexical order
+//   or "-" to read the configuration from the standard input
 //   - parameters *cconfig.ConfigParams
 // values to parameters the configuration or null to skip parameterization.
 func (c *Container) ReadConfigFromFile(correlationId string,
 	path string, parameters *cconfig.ConfigParams) error {
 
 	var err error
+
+	parameters = c.setParameters(parameters)
+	c.configPath = path
+	if config.IsStdinPath(path) {
+		// The standard input can't be read again to reload the configuration
+		c.configPath = ""
+	}
 	c.config, err = config.ContainerConfigReader.ReadFromFile(correlationId, path, parameters)
+	c.configErr = err
 	//c.logger.Trace(correlationId, config.String())
 	return err
 }
 
+// Reads container configuration from the content and parameterizes it with given values.
+// It allows to load configurations embedded with go:embed directive without writing temporary files.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - data []byte
+//   the configuration content.
+//   - format string
+//   a file extension or a MIME type of the content, for instance ".yml" or "application/json".
+//   - parameters *cconfig.ConfigParams
+//   values to parameters the configuration or nil to skip parameterization.
+// Returns error
+func (c *Container) ReadConfigFromBytes(correlationId string, data []byte,
+	format string, parameters *cconfig.ConfigParams) error {
+
+	var err error
+
+	parameters = c.setParameters(parameters)
+	c.configPath = ""
+	c.config, err = config.ContainerConfigReader.ReadFromBytes(correlationId, data, format, parameters)
+	c.configErr = err
+	return err
+}
+
+// Reads container configuration from the stream and parameterizes it with given values.
+// It allows to load configurations streamed, for instance, from an object storage.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - reader io.Reader
+//   the stream with configuration content.
+//   - format string
+//   a file extension or a MIME type of the content, for instance ".yml" or "application/json".
+//   - parameters *cconfig.ConfigParams
+//   values to parameters the configuration or nil to skip parameterization.
+// Returns error
+func (c *Container) ReadConfigFromReader(correlationId string, reader io.Reader,
+	format string, parameters *cconfig.ConfigParams) error {
+
+	var err error
+
+	parameters = c.setParameters(parameters)
+	c.configPath = ""
+	c.config, err = config.ContainerConfigReader.ReadFromReader(correlationId, reader, format, parameters)
+	c.configErr = err
+	return err
+}
+
+// Keeps configuration parameters for profiles and reloads, adding resolved host addresses
+// unless they are set explicitly.
+func (c *Container) setParameters(parameters *cconfig.ConfigParams) *cconfig.ConfigParams {
+	c.host = ResolveHostInfo()
+	if parameters != nil {
+		parameters = parameters.SetDefaults(c.host.GetParameters())
+	}
+
+	c.parameters = parameters
+	return parameters
+}
+
+// Applies options defined in the container configuration section.
+func (c *Container) configureOptions(options *cconfig.ConfigParams) {
+	c.supervisor.Configure(options)
+	c.markers.Configure(options)
+	c.cloudMetadata.Configure(options)
+	c.groups.Configure(options)
+	c.leadership.Configure(options)
+	c.stateStore.Configure(options)
+	c.watcher.Configure(options)
+	c.resources.Configure(options)
+	c.history.Configure(options)
+	c.alerts.Configure(options)
+	c.introspection.Configure(options)
+	c.runtime.Configure(options)
+	c.dispatcher.Configure(options)
+	c.versions.Configure(options)
+
+	flushTimeout := options.GetAsLongWithDefault("telemetry.flush_timeout", int64(c.flushTimeout/time.Millisecond))
+	c.flushTimeout = time.Duration(flushTimeout) * time.Millisecond
+	shutdownTimeout := options.GetAsLongWithDefault("shutdown_timeout", int64(c.shutdownTimeout/time.Millisecond))
+	c.shutdownTimeout = time.Duration(shutdownTimeout) * time.Millisecond
+	hookTimeout := options.GetAsLongWithDefault("shutdown_hooks.timeout", int64(c.hookTimeout/time.Millisecond))
+	c.hookTimeout = time.Duration(hookTimeout) * time.Millisecond
+	c.sheddingThreshold = options.GetAsDoubleWithDefault("shedding.threshold", c.sheddingThreshold)
+	c.sheddingRetryAfter = options.GetAsIntegerWithDefault("shedding.retry_after", c.sheddingRetryAfter)
+	c.openConcurrency = options.GetAsIntegerWithDefault("open.concurrency", c.openConcurrency)
+	openBudget := options.GetAsLongWithDefault("open.budget", int64(c.openBudget/time.Millisecond))
+	c.openBudget = time.Duration(openBudget) * time.Millisecond
+	c.openBatchSize = options.GetAsIntegerWithDefault("open.batch_size", c.openBatchSize)
+	c.wiringReportPath = options.GetAsStringWithDefault("wiring_report.path", c.wiringReportPath)
+	c.failFast = options.GetAsBooleanWithDefault("fail_fast", c.failFast)
+	c.strictConfig = options.GetAsStringWithDefault("strict_config", c.strictConfig)
+	c.lenient = options.GetAsBooleanWithDefault("lenient", c.lenient)
+	c.logMutations = options.GetAsBooleanWithDefault("references.log_mutations", c.logMutations)
+}
+
 func (c *Container) initReferences(references crefer.IReferences) {
 	existingInfo, ok := references.GetOneOptional(
 		crefer.NewDescriptor("pip-services", "context-info", "*", "*", "1.0"),
@@ -164,13 +437,32 @@ func (c *Container) initReferences(references crefer.IReferences) {
 			c.info,
 		)
 	} else {
-		c.info = existingInfo
+		c.setInfo(existingInfo)
 	}
 
 	references.Put(
 		crefer.NewDescriptor("pip-services", "factory", "container", "default", "1.0"),
 		c.factories,
 	)
+
+	references.Put(
+		crefer.NewDescriptor("pip-services", "health-source", "container", "default", "1.0"),
+		&containerHealthSource{container: c},
+	)
+}
+
+// Gets the container references or nil when the container is not opened.
+// References are replaced by Open and Close, so they are read under the lock.
+func (c *Container) getReferences() *refer.ContainerReferences {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.references
+}
+
+func (c *Container) setReferences(references *refer.ContainerReferences) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.references = references
 }
 
 func (c *Container) Logger() log.ILogger {
@@ -179,41 +471,475 @@ func (c *Container) Logger() log.ILogger {
 
 func (c *Container) SetLogger(logger log.ILogger) {
 	c.logger = logger
+	c.supervisor.SetLogger(logger)
+	c.groups.SetLogger(logger)
+	c.leadership.SetLogger(logger)
+	c.stateStore.SetLogger(logger)
+	c.versions.SetLogger(logger)
+	c.alerts.SetLogger(logger)
+	c.introspection.SetLogger(logger)
+	c.runtime.SetLogger(logger)
 }
 
 func (c *Container) Info() *info.ContextInfo {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 	return c.info
 }
 
+func (c *Container) setInfo(info *info.ContextInfo) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.info = info
+}
+
+// Gets the number of goroutines running on behalf of each component that implements IGoroutineAccountable.
+// The result also includes the total number of goroutines in the process under "process" key.
+// Returns map[string]int64
+// goroutine counts indexed by component locators.
+func (c *Container) GetGoroutineCounts() map[string]int64 {
+	result := map[string]int64{
+		"process": int64(runtime.NumGoroutine()),
+	}
+
+	references := c.getReferences()
+	if references == nil {
+		return result
+	}
+
+	locators := references.GetAllLocators()
+	for index, component := range references.GetAll() {
+		accountable, ok := component.(IGoroutineAccountable)
+		if !ok || index >= len(locators) {
+			continue
+		}
+		result[cconv.StringConverter.ToString(locators[index])] = accountable.GoroutineCount()
+	}
+
+	return result
+}
+
+// Runs the container wiring over an object that is not registered in the container references.
+// If the object implements IReferenceable interface it receives references to all container components.
+// It allows short-lived helpers, for instance CLI commands, to use container components without being managed by the container.
+// Parameters:
+//   - obj interface{}
+//   an object to inject references into.
+// Returns error
+// InvalidStateError when the container is not opened.
+func (c *Container) InjectInto(obj interface{}) error {
+	return c.InjectIntoWithConfig(obj, nil)
+}
+
+// Runs the container wiring over an object that is not registered in the container references.
+// If the object implements IConfigurable interface it is configured with the given parameters first,
+// and then if it implements IReferenceable interface it receives references to all container components.
+// Parameters:
+//   - obj interface{}
+//   an object to configure and inject references into.
+//   - conf *cconfig.ConfigParams
+//   configuration parameters or nil to skip configuration.
+// Returns error
+// InvalidStateError when the container is not opened.
+func (c *Container) InjectIntoWithConfig(obj interface{}, conf *cconfig.ConfigParams) error {
+	if obj == nil {
+		return nil
+	}
+
+	references := c.getReferences()
+	if references == nil {
+		return cerr.NewInvalidStateError(
+			"", "NOT_OPENED", "Container is not opened",
+		)
+	}
+
+	if conf != nil {
+		if configurable, ok := obj.(cconfig.IConfigurable); ok {
+			configurable.Configure(conf)
+		}
+	}
+
+	if referenceable, ok := obj.(crefer.IReferenceable); ok {
+		referenceable.SetReferences(references)
+	}
+
+	return nil
+}
+
+// Sets marker files that reflect the container state for file-based health checks.
+// The same can be configured with "markers" options in the container configuration section.
+// Parameters:
+//   - readyPath string
+//   a path to the file that exists while the container is opened, or empty string to disable it.
+//   - livePath string
+//   a path to the file that is touched while the container runs, or empty string to disable it.
+//   - heartbeatInterval time.Duration
+//   an interval to touch the liveness file.
+func (c *Container) SetMarkerFiles(readyPath string, livePath string, heartbeatInterval time.Duration) {
+	c.markers.SetPaths(readyPath, livePath, heartbeatInterval)
+}
+
+// Gets the supervisor that restarts components and quarantines the failing ones.
+// Returns *ComponentSupervisor
+func (c *Container) Supervisor() *ComponentSupervisor {
+	return c.supervisor
+}
+
+// Restarts all components that match the locator by closing and opening them again.
+// Components restarted too often are quarantined and can be released with UnquarantineComponent.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - locator interface{}
+//   a locator of components to restart.
+// Returns error
+func (c *Container) RestartComponent(correlationId string, locator interface{}) error {
+	correlationId = c.nextCorrelationId(correlationId, "restart")
+	references := c.getReferences()
+	if references == nil {
+		return cerr.NewInvalidStateError(
+			correlationId, "NOT_OPENED", "Container is not opened",
+		)
+	}
+
+	components, err := references.GetRequired(locator)
+	if err != nil {
+		return err
+	}
+
+	before := c.getReferenceLocators()
+	defer func() {
+		c.publishReferenceMutation(correlationId, ReferenceMutationRestart, before, err)
+	}()
+
+	for _, component := range components {
+		name := cconv.StringConverter.ToString(references.GetComponentLocator(component))
+		degraded := c.supervisor.IsDegraded()
+		err = c.supervisor.Restart(correlationId, name, component)
+		c.recordHistory(correlationId, HistoryComponentRestarted, name, err, nil)
+		if !degraded && c.supervisor.IsDegraded() {
+			c.notify(correlationId, EventDegraded, "component", name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Gets locators of components that depend on components matching the locator,
+// for instance to find out what breaks if the component is restarted.
+// Dependencies are taken from "depends_on" and "dependencies" sections of component configurations.
+// Parameters:
+//   - locator interface{}
+//   a locator of components.
+//   - transitive bool
+//   true to include components that depend on them indirectly.
+// Returns []interface{}
+// locators of dependent components.
+func (c *Container) GetComponentDependents(locator interface{}, transitive bool) []interface{} {
+	result := []interface{}{}
+	references := c.getReferences()
+	if references == nil {
+		return result
+	}
+
+	for _, component := range references.GetOptional(locator) {
+		for _, dependent := range references.GetDependents(component, transitive) {
+			result = append(result, references.GetComponentLocator(dependent))
+		}
+	}
+	return result
+}
+
+// Gets locators of components that components matching the locator depend on.
+// Parameters:
+//   - locator interface{}
+//   a locator of components.
+// Returns []interface{}
+// locators of dependencies.
+func (c *Container) GetComponentDependencies(locator interface{}) []interface{} {
+	result := []interface{}{}
+	references := c.getReferences()
+	if references == nil {
+		return result
+	}
+
+	for _, component := range references.GetOptional(locator) {
+		for _, dependency := range references.GetDependencies(component) {
+			result = append(result, references.GetComponentLocator(dependency))
+		}
+	}
+	return result
+}
+
+// Releases quarantined components that match the locator so they can be restarted again.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - locator interface{}
+//   a locator of quarantined components.
+// Returns bool
+// true if at least one component was released and false otherwise.
+func (c *Container) UnquarantineComponent(correlationId string, locator interface{}) bool {
+	references := c.getReferences()
+	if references == nil {
+		return false
+	}
+
+	result := false
+	for _, component := range references.GetOptional(locator) {
+		name := cconv.StringConverter.ToString(references.GetComponentLocator(component))
+		if c.supervisor.Unquarantine(correlationId, name) {
+			result = true
+		}
+	}
+	return result
+}
+
+// Opens a dormant component group on the first call and restarts its idle timeout on subsequent calls.
+// Components are assigned to groups by "group" parameter in their configuration.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - name string
+//   a name of the group.
+// Returns error
+// InvalidStateError when the container is not opened, NotFoundError when the group is not declared
+// or an error of the component that failed to open.
+func (c *Container) EnsureGroupOpen(correlationId string, name string) error {
+	if !c.IsOpen() {
+		return cerr.NewInvalidStateError(
+			correlationId, "NOT_OPENED", "Container is not opened",
+		)
+	}
+	return c.groups.EnsureOpen(correlationId, name)
+}
+
+// Registers a command executed by IExecutable component.
+// Commands can also be defined in "commands" section of the container options.
+// Parameters:
+//   - name string
+//   a name of the command.
+//   - descriptor *crefer.Descriptor
+//   a descriptor of IExecutable component to run the command.
+// Returns error
+// BadRequestError when the name is empty or the descriptor is nil.
+func (c *Container) RegisterCommand(name string, descriptor *crefer.Descriptor) error {
+	if name == "" {
+		return cerr.NewBadRequestError("", "NO_COMMAND_NAME", "Command name cannot be empty")
+	}
+	if descriptor == nil {
+		return cerr.NewBadRequestError(
+			"", "NO_DESCRIPTOR", "Descriptor of command "+name+" cannot be nil",
+		).WithDetails("command", name)
+	}
+	c.dispatcher.Register(name, descriptor)
+	return nil
+}
+
+// Executes the named command by IExecutable component in the opened container.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - name string
+//   a name of the command.
+//   - args *run.Parameters
+//   command parameters.
+// Returns interface{}, error
+// the command result or an error when the container is not opened, the command is not found or failed.
+func (c *Container) ExecuteCommand(correlationId string, name string, args *run.Parameters) (interface{}, error) {
+	return c.ExecuteCommandWithContext(context.Background(), correlationId, name, args)
+}
+
+// Executes the named command by IExecutable component in the opened container.
+// The execution is limited by the context and the configured execution timeout.
+// Components that implement IContextExecutable interface receive a context derived from it.
+// Parameters:
+//   - ctx context.Context
+//   a context to cancel the execution.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - name string
+//   a name of the command.
+//   - args *run.Parameters
+//   command parameters.
+// Returns interface{}, error
+// the command result or an error when the container is not opened, the command is not found,
+// failed, timed out or was cancelled.
+func (c *Container) ExecuteCommandWithContext(ctx context.Context, correlationId string,
+	name string, args *run.Parameters) (interface{}, error) {
+	references := c.getReferences()
+	if !c.IsOpen() || references == nil {
+		return nil, cerr.NewInvalidStateError(
+			correlationId, "NOT_OPENED", "Container is not opened",
+		)
+	}
+	return c.dispatcher.ExecuteWithContext(ctx, correlationId, references, name, args)
+}
+
+// Excludes components that are not required to run the command from automatic opening.
+// Loggers, tracers and components declared in "depends_on" of the command component are kept.
+func (c *Container) limitToCommand(correlationId string, name string) error {
+	descriptor, err := c.dispatcher.GetDescriptor(correlationId, name)
+	if err != nil {
+		return err
+	}
+
+	references := c.getReferences()
+	components, err := references.GetRequired(descriptor)
+	if err != nil {
+		return err
+	}
+
+	references.ExcludeAllExcept(references.WithDependencies(components), "logger", "tracer")
+	return nil
+}
+
+// Registers a provider that resolves configuration values with the given scheme, for instance "vault:secret/db".
+// Values are resolved when the container is opened, right before components are created.
+// Parameters:
+//  - scheme string
+//  a scheme of values to resolve.
+//  - provider config.IConfigValueProvider
+//  a provider to resolve the values or nil to remove the provider registered for the scheme.
+// Returns error
+// BadRequestError when the scheme is empty.
+func (c *Container) AddConfigValueProvider(scheme string, provider config.IConfigValueProvider) error {
+	if strings.TrimSuffix(scheme, ":") == "" {
+		return cerr.NewBadRequestError("", "NO_SCHEME", "Scheme of config value provider cannot be empty")
+	}
+	c.valueProviders.Register(scheme, provider)
+	return nil
+}
+
 // Adds a factory to the container. The factory is used to create components added to the container by their locators (descriptors).
+// Nil factories are ignored, use TryAddFactory to detect them.
 // Parameters:
 //  - factory IFactory
 //  a component factory to be added.
 func (c *Container) AddFactory(factory cbuild.IFactory) {
+	c.TryAddFactory(factory)
+}
+
+// Adds a factory to the container like AddFactory, but reports factories that can't be added.
+// Parameters:
+//  - factory IFactory
+//  a component factory to be added.
+// Returns error
+// BadRequestError when the factory is nil.
+func (c *Container) TryAddFactory(factory cbuild.IFactory) error {
+	if factory == nil || (reflect.ValueOf(factory).Kind() == reflect.Ptr && reflect.ValueOf(factory).IsNil()) {
+		return cerr.NewBadRequestError("", "NO_FACTORY", "Factory cannot be nil")
+	}
 	c.factories.Add(factory)
+	c.factoryNames = append(c.factoryNames, fmt.Sprintf("%T", factory))
+	c.factoryList = append(c.factoryList, factory)
+	return nil
 }
 
-// Checks if the component is opened.
+// Checks if the component is opened. The container stays opened while it reloads configuration.
 // Returns bool
 // true if the component has been opened and false otherwise.
 func (c *Container) IsOpen() bool {
-	return c.references != nil
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.state == StateOpen || c.state == StateReloading
 }
 
 // Opens the component.
+// It is safe to call Open and Close concurrently. When another call is in progress
+// Open returns ErrOpening or ErrClosing.
 // Parameters:
 //   - correlationId string
 //   transaction id to trace execution through call chain.
 // Returns error
-func (c *Container) Open(correlationId string) (err error) {
-	//var err error
+func (c *Container) Open(correlationId string) error {
+	return c.OpenWithContext(context.Background(), correlationId)
+}
 
-	if c.references != nil {
-		return cerr.NewInvalidStateError(
-			correlationId, "ALREADY_OPENED", "Container was already opened",
-		)
+// Opens the component. When the context is cancelled or its deadline is exceeded,
+// opening stops before the next component and already opened components are closed.
+// Components that implement IContextOpenable interface receive a context derived from it.
+// Parameters:
+//   - ctx context.Context
+//   a context to cancel opening.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+func (c *Container) OpenWithContext(ctx context.Context, correlationId string) (err error) {
+	// Malformed configuration is reported before it reaches components
+	if c.configErr != nil {
+		return c.configErr
+	}
+	if err = c.config.Validate(correlationId); err != nil {
+		return err
 	}
 
+	// The strategy is configured before other options to apply it to the open operation itself
+	options, _ := c.config.ExtractOptions()
+	c.configureCorrelationIds(options)
+	correlationId = c.nextCorrelationId(correlationId, "open")
+	if err = c.beginTransition(correlationId, StateOpening); err != nil {
+		return err
+	}
+
+	c.publish(correlationId, EventOpening, nil, nil)
+
+	err = c.open(ctx, correlationId)
+	if err != nil {
+		c.logger.Fatal(correlationId, err, "Failed to start container")
+		if references := c.getReferences(); references != nil {
+			c.countFailure("open", references.Runner.GetFailedLocator(), err, false)
+		}
+		// Cleanup is not bound by the cancelled context
+		c.close(context.Background(), correlationId)
+		c.markers.Clear()
+		c.endTransition(StateFailed)
+		c.publish(correlationId, EventFailed, err, nil)
+	} else {
+		c.markers.MarkReady()
+		c.endTransition(StateOpen)
+		c.notify(correlationId, EventOpened)
+	}
+
+	return err
+}
+
+// Selects components of active profiles, swaps implementations overridden in tests,
+// applies configuration transformers and resolves values from external providers.
+func (c *Container) selectComponents(correlationId string, options *cconfig.ConfigParams,
+	containerConfig config.ContainerConfig) (config.ContainerConfig, error) {
+	profiles, err := config.GetActiveProfiles(options, c.parameters)
+	if err != nil {
+		return nil, err
+	}
+	containerConfig = containerConfig.FilterByProfiles(profiles)
+	if len(profiles) > 0 {
+		c.logger.Debug(correlationId, "Active profiles: %v", profiles)
+	}
+
+	containerConfig, err = containerConfig.FilterByConditions(c.parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.testOverrides) > 0 {
+		containerConfig = containerConfig.ApplyTestOverrides(c.testOverrides)
+		c.logger.Debug(correlationId, "Applied %d test overrides", len(c.testOverrides))
+	}
+
+	containerConfig, err = c.transformConfig(correlationId, containerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.valueProviders.Resolve(correlationId, containerConfig)
+}
+
+func (c *Container) open(ctx context.Context, correlationId string) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			recoverErr, ok := r.(error)
@@ -222,88 +948,408 @@ func (c *Container) Open(correlationId string) (err error) {
 				recoverErr = errors.New(msg)
 			}
 			err = recoverErr
-			c.logger.Error(correlationId, recoverErr, "Failed to start container")
-			c.Close(correlationId)
 		}
 	}()
 
 	c.logger.Trace(correlationId, "Starting container.")
 
+	// Apply options of the container itself
+	options, containerConfig := c.config.ExtractOptions()
+	c.configureOptions(options)
+	c.markers.MarkLive()
+
+	// Tune the Go runtime before components allocate their resources
+	err = c.runtime.Apply(correlationId)
+	if err != nil {
+		return err
+	}
+
 	// Create references with configured components
-	c.references = refer.NewContainerReferences()
-	c.initReferences(c.references)
-	err = c.references.PutFromConfig(c.config)
+	references := refer.NewContainerReferences()
+	c.initReferences(references)
+	references.Runner.SetOpenConcurrency(c.openConcurrency, references.GetDependencies)
+	references.Runner.SetOpenBudget(c.openBudget, c.openBatchSize)
+	references.Runner.SetContinueOnError(!c.failFast, c.isCriticalComponent)
+	references.SetLenient(c.lenient)
+	c.supervisor.SetRunner(references.Runner)
+	c.lock.Lock()
+	for _, listener := range c.listeners {
+		references.Runner.AddListener(listener)
+	}
+	c.lock.Unlock()
+	references.Runner.AddListener(c.history)
+
+	// Publish references to readers that run concurrently with opening
+	c.setReferences(references)
+
+	// Select components of active profiles and resolve their external values
+	containerConfig, err = c.selectComponents(correlationId, options, containerConfig)
+	if err != nil {
+		return err
+	}
+
+	// Detect port clashes before any component starts listening
+	err = containerConfig.CheckListeners(correlationId)
+	if err != nil {
+		return err
+	}
+
+	// Report all schema violations before any component is created
+	err = c.validateSchemas(correlationId, containerConfig)
+	if err != nil {
+		return err
+	}
+
+	// Create loggers and tracers first so messages produced while
+	// other components are created reach the configured sinks
+	loggerConfig, componentConfig := containerConfig.SplitByTypes("logger", "tracer")
+	err = references.PutFromConfig(loggerConfig)
+	if err != nil {
+		return err
+	}
+	if len(loggerConfig) > 0 {
+		c.logger = log.NewCompositeLoggerFromReferences(references)
+		references.SetLogger(c.logger)
+	}
+
+	err = references.PutFromConfig(componentConfig)
+	if err != nil {
+		return err
+	}
+
+	// Check that all declared dependencies are present
+	err = references.ValidateDependencies(correlationId)
 	if err != nil {
 		return err
 	}
 
 	if c.referenceable != nil {
-		c.referenceable.SetReferences(c.references)
+		c.referenceable.SetReferences(references)
 	}
 
 	// Get custom description if available
 	infoDescriptor := crefer.NewDescriptor("*", "context-info", "*", "*", "*")
-	info, ok := c.references.GetOneOptional(infoDescriptor).(*info.ContextInfo)
+	info, ok := references.GetOneOptional(infoDescriptor).(*info.ContextInfo)
 	if ok {
-		c.info = info
+		c.setInfo(info)
+	}
+
+	// Add host addresses and cloud instance metadata to context properties
+	if c.host == nil {
+		c.host = ResolveHostInfo()
+	}
+	if c.info.Properties == nil {
+		c.info.Properties = map[string]string{}
+	}
+	for key, value := range c.host.GetProperties() {
+		c.info.Properties[key] = value
+	}
+
+	if c.cloudMetadata.IsEnabled() {
+		for key, value := range c.cloudMetadata.Read() {
+			c.info.Properties[key] = value
+		}
 	}
 
 	// Get reference to logger
-	c.logger = log.NewCompositeLoggerFromReferences(c.references)
+	c.logger = log.NewCompositeLoggerFromReferences(references)
+	references.SetLogger(c.logger)
+	c.supervisor.SetLogger(c.logger)
+	c.groups.SetLogger(c.logger)
+	c.leadership.SetLogger(c.logger)
+	c.stateStore.SetLogger(c.logger)
+	c.versions.SetLogger(c.logger)
 
-	// Open references
-	err = c.references.Open(correlationId)
-	if err == nil {
-		c.logger.Info(correlationId, "Container %s started", c.info.Name)
+	// Check that libraries are not mixed in incompatible versions
+	sources := append(append([]interface{}{}, c.factoryList...), references.GetAll()...)
+	err = c.versions.Check(correlationId, sources)
+	if err != nil {
+		return err
+	}
+
+	// Catch mistyped keys that components silently ignore
+	err = c.checkUnusedConfigKeys(correlationId)
+	if err != nil {
+		return err
+	}
+
+	// Exclude components of dormant groups from automatic opening
+	c.groups.Register(references)
+
+	// Exclude scheduled components that are outside of their windows
+	c.scheduler = NewComponentScheduler(c.logger)
+	err = c.scheduler.Register(references)
+	if err != nil {
+		return err
+	}
+
+	// Open only components required to run the command
+	if c.command != "" {
+		err = c.limitToCommand(correlationId, c.command)
+		if err != nil {
+			return err
+		}
 	} else {
-		c.logger.Fatal(correlationId, err, "Failed to start container")
-		c.Close(correlationId)
+		// Keep components gated by the leadership closed until the election is won
+		c.leadership.Register(references, c.info.Name)
 	}
 
-	return err
+	// Restore state of stateful components saved before the last restart
+	err = c.stateStore.Restore(correlationId, references)
+	if err != nil {
+		return err
+	}
+
+	// Open references
+	err = references.OpenWithContext(ctx, correlationId)
+	if err != nil {
+		return err
+	}
+	c.reportOpenFailures(correlationId)
+
+	// Save how dependencies of components were resolved
+	if c.wiringReportPath != "" {
+		c.saveWiringReport(correlationId, c.wiringReportPath)
+	}
+
+	// Open the inheriting object when all its references are ready
+	if openable, ok := c.inherited().(run.IOpenable); ok && !openable.IsOpen() {
+		err = openable.Open(correlationId)
+		if err != nil {
+			return err
+		}
+		c.inheritedOpened = true
+	}
+
+	c.scheduler.Start(correlationId)
+
+	// Open gated components when the container becomes the leader
+	c.leadership.SetOnChange(func(correlationId string, leader bool) {
+		if leader {
+			c.notify(correlationId, EventPromoted)
+		} else {
+			c.notify(correlationId, EventDemoted)
+		}
+	})
+	err = c.leadership.Start(correlationId, references)
+	if err != nil {
+		return err
+	}
+
+	// Reload configuration when the file changes
+	if c.watcher.IsEnabled() && c.configPath != "" {
+		if c.parameters != nil {
+			environment := c.parameters.GetAsString(config.EnvironmentParameter)
+			c.watcher.SetOverlayPath(config.GetOverlayPath(c.configPath, environment))
+		}
+		c.watcher.Start(c.configPath, func() {
+			c.reloadFromFile(correlationId)
+		})
+	}
+
+	err = c.introspection.Start(correlationId, c.IntrospectionHandler())
+	if err != nil {
+		return err
+	}
+
+	c.resources.Register(c.info.Name, references)
+	c.resources.Start(correlationId)
+	c.alerts.Start(c.getAlertMetric, func(raised []*AlertViolation, resolved []*AlertViolation) {
+		c.handleAlerts(correlationId, raised, resolved)
+	})
+
+	c.logger.Info(correlationId, "Container %s started", c.info.Name)
+
+	return nil
+}
+
+// Sets overrides that swap component implementations, for instance with in-memory fakes,
+// to run integration tests against production configurations.
+// The overrides are applied when the container is opened or reloads its configuration.
+// Parameters:
+//   - overrides config.TestOverrides
+//   the overrides or nil to create components as configured.
+func (c *Container) SetTestOverrides(overrides config.TestOverrides) {
+	c.testOverrides = overrides
+}
+
+// Sets timeout to close components. When it is exceeded, components that are still closing
+// are abandoned and Close returns an error with SHUTDOWN_TIMEOUT code.
+// The timeout can also be set by "shutdown_timeout" option.
+// Parameters:
+//   - timeout time.Duration
+//   a timeout to close components or 0 to wait indefinitely.
+func (c *Container) SetShutdownTimeout(timeout time.Duration) {
+	c.shutdownTimeout = timeout
 }
 
 // Closes component and frees used resources.
+// It is safe to call Open and Close concurrently. When another call is in progress
+// Close returns ErrOpening or ErrClosing.
 // Parameters:
 //   - correlationId string
 //   transaction id to trace execution through call chain.
 // Returns error
 func (c *Container) Close(correlationId string) error {
+	return c.CloseWithContext(context.Background(), correlationId)
+}
+
+// Closes component and frees used resources. Components that implement IContextClosable
+// interface receive a context derived from the context, the rest are closed as usual.
+// Parameters:
+//   - ctx context.Context
+//   a context to cancel closing.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+func (c *Container) CloseWithContext(ctx context.Context, correlationId string) error {
+	correlationId = c.nextCorrelationId(correlationId, "close")
+	c.lock.Lock()
 	// Skip if container wasn't opened
-	if c.references == nil {
+	if c.references == nil && c.state != StateOpening && c.state != StateClosing {
+		c.lock.Unlock()
 		return nil
 	}
+	c.lock.Unlock()
 
-	var err error
+	if err := c.beginTransition(correlationId, StateClosing); err != nil {
+		return err
+	}
+
+	c.markers.UnmarkReady()
+	c.notify(correlationId, EventClosing)
+	err := c.close(ctx, correlationId)
+	c.markers.Clear()
+	c.endTransition(StateClosed)
+	c.publish(correlationId, EventClosed, err, nil)
+
+	return err
+}
+
+func (c *Container) close(ctx context.Context, correlationId string) (err error) {
+	// Skip if container wasn't opened
+	references := c.getReferences()
+	if references == nil {
+		return nil
+	}
 
 	defer func() {
 		if r := recover(); r != nil {
-			err, ok := r.(error)
+			recoverErr, ok := r.(error)
 			if !ok {
 				msg := cconv.StringConverter.ToString(r)
-				err = errors.New(msg)
+				recoverErr = errors.New(msg)
 			}
+			err = recoverErr
+			c.setReferences(nil)
 			c.logger.Error(correlationId, err, "Failed to stop container")
 		}
 	}()
 
 	c.logger.Trace(correlationId, "Stopping %s container", c.info.Name)
 
+	c.watcher.Stop()
+	c.resources.Stop()
+	c.alerts.Stop()
+	c.introspection.Stop()
+
+	// Stop opening and closing scheduled components
+	if c.scheduler != nil {
+		c.scheduler.Stop()
+	}
+	c.groups.Stop()
+	c.leadership.Stop(correlationId)
+
+	// Save state of stateful components while the store backend is still opened
+	c.stateStore.Save(correlationId, references)
+
+	// Close the inheriting object while its references are still opened
+	if closable, ok := c.inherited().(run.IClosable); ok && c.inheritedOpened {
+		c.inheritedOpened = false
+		closeErr := closable.Close(correlationId)
+		if closeErr != nil {
+			c.logger.Error(correlationId, closeErr, "Failed to close inherited container")
+		}
+	}
+
 	// Unset references for child container
 	if c.unreferenceable != nil {
 		c.unreferenceable.UnsetReferences()
 	}
 
 	// Close and dereference components
-	err = c.references.Close(correlationId)
+	if c.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.shutdownTimeout)
+		defer cancel()
+	}
 
-	c.references = nil
+	components := references.GetAll()
+	var report *refer.CloseReport
+	report, err = references.CloseWithReportContext(ctx, correlationId)
+	c.closeReport = report
+	c.logCloseReport(correlationId, report)
+	abandoned := []string{}
+	for _, component := range report.Components {
+		if component.Error != nil || component.TimedOut {
+			c.countFailure("close", component.Locator, component.Error, component.TimedOut)
+		}
+		if component.TimedOut {
+			abandoned = append(abandoned, cconv.StringConverter.ToString(component.Locator))
+		}
+	}
+
+	if len(abandoned) > 0 && ctx.Err() == context.DeadlineExceeded {
+		c.logger.Warn(correlationId, "Shutdown timeout is exceeded, abandoned components: %s",
+			strings.Join(abandoned, ", "))
+		err = cerr.NewInternalError(
+			correlationId, "SHUTDOWN_TIMEOUT", "Container "+c.info.Name+" was not closed in time",
+		).WithDetails("components", abandoned).WithDetails("timeout", c.shutdownTimeout.Milliseconds())
+	}
+
+	c.setReferences(nil)
 
 	if err == nil {
-		c.logger.Info(correlationId, "Container %s stopped", c.info.Name)
+		c.logger.Info(correlationId, "Container %s stopped in %d ms",
+			c.info.Name, report.Duration.Milliseconds())
 	} else {
 		c.logger.Error(correlationId, err, "Failed to stop container")
 	}
 
+	// Run process-level cleanup after all components are closed
+	hookErr := c.runShutdownHooks(correlationId)
+	if err == nil {
+		err = hookErr
+	}
+
+	// Flush buffered loggers and counters as the very last step
+	flushErr := c.flush(correlationId, components)
+	if flushErr != nil {
+		c.logger.Warn(correlationId, "Failed to flush telemetry: %v", flushErr)
+	}
+
 	return err
 }
+
+func (c *Container) logCloseReport(correlationId string, report *refer.CloseReport) {
+	for _, component := range report.Components {
+		name := cconv.StringConverter.ToString(component.Locator)
+		duration := component.Duration.Milliseconds()
+
+		if component.TimedOut {
+			c.logger.Warn(correlationId, "Component %s timed out to close after %d ms", name, duration)
+		} else if component.Error != nil {
+			c.logger.Error(correlationId, component.Error, "Component %s failed to close in %d ms", name, duration)
+		} else {
+			c.logger.Debug(correlationId, "Component %s closed in %d ms", name, duration)
+		}
+	}
+}
+
+// Gets the report created during the last container close.
+// It contains close duration and outcome for every component and the total shutdown time.
+// Returns *refer.CloseReport
+// the last close report or nil if the container was never closed.
+func (c *Container) LastCloseReport() *refer.CloseReport {
+	return c.closeReport
+}
@@ -0,0 +1,206 @@
+package container
+
+import (
+	"time"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/run"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+// Names of container events passed in "event" parameter to components that implement INotifiable interface.
+const (
+	// The container has opened all components.
+	EventOpened = "opened"
+	// A component was quarantined or failed to open and the container runs in degraded mode.
+	EventDegraded = "degraded"
+	// A new configuration was applied to the running container.
+	EventReloaded = "reloaded"
+	// The container is about to close its components.
+	EventClosing = "closing"
+	// The container acquired the leadership and opened gated components.
+	EventPromoted = "promoted"
+	// The container lost the leadership and closed gated components.
+	EventDemoted = "demoted"
+	// A metric violated an alert rule (see AlertMonitor).
+	EventAlert = "alert"
+	// A metric returned within the threshold of a raised alert.
+	EventAlertResolved = "alert_resolved"
+)
+
+// Names of container events delivered only to subscribers, since components are not available at that time.
+const (
+	// The container started to open its components.
+	EventOpening = "opening"
+	// The container failed to open and closed its components.
+	EventFailed = "failed"
+	// The container has closed all components.
+	EventClosed = "closed"
+)
+
+// Event delivered to channels registered by Container.Subscribe.
+type ContainerEvent struct {
+	Event         string
+	CorrelationId string
+	Time          time.Time
+	Error         error
+	Parameters    *run.Parameters
+}
+
+// Subscribes the channel to container events. Events are sent without blocking:
+// when the channel buffer is full the event is dropped for that subscriber,
+// so a slow consumer cannot delay opening or closing of the container.
+// Use a buffered channel to avoid losing events.
+// Parameters:
+//   - ch chan<- ContainerEvent
+//   a channel to receive events.
+func (c *Container) Subscribe(ch chan<- ContainerEvent) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.subscribers = append(c.subscribers, ch)
+}
+
+// Unsubscribes the previously subscribed channel from container events.
+// The channel is not closed.
+// Parameters:
+//   - ch chan<- ContainerEvent
+//   a channel to be removed.
+func (c *Container) Unsubscribe(ch chan<- ContainerEvent) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for index, subscriber := range c.subscribers {
+		if subscriber == ch {
+			c.subscribers = append(c.subscribers[:index:index], c.subscribers[index+1:]...)
+			return
+		}
+	}
+}
+
+// Listener of container and component lifecycle transitions.
+// Transitions of the container itself are reported with nil locator.
+type IContainerListener = refer.ILifecycleListener
+
+// Adds a listener of container and component lifecycle transitions.
+// It shall be added before the container is opened to receive transitions of components.
+// Parameters:
+//   - listener IContainerListener
+//   a listener to be added.
+func (c *Container) AddLifecycleListener(listener IContainerListener) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.listeners = append(c.listeners, listener)
+}
+
+// Calls lifecycle listeners for the container transition.
+func (c *Container) callListeners(correlationId string, event string, err error) {
+	c.lock.Lock()
+	listeners := c.listeners
+	c.lock.Unlock()
+
+	for _, listener := range listeners {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					c.logger.Error(correlationId, nil, "Lifecycle listener panicked on %s event: %v", event, r)
+				}
+			}()
+
+			switch {
+			case event == EventOpening:
+				listener.OnOpening(correlationId, nil)
+			case event == EventOpened:
+				listener.OnOpened(correlationId, nil)
+			case event == EventClosing:
+				listener.OnClosing(correlationId, nil)
+			case event == EventClosed && err == nil:
+				listener.OnClosed(correlationId, nil)
+			case event == EventClosed, event == EventFailed:
+				listener.OnFailed(correlationId, nil, err)
+			}
+		}()
+	}
+}
+
+// Sends the event to lifecycle listeners and all subscribed channels without blocking.
+func (c *Container) publish(correlationId string, event string, err error, args *run.Parameters) {
+	c.callListeners(correlationId, event, err)
+	c.recordEvent(correlationId, event, err, args)
+
+	c.lock.Lock()
+	subscribers := c.subscribers
+	c.lock.Unlock()
+
+	if len(subscribers) == 0 {
+		return
+	}
+
+	containerEvent := ContainerEvent{
+		Event:         event,
+		CorrelationId: correlationId,
+		Time:          time.Now().UTC(),
+		Error:         err,
+		Parameters:    args,
+	}
+
+	for _, subscriber := range subscribers {
+		select {
+		case subscriber <- containerEvent:
+		default:
+			c.logger.Debug(correlationId, "Dropped %s event for a slow subscriber", event)
+		}
+	}
+}
+
+// Notifies subscribers, components and the inheriting object that implement INotifiable interface about the container event.
+// Panics in components are logged and do not prevent notification of other components.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - event string
+//   a name of the event.
+//   - tuples ...interface{}
+//   additional event parameters as key-value pairs.
+func (c *Container) notify(correlationId string, event string, tuples ...interface{}) {
+	args := run.NewParametersFromTuples(append([]interface{}{"event", event}, tuples...)...)
+	c.publish(correlationId, event, nil, args)
+
+	components := []interface{}{}
+	if references := c.getReferences(); references != nil {
+		components = references.GetAll()
+	}
+	if inherited := c.inherited(); inherited != nil {
+		components = append(components, inherited)
+	}
+
+	for _, component := range components {
+		notifiable, ok := component.(run.INotifiable)
+		if !ok {
+			continue
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					c.logger.Error(correlationId, nil, "Component panicked on %s event: %v", event, r)
+				}
+			}()
+			notifiable.Notify(correlationId, args)
+		}()
+	}
+}
+
+// Records the container event with its parameters in the history.
+func (c *Container) recordEvent(correlationId string, event string, err error, args *run.Parameters) {
+	var details map[string]string
+	if args != nil {
+		details = map[string]string{}
+		for _, key := range args.Keys() {
+			if key != "event" {
+				details[key] = args.GetAsString(key)
+			}
+		}
+	}
+	c.recordHistory(correlationId, event, "", err, details)
+}
@@ -0,0 +1,194 @@
+package container
+
+import (
+	"strings"
+
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+// Value of "reload" component parameter that freezes the component during reloads.
+const ReloadNever = "never"
+
+// Applies the updated configuration to the running container. Only components whose
+// sections were changed are affected: removed components are closed and dereferenced,
+// changed components are reconfigured and restarted in place and added components are created and opened.
+// Unchanged components are reused as they are, unless components they depend on were replaced,
+// then they are relinked and restarted (see ComponentFingerprint in refer package).
+// Mutated references are reported to subscribers with "references_changed" event.
+// Running components marked with "reload: never" are never reconfigured or relinked,
+// a warning is logged instead, so stateful components are not wiped accidentally.
+// Options of the container itself are applied on the next open.
+// On success components are notified with "reloaded" event.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - containerConfig config.ContainerConfig
+//   the updated container configuration.
+// Returns error
+// InvalidStateError when the container is not opened or an error of the component that failed to restart.
+func (c *Container) ReloadConfig(correlationId string, containerConfig config.ContainerConfig) error {
+	correlationId = c.nextCorrelationId(correlationId, "reload")
+	if err := containerConfig.Validate(correlationId); err != nil {
+		return err
+	}
+	if err := c.beginTransition(correlationId, StateReloading); err != nil {
+		return err
+	}
+	defer c.endTransition(StateOpen)
+	references := c.getReferences()
+
+	options, components := containerConfig.ExtractOptions()
+	components, err := c.selectComponents(correlationId, options, components)
+	if err != nil {
+		return err
+	}
+
+	err = components.CheckListeners(correlationId)
+	if err != nil {
+		return err
+	}
+
+	err = c.validateSchemas(correlationId, components)
+	if err != nil {
+		return err
+	}
+
+	diff := config.DiffContainerConfigs(c.getRunningConfig(), components)
+	c.config = containerConfig
+	if diff.IsEmpty() {
+		c.logger.Debug(correlationId, "Configuration of container %s has no changes in components", c.info.Name)
+		return nil
+	}
+
+	changed := []string{}
+	frozen := 0
+
+	// Report locators before and after the reload, even when it fails halfway
+	before := c.getReferenceLocators()
+	defer func() {
+		c.publishReferenceMutation(correlationId, ReferenceMutationReload, before, err)
+	}()
+
+	// Fingerprint components that keep their configuration to find ones whose dependencies are replaced
+	kept := []interface{}{}
+	fingerprints := []*refer.ComponentFingerprint{}
+	for _, component := range references.GetAll() {
+		componentConfig := references.GetComponentConfig(component)
+		if componentConfig == nil || diff.IsAffected(componentConfig) {
+			continue
+		}
+		kept = append(kept, component)
+		fingerprints = append(fingerprints, references.GetFingerprint(component))
+	}
+
+	for _, componentConfig := range diff.Removed {
+		component := c.findComponentByConfig(componentConfig)
+		if component != nil {
+			changed = append(changed, cconv.StringConverter.ToString(references.GetComponentLocator(component)))
+			references.RemoveComponent(component)
+		}
+	}
+
+	for _, change := range diff.Changed {
+		component := c.findComponentByConfig(change.Current)
+		if component == nil {
+			continue
+		}
+		if isFrozen(change.Current) {
+			c.logger.Warn(correlationId, "Component %v is not reconfigured because it is marked with reload: never",
+				references.GetComponentLocator(component))
+			frozen++
+			continue
+		}
+		changed = append(changed, cconv.StringConverter.ToString(references.GetComponentLocator(component)))
+		err = references.ReconfigureComponent(correlationId, component, change.Updated)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = references.AddFromConfig(correlationId, diff.Added)
+	if err != nil {
+		return err
+	}
+	for _, componentConfig := range diff.Added {
+		if componentConfig.Descriptor != nil {
+			changed = append(changed, componentConfig.Descriptor.String())
+		} else if componentConfig.Type != nil {
+			changed = append(changed, componentConfig.Type.String())
+		}
+	}
+
+	relinked := 0
+	for index, component := range kept {
+		if fingerprints[index].Equals(references.GetFingerprint(component)) {
+			continue
+		}
+		if isFrozen(references.GetComponentConfig(component)) {
+			c.logger.Warn(correlationId, "Component %v is not relinked because it is marked with reload: never",
+				references.GetComponentLocator(component))
+			continue
+		}
+		changed = append(changed, cconv.StringConverter.ToString(references.GetComponentLocator(component)))
+		err = references.RelinkComponent(correlationId, component)
+		if err != nil {
+			return err
+		}
+		relinked++
+	}
+
+	c.logger.Info(correlationId, "Container %s reloaded configuration: %d added, %d removed, %d changed, %d relinked, %d reused, %d frozen components",
+		c.info.Name, len(diff.Added), len(diff.Removed), len(diff.Changed)-frozen, relinked, len(kept)-relinked, frozen)
+	c.notify(correlationId, EventReloaded, "components", strings.Join(changed, ","))
+
+	return nil
+}
+
+// Reads the configuration file again and applies it to the running container.
+func (c *Container) reloadFromFile(correlationId string) {
+	containerConfig, err := config.ContainerConfigReader.ReadFromFile(correlationId, c.configPath, c.parameters)
+	if err == nil {
+		err = c.ReloadConfig(correlationId, containerConfig)
+	}
+	if err != nil {
+		c.logger.Error(correlationId, err, "Failed to reload configuration from %s", c.configPath)
+	}
+}
+
+// Checks if the component is marked with "reload: never" and shall not be touched by reloads.
+func isFrozen(componentConfig *config.ComponentConfig) bool {
+	return componentConfig != nil && componentConfig.Config != nil &&
+		strings.EqualFold(componentConfig.Config.GetAsString("reload"), ReloadNever)
+}
+
+// Finds the running component created from the configuration.
+func (c *Container) findComponentByConfig(componentConfig *config.ComponentConfig) interface{} {
+	references := c.getReferences()
+	if references == nil {
+		return nil
+	}
+	for _, component := range references.GetAll() {
+		if references.GetComponentConfig(component) == componentConfig {
+			return component
+		}
+	}
+	return nil
+}
+
+// Collects configurations of running components.
+func (c *Container) getRunningConfig() config.ContainerConfig {
+	result := config.ContainerConfig{}
+	references := c.getReferences()
+	if references == nil {
+		return result
+	}
+	for _, component := range references.GetAll() {
+		if componentConfig := references.GetComponentConfig(component); componentConfig != nil {
+			result = append(result, componentConfig)
+		}
+	}
+	return result
+}
+
@@ -0,0 +1,156 @@
+package container
+
+import (
+	"time"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+State of the container lifecycle.
+
+Transitions
+  Created -> Opening -> Open | Failed
+  Open -> Reloading -> Open
+  Open | Failed -> Closing -> Closed
+  Closed | Failed -> Opening
+
+see
+Container.GetState
+Container.WaitForState
+*/
+type ContainerState int
+
+const (
+	// The container is created and was never opened.
+	StateCreated ContainerState = iota
+	// The container opens its components.
+	StateOpening
+	// All components are opened.
+	StateOpen
+	// The container closes its components.
+	StateClosing
+	// All components are closed.
+	StateClosed
+	// The container failed to open and closed its components.
+	StateFailed
+	// The container applies the updated configuration.
+	StateReloading
+)
+
+var containerStateNames = []string{"Created", "Opening", "Open", "Closing", "Closed", "Failed", "Reloading"}
+
+// Gets the state name.
+// Returns string
+func (c ContainerState) String() string {
+	if c >= 0 && int(c) < len(containerStateNames) {
+		return containerStateNames[c]
+	}
+	return "Unknown"
+}
+
+// Error returned when the container is requested to open or close while it is being opened.
+var ErrOpening = cerr.NewInvalidStateError("", "OPENING", "Container is being opened")
+
+// Error returned when the container is requested to open or close while it is being closed.
+var ErrClosing = cerr.NewInvalidStateError("", "CLOSING", "Container is being closed")
+
+// Error returned when the container is requested to open or close while it reloads configuration.
+var ErrReloading = cerr.NewInvalidStateError("", "RELOADING", "Container is reloading configuration")
+
+// Moves the container into transitional state or returns an error
+// when the transition is not allowed from the current state.
+func (c *Container) beginTransition(correlationId string, state ContainerState) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	switch c.state {
+	case StateOpening:
+		return ErrOpening
+	case StateClosing:
+		return ErrClosing
+	case StateReloading:
+		return ErrReloading
+	}
+
+	if state == StateReloading && c.state != StateOpen {
+		return cerr.NewInvalidStateError(
+			correlationId, "NOT_OPENED", "Container is not opened",
+		)
+	}
+
+	if state == StateOpening && c.references != nil {
+		return cerr.NewInvalidStateError(
+			correlationId, "ALREADY_OPENED", "Container was already opened",
+		)
+	}
+
+	c.setState(state)
+	return nil
+}
+
+// Completes the transition by moving the container into the final state.
+func (c *Container) endTransition(state ContainerState) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.setState(state)
+}
+
+// Sets the state and wakes up callers of WaitForState. It shall be called under the lock.
+func (c *Container) setState(state ContainerState) {
+	c.state = state
+	if c.stateChanged != nil {
+		close(c.stateChanged)
+	}
+	c.stateChanged = make(chan struct{})
+}
+
+// Gets the current state of the container.
+// Returns ContainerState
+func (c *Container) GetState() ContainerState {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.state
+}
+
+// Waits until the container reaches the state. It is used by supervisors and tests
+// to coordinate with long-running Open and Close called from other goroutines.
+// Parameters:
+//   - state ContainerState
+//   the state to wait for.
+//   - timeout time.Duration
+//   the maximum time to wait or 0 to wait indefinitely.
+// Returns error
+// InvalidStateError with STATE_TIMEOUT code when the state was not reached in time.
+func (c *Container) WaitForState(state ContainerState, timeout time.Duration) error {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		c.lock.Lock()
+		current := c.state
+		if c.stateChanged == nil {
+			c.stateChanged = make(chan struct{})
+		}
+		changed := c.stateChanged
+		c.lock.Unlock()
+
+		if current == state {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-expired:
+			return cerr.NewInvalidStateError(
+				"", "STATE_TIMEOUT", "Container did not reach "+state.String()+" state in time",
+			).WithDetails("state", state.String()).WithDetails("current", current.String())
+		}
+	}
+}
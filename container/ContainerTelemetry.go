@@ -0,0 +1,73 @@
+package container
+
+import (
+	"time"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+// Interface of buffered loggers and counters, such as CachedLogger and CachedCounters,
+// that keep messages in memory until they are dumped.
+type iDumpable interface {
+	Dump() error
+}
+
+// Flushes buffered loggers and counters in the container.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+// the first error returned by a component or InvalidStateError when flush didn't complete within the flush timeout.
+func (c *Container) FlushTelemetry(correlationId string) error {
+	references := c.getReferences()
+	if references == nil {
+		return nil
+	}
+	return c.flush(correlationId, references.GetAll())
+}
+
+// Dumps buffered components waiting no longer than the flush timeout,
+// so a stuck backend cannot delay the process exit.
+func (c *Container) flush(correlationId string, components []interface{}) error {
+	dumpables := []iDumpable{}
+	for _, component := range components {
+		if dumpable, ok := component.(iDumpable); ok {
+			dumpables = append(dumpables, dumpable)
+		}
+	}
+	if len(dumpables) == 0 {
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		var result error
+		for _, dumpable := range dumpables {
+			err := func() (err error) {
+				defer func() {
+					if r := recover(); r != nil {
+						err = cerr.NewInternalError(correlationId, "PANIC", "Component panicked on flush")
+					}
+				}()
+				return dumpable.Dump()
+			}()
+			if result == nil {
+				result = err
+			}
+		}
+		done <- result
+	}()
+
+	if c.flushTimeout <= 0 {
+		return <-done
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(c.flushTimeout):
+		return cerr.NewInvalidStateError(
+			correlationId, "FLUSH_TIMEOUT", "Telemetry was not flushed in time",
+		).WithDetails("timeout", c.flushTimeout.Milliseconds())
+	}
+}
@@ -0,0 +1,57 @@
+package container
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+// Gets the report of how dependencies declared in component configurations were resolved,
+// for instance to expose it in an admin API.
+// Returns *refer.WiringReport
+// the wiring report or nil if the container is not opened.
+func (c *Container) GetWiringReport() *refer.WiringReport {
+	references := c.getReferences()
+	if references == nil {
+		return nil
+	}
+	return references.GetWiringReport()
+}
+
+// Saves the wiring report into JSON file. Failures are logged and do not stop the container.
+func (c *Container) saveWiringReport(correlationId string, path string) {
+	report := c.GetWiringReport()
+	if report == nil {
+		return
+	}
+	if report.Unresolved > 0 {
+		c.logger.Warn(correlationId, "%d declared dependencies of components are not resolved", report.Unresolved)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		c.logger.Warn(correlationId, "Failed to save wiring report to %s: %v", path, err)
+	} else {
+		c.logger.Debug(correlationId, "Saved wiring report to %s", path)
+	}
+}
+
+// Gets network endpoints that components declare to listen on. Before the container is opened
+// the endpoints are taken from the configuration, afterwards from configurations of created components.
+// Returns []*config.ListenerEndpoint
+// the declared endpoints.
+func (c *Container) GetListeners() []*config.ListenerEndpoint {
+	if c.getReferences() == nil {
+		if c.config.Validate("") != nil {
+			return []*config.ListenerEndpoint{}
+		}
+		_, components := c.config.ExtractOptions()
+		return components.GetListeners()
+	}
+	return c.getRunningConfig().GetListeners()
+}
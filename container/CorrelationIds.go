@@ -0,0 +1,137 @@
+package container
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cdata "github.com/pip-services3-go/pip-services3-commons-go/data"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Strategy to generate correlation ids of operations the container performs on components:
+open, close, restart and reload. It lets container operations correlate with distributed tracing
+conventions used by the application.
+
+Built-in strategies are selected by "correlation_ids.strategy" option:
+  - static: the correlation id passed by the caller is used as is (default)
+  - uuid: every operation gets a new unique id
+  - traceparent: every operation gets a W3C traceparent value; when the caller passes a traceparent,
+    the trace id is kept and a new parent id is generated
+
+see
+Container.SetCorrelationIdStrategy
+*/
+type ICorrelationIdStrategy interface {
+	// Gets correlation id for the container operation.
+	// Parameters:
+	//   - correlationId string
+	//   the correlation id passed by the caller.
+	//   - operation string
+	//   the operation name: open, close, restart or reload.
+	// Returns string
+	// the correlation id passed to components.
+	Next(correlationId string, operation string) string
+}
+
+// Strategy that uses correlation ids passed by the callers.
+type StaticCorrelationIds struct{}
+
+// Gets correlation id for the container operation.
+// Returns string
+// the correlation id passed by the caller.
+func (c *StaticCorrelationIds) Next(correlationId string, operation string) string {
+	return correlationId
+}
+
+// Strategy that generates a new unique id for every operation.
+type UuidCorrelationIds struct{}
+
+// Gets correlation id for the container operation.
+// Returns string
+// a new unique id.
+func (c *UuidCorrelationIds) Next(correlationId string, operation string) string {
+	return cdata.IdGenerator.NextLong()
+}
+
+// Strategy that generates W3C traceparent values: "00-<trace id>-<parent id>-01".
+type TraceparentCorrelationIds struct{}
+
+// Gets correlation id for the container operation.
+// Returns string
+// a traceparent that continues the caller trace or starts a new one.
+func (c *TraceparentCorrelationIds) Next(correlationId string, operation string) string {
+	traceId := ""
+	parts := strings.Split(correlationId, "-")
+	if len(parts) == 4 && len(parts[1]) == 32 && len(parts[2]) == 16 {
+		traceId = parts[1]
+	} else {
+		traceId = randomHex(16)
+	}
+	return "00-" + traceId + "-" + randomHex(8) + "-01"
+}
+
+func randomHex(size int) string {
+	buffer := make([]byte, size)
+	rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
+
+// Creates a built-in correlation id strategy by its name.
+// Parameters:
+//   - name string
+//   the strategy name: static, uuid or traceparent.
+// Returns ICorrelationIdStrategy, error
+// the strategy or ConfigError when the name is unknown.
+func NewCorrelationIdStrategy(name string) (ICorrelationIdStrategy, error) {
+	switch strings.ToLower(name) {
+	case "", "static":
+		return &StaticCorrelationIds{}, nil
+	case "uuid":
+		return &UuidCorrelationIds{}, nil
+	case "traceparent":
+		return &TraceparentCorrelationIds{}, nil
+	}
+	return nil, cerr.NewConfigError(
+		"", "BAD_CORRELATION_STRATEGY", "Unknown correlation id strategy "+name,
+	).WithDetails("strategy", name)
+}
+
+// Sets the strategy to generate correlation ids of container operations.
+// Parameters:
+//   - strategy ICorrelationIdStrategy
+//   the strategy to be set.
+func (c *Container) SetCorrelationIdStrategy(strategy ICorrelationIdStrategy) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.correlationIds = strategy
+}
+
+func (c *Container) configureCorrelationIds(options *cconfig.ConfigParams) {
+	if !options.Contains("correlation_ids.strategy") {
+		return
+	}
+	name := options.GetAsString("correlation_ids.strategy")
+
+	strategy, err := NewCorrelationIdStrategy(name)
+	if err != nil {
+		c.logger.Warn("", "%v", err)
+		return
+	}
+	c.SetCorrelationIdStrategy(strategy)
+}
+
+// Gets correlation id for the container operation.
+func (c *Container) nextCorrelationId(correlationId string, operation string) string {
+	c.lock.Lock()
+	strategy := c.correlationIds
+	c.lock.Unlock()
+
+	if strategy == nil {
+		return correlationId
+	}
+	return strategy.Next(correlationId, operation)
+}
@@ -0,0 +1,161 @@
+package container
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Cron-like expression with five fields: minute, hour, day of month, month and day of week.
+
+Each field supports "*", single values, ranges "a-b", lists "a,b,c" and steps "a-b/n"
+(a step after "*" applies to the whole range of the field).
+Day of week is numbered from 0 (Sunday) to 6 (Saturday), 7 is also accepted as Sunday.
+When both day of month and day of week are restricted, the expression matches when either of them matches.
+
+Example
+  expr, err := ParseCronExpression("0 22 * * 1-5")
+  expr.Match(time.Now())
+*/
+type CronExpression struct {
+	minutes     []bool
+	hours       []bool
+	daysOfMonth []bool
+	months      []bool
+	daysOfWeek  []bool
+	anyDom      bool
+	anyDow      bool
+}
+
+// Parses cron expression from string.
+// Parameters:
+//   - value string
+//   a string with five space-separated fields.
+// Returns *CronExpression, error
+// the parsed expression or ConfigError when the expression is invalid.
+func ParseCronExpression(value string) (*CronExpression, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 5 {
+		return nil, cerr.NewConfigError("", "BAD_CRON_EXPRESSION",
+			"Cron expression must have 5 fields").WithDetails("expression", value)
+	}
+
+	var err error
+	c := &CronExpression{
+		anyDom: fields[2] == "*",
+		anyDow: fields[4] == "*",
+	}
+
+	if c.minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if c.hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if c.daysOfMonth, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if c.months, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if c.daysOfWeek, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	if c.daysOfWeek[7] {
+		c.daysOfWeek[0] = true
+	}
+
+	return c, nil
+}
+
+func parseCronField(field string, min int, max int) ([]bool, error) {
+	result := make([]bool, max+1)
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if pos := strings.Index(part, "/"); pos >= 0 {
+			value, err := strconv.Atoi(part[pos+1:])
+			if err != nil || value <= 0 {
+				return nil, cerr.NewConfigError("", "BAD_CRON_EXPRESSION",
+					"Invalid step in cron field").WithDetails("field", field)
+			}
+			step = value
+			part = part[:pos]
+		}
+
+		start, end := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			value, err := strconv.Atoi(bounds[0])
+			if err != nil {
+				return nil, cerr.NewConfigError("", "BAD_CRON_EXPRESSION",
+					"Invalid value in cron field").WithDetails("field", field)
+			}
+			start, end = value, value
+			if len(bounds) > 1 {
+				if end, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, cerr.NewConfigError("", "BAD_CRON_EXPRESSION",
+						"Invalid range in cron field").WithDetails("field", field)
+				}
+			} else if step > 1 {
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return nil, cerr.NewConfigError("", "BAD_CRON_EXPRESSION",
+				"Cron field value is out of range").WithDetails("field", field)
+		}
+
+		for value := start; value <= end; value += step {
+			result[value] = true
+		}
+	}
+
+	return result, nil
+}
+
+// Checks if the expression matches the given time with minute precision.
+// Parameters:
+//   - t time.Time
+//   a time to check.
+// Returns bool
+// true if the time matches the expression and false otherwise.
+func (c *CronExpression) Match(t time.Time) bool {
+	if !c.minutes[t.Minute()] || !c.hours[t.Hour()] || !c.months[int(t.Month())] {
+		return false
+	}
+
+	domMatch := c.daysOfMonth[t.Day()]
+	dowMatch := c.daysOfWeek[int(t.Weekday())]
+
+	if c.anyDom || c.anyDow {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Finds the latest time before or at the given time that matches the expression.
+// Parameters:
+//   - t time.Time
+//   a time to start search from.
+//   - lookback time.Duration
+//   the maximum interval to search.
+// Returns time.Time, bool
+// the matching time and true if it was found within the interval.
+func (c *CronExpression) Previous(t time.Time, lookback time.Duration) (time.Time, bool) {
+	current := t.Truncate(time.Minute)
+	limit := t.Add(-lookback)
+
+	for !current.Before(limit) {
+		if c.Match(current) {
+			return current, true
+		}
+		current = current.Add(-time.Minute)
+	}
+
+	return time.Time{}, false
+}
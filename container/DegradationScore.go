@@ -0,0 +1,143 @@
+package container
+
+import (
+	"net/http"
+	"strconv"
+
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	"github.com/pip-services3-go/pip-services3-commons-go/run"
+)
+
+/*
+Interface for components that report their own health, for instance a connection
+that is opened but lost its server. Components that don't implement it are healthy
+while they are opened and not quarantined.
+*/
+type IHealthy interface {
+	// Checks if the component is healthy.
+	// Parameters:
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	// Returns bool
+	IsHealthy(correlationId string) bool
+}
+
+/*
+Degradation of the container calculated from health of its components weighted by their criticality.
+
+Criticality is set by "criticality" parameter in component configuration (default: 1).
+Components with 0 criticality don't affect the score.
+
+Score is 0 when all components are healthy and 1 when all of them are unhealthy.
+When the score reaches "shedding.threshold" option, the container signals load balancers to shed traffic
+through Container.ShouldShedLoad and Container.SheddingMiddleware.
+
+Configuration parameters
+  - shedding:
+    - threshold: degradation score to start shedding traffic, 0 to never shed (default: 0)
+    - retry_after: value of Retry-After header in seconds for shed requests (default: 5)
+
+Example
+  - descriptor: "pip-services:container:default:default:1.0"
+    shedding:
+      threshold: 0.5
+
+  - descriptor: "mygroup:persistence:mongodb:default:1.0"
+    criticality: 10
+
+see
+Container.GetDegradation
+*/
+type DegradationScore struct {
+	Score     float64  `json:"score"`
+	Healthy   float64  `json:"healthy"`
+	Total     float64  `json:"total"`
+	Unhealthy []string `json:"unhealthy"`
+}
+
+// Calculates degradation of the container from health of its components.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns *DegradationScore
+// the degradation score or nil if the container is not opened.
+func (c *Container) GetDegradation(correlationId string) *DegradationScore {
+	references := c.getReferences()
+	if references == nil {
+		return nil
+	}
+
+	result := &DegradationScore{
+		Unhealthy: []string{},
+	}
+
+	for _, component := range references.GetAll() {
+		// Dormant components of groups and schedules are not expected to be opened
+		if references.Runner.IsExcluded(component) {
+			continue
+		}
+
+		criticality := 1.0
+		if componentConfig := references.GetComponentConfig(component); componentConfig != nil && componentConfig.Config != nil {
+			criticality = componentConfig.Config.GetAsDoubleWithDefault("criticality", criticality)
+		}
+		if criticality <= 0 {
+			continue
+		}
+
+		name := cconv.StringConverter.ToString(references.GetComponentLocator(component))
+		result.Total += criticality
+		if c.isComponentHealthy(correlationId, name, component) {
+			result.Healthy += criticality
+		} else {
+			result.Unhealthy = append(result.Unhealthy, name)
+		}
+	}
+
+	if result.Total > 0 {
+		result.Score = 1 - result.Healthy/result.Total
+	}
+	return result
+}
+
+func (c *Container) isComponentHealthy(correlationId string, name string, component interface{}) bool {
+	if c.supervisor.IsQuarantined(name) {
+		return false
+	}
+	if openable, ok := component.(run.IOpenable); ok && !openable.IsOpen() {
+		return false
+	}
+	if healthy, ok := component.(IHealthy); ok {
+		return healthy.IsHealthy(correlationId)
+	}
+	return true
+}
+
+// Checks if upstream load balancers shall shed traffic because
+// the degradation score reached the configured threshold.
+// Returns bool
+// true to shed traffic and false otherwise.
+func (c *Container) ShouldShedLoad() bool {
+	if c.sheddingThreshold <= 0 {
+		return false
+	}
+	degradation := c.GetDegradation("")
+	return degradation != nil && degradation.Score >= c.sheddingThreshold
+}
+
+// Wraps HTTP handler to respond with 503 Service Unavailable and Retry-After header
+// while the container sheds traffic, so load balancers move requests to healthy instances.
+// Parameters:
+//   - next http.Handler
+//   the handler to be wrapped.
+// Returns http.Handler
+func (c *Container) SheddingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.ShouldShedLoad() {
+			w.Header().Set("Retry-After", strconv.Itoa(c.sheddingRetryAfter))
+			http.Error(w, "Service is degraded", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
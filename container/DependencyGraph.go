@@ -0,0 +1,139 @@
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+// Formats of the exported dependency graph.
+const (
+	DependencyGraphDot  = "dot"
+	DependencyGraphJson = "json"
+)
+
+// Component in the dependency graph. Missing nodes stand for declared dependencies
+// that no component satisfies.
+type DependencyGraphNode struct {
+	Id      string `json:"id"`
+	Missing bool   `json:"missing,omitempty"`
+}
+
+// Reference from a component to a component that satisfies its declared dependency.
+type DependencyGraphEdge struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Dependency string `json:"dependency"`
+}
+
+// Graph of references between components of the container.
+type DependencyGraph struct {
+	Nodes []*DependencyGraphNode `json:"nodes"`
+	Edges []*DependencyGraphEdge `json:"edges"`
+}
+
+// Gets the graph of references between components. Edges are built from dependencies declared
+// in "depends_on" lists and "dependencies" sections used by dependency resolvers (see GetWiringReport).
+// Returns *DependencyGraph
+// the dependency graph or nil if the container is not opened.
+func (c *Container) GetDependencyGraph() *DependencyGraph {
+	references := c.getReferences()
+	if references == nil {
+		return nil
+	}
+
+	graph := &DependencyGraph{
+		Nodes: []*DependencyGraphNode{},
+		Edges: []*DependencyGraphEdge{},
+	}
+	nodes := map[string]bool{}
+	addNode := func(id string, missing bool) {
+		if !nodes[id] {
+			nodes[id] = true
+			graph.Nodes = append(graph.Nodes, &DependencyGraphNode{Id: id, Missing: missing})
+		}
+	}
+
+	for _, locator := range references.GetAllLocators() {
+		addNode(cconv.StringConverter.ToString(locator), false)
+	}
+
+	for _, wiring := range references.GetWiringReport().Dependencies {
+		if len(wiring.Resolved) == 0 {
+			addNode(wiring.Locator, true)
+			graph.Edges = append(graph.Edges, &DependencyGraphEdge{
+				From: wiring.Component, To: wiring.Locator, Dependency: wiring.Dependency,
+			})
+			continue
+		}
+		for _, resolved := range wiring.Resolved {
+			graph.Edges = append(graph.Edges, &DependencyGraphEdge{
+				From: wiring.Component, To: resolved, Dependency: wiring.Dependency,
+			})
+		}
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].Id < graph.Nodes[j].Id
+	})
+	return graph
+}
+
+// Exports the graph of references between components to render the wiring of the container
+// in documentation or to debug missing references visually. Missing dependencies are drawn dashed in DOT.
+//
+// Example
+//   data, err := container.ExportDependencyGraph(DependencyGraphDot)
+//   ioutil.WriteFile("wiring.dot", data, 0644)
+//   // dot -Tsvg wiring.dot -o wiring.svg
+// Parameters:
+//   - format string
+//   the format of the graph: "dot" for Graphviz or "json".
+// Returns []byte, error
+// the exported graph, InvalidStateError when the container is not opened
+// and BadRequestError when the format is not supported.
+func (c *Container) ExportDependencyGraph(format string) ([]byte, error) {
+	graph := c.GetDependencyGraph()
+	if graph == nil {
+		return nil, cerr.NewInvalidStateError(
+			"", "NOT_OPENED", "Container is not opened",
+		)
+	}
+
+	switch strings.ToLower(format) {
+	case DependencyGraphJson:
+		return json.MarshalIndent(graph, "", "  ")
+	case DependencyGraphDot:
+		return graph.toDot(c.info.Name), nil
+	default:
+		return nil, cerr.NewBadRequestError(
+			"", "UNSUPPORTED_FORMAT", "Dependency graph format "+format+" is not supported",
+		).WithDetails("format", format)
+	}
+}
+
+// Writes the graph in Graphviz DOT language.
+func (c *DependencyGraph) toDot(name string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("digraph " + strconv.Quote(name) + " {\n")
+	buffer.WriteString("  rankdir=LR;\n")
+	buffer.WriteString("  node [shape=box];\n")
+	for _, node := range c.Nodes {
+		buffer.WriteString("  " + strconv.Quote(node.Id))
+		if node.Missing {
+			buffer.WriteString(" [style=dashed, color=red]")
+		}
+		buffer.WriteString(";\n")
+	}
+	for _, edge := range c.Edges {
+		buffer.WriteString("  " + strconv.Quote(edge.From) + " -> " + strconv.Quote(edge.To) +
+			" [label=" + strconv.Quote(edge.Dependency) + "];\n")
+	}
+	buffer.WriteString("}\n")
+	return buffer.Bytes()
+}
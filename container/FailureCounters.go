@@ -0,0 +1,74 @@
+package container
+
+import (
+	"strings"
+
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-components-go/count"
+)
+
+// Categories of component failures reported to counters.
+const (
+	FailureConfig     = "config"
+	FailureConnection = "connection"
+	FailureTimeout    = "timeout"
+	FailureInternal   = "internal"
+)
+
+// Gets the failure category of the error.
+// Parameters:
+//   - err error
+//   an error returned by a component.
+//   - timedOut bool
+//   true if the operation was interrupted by timeout.
+// Returns string
+// one of config, connection, timeout or internal categories.
+func GetFailureCategory(err error, timedOut bool) string {
+	if timedOut {
+		return FailureTimeout
+	}
+
+	appErr, ok := err.(*cerr.ApplicationError)
+	if !ok {
+		return FailureInternal
+	}
+
+	if strings.Contains(strings.ToUpper(appErr.Code), "TIMEOUT") {
+		return FailureTimeout
+	}
+
+	switch appErr.Category {
+	case "Misconfiguration":
+		return FailureConfig
+	case "NoResponse":
+		return FailureConnection
+	default:
+		return FailureInternal
+	}
+}
+
+// Increments counters of component failures. The counter "container.<stage>_failures.<category>"
+// counts all failures and the counter with the component descriptor appended counts failures of the component.
+// Parameters:
+//   - stage string
+//   the lifecycle stage: "open" or "close".
+//   - locator interface{}
+//   a locator of the failed component or nil if it is unknown.
+//   - err error
+//   the component error.
+//   - timedOut bool
+//   true if the operation was interrupted by timeout.
+func (c *Container) countFailure(stage string, locator interface{}, err error, timedOut bool) {
+	references := c.getReferences()
+	if references == nil {
+		return
+	}
+
+	counters := count.NewCompositeCountersFromReferences(references)
+	name := "container." + stage + "_failures." + GetFailureCategory(err, timedOut)
+	counters.IncrementOne(name)
+	if locator != nil {
+		counters.IncrementOne(name + "." + cconv.StringConverter.ToString(locator))
+	}
+}
@@ -0,0 +1,74 @@
+package container
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+/*
+Interface for components that report the number of goroutines they have spawned.
+The container uses it to attribute goroutine leaks to a specific component.
+
+see
+GoroutineTracker
+Container.GetGoroutineCounts
+*/
+type IGoroutineAccountable interface {
+	// Gets the number of goroutines currently running on behalf of the component.
+	GoroutineCount() int64
+}
+
+/*
+Helper that counts goroutines started by a component. Components can embed it
+to implement IGoroutineAccountable and start their background work through Go method.
+
+Example
+  type MyComponent struct {
+	  *container.GoroutineTracker
+  }
+
+  func (c *MyComponent) Open(correlationId string) error {
+	  c.Go(func() {
+		  // Background work
+	  })
+	  return nil
+  }
+*/
+type GoroutineTracker struct {
+	count int64
+	wait  sync.WaitGroup
+}
+
+// Creates a new instance of the goroutine tracker.
+// Returns *GoroutineTracker
+func NewGoroutineTracker() *GoroutineTracker {
+	return &GoroutineTracker{}
+}
+
+// Starts the function in a new goroutine and accounts it until the function returns.
+// Parameters:
+//   - fn func()
+//   a function to run.
+func (c *GoroutineTracker) Go(fn func()) {
+	atomic.AddInt64(&c.count, 1)
+	c.wait.Add(1)
+
+	go func() {
+		defer func() {
+			atomic.AddInt64(&c.count, -1)
+			c.wait.Done()
+		}()
+		fn()
+	}()
+}
+
+// Waits until all tracked goroutines complete.
+func (c *GoroutineTracker) Wait() {
+	c.wait.Wait()
+}
+
+// Gets the number of tracked goroutines that are still running.
+// Returns int64
+func (c *GoroutineTracker) GoroutineCount() int64 {
+	return atomic.LoadInt64(&c.count)
+}
@@ -0,0 +1,53 @@
+package container
+
+import (
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+)
+
+// Checks if the container serves requests: it is opened or applies updated configuration
+// and doesn't shed traffic.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns bool
+func (c *Container) IsServing(correlationId string) bool {
+	state := c.GetState()
+	return (state == StateOpen || state == StateReloading) && !c.ShouldShedLoad()
+}
+
+// Gets health of created components. Dormant components of groups and schedules are skipped.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns map[string]bool
+// health of components indexed by their locators.
+func (c *Container) GetComponentHealth(correlationId string) map[string]bool {
+	result := map[string]bool{}
+	references := c.getReferences()
+	if references == nil {
+		return result
+	}
+
+	for _, component := range references.GetAll() {
+		if references.Runner.IsExcluded(component) {
+			continue
+		}
+		name := cconv.StringConverter.ToString(references.GetComponentLocator(component))
+		result[name] = c.isComponentHealthy(correlationId, name, component)
+	}
+	return result
+}
+
+// Reports the container health to components, like health services, without exposing
+// the container itself, so it isn't opened and closed as a component.
+type containerHealthSource struct {
+	container *Container
+}
+
+func (c *containerHealthSource) IsServing(correlationId string) bool {
+	return c.container.IsServing(correlationId)
+}
+
+func (c *containerHealthSource) GetComponentHealth(correlationId string) map[string]bool {
+	return c.container.GetComponentHealth(correlationId)
+}
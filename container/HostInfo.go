@@ -0,0 +1,138 @@
+package container
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+// Names of configuration parameters with resolved host addresses.
+// They are added to container parameters unless they are set explicitly.
+const (
+	// Name of the host.
+	HostNameParameter = "HOST_NAME"
+	// Fully qualified domain name of the host.
+	HostFqdnParameter = "HOST_FQDN"
+	// Primary IP address used for outgoing connections.
+	HostIpParameter = "HOST_IP"
+	// Comma-separated list of all non-loopback IP addresses.
+	HostIpsParameter = "HOST_IPS"
+)
+
+// Time to wait for DNS when the fully qualified domain name is resolved.
+const hostLookupTimeout = time.Second
+
+/*
+Resolves name and addresses of the host the container runs on, so configurations
+can advertise correct callback addresses, for instance in discovery registrations.
+
+Resolved values are available as parameters for configuration templates:
+  - HOST_NAME: name of the host
+  - HOST_FQDN: fully qualified domain name (defaults to HOST_NAME when it cannot be resolved)
+  - HOST_IP: primary IP address used for outgoing connections
+  - HOST_IPS: comma-separated list of all non-loopback IP addresses
+
+and as ContextInfo properties host.name, host.fqdn, host.ip and host.ips.
+
+Example
+  - descriptor: "pip-services:discovery:memory:default:1.0"
+    myservice: "host={{HOST_IP}};port=8080"
+*/
+type HostInfo struct {
+	Name string
+	Fqdn string
+	Ip   string
+	Ips  []string
+}
+
+// Resolves name and addresses of the current host.
+// Values that cannot be resolved are left empty.
+// Returns *HostInfo
+func ResolveHostInfo() *HostInfo {
+	c := &HostInfo{}
+
+	c.Name, _ = os.Hostname()
+	c.Fqdn = resolveFqdn(c.Name)
+	c.Ips = resolveIps()
+	c.Ip = resolvePrimaryIp()
+	if c.Ip == "" && len(c.Ips) > 0 {
+		c.Ip = c.Ips[0]
+	}
+
+	return c
+}
+
+// Gets host information as configuration parameters.
+// Returns *cconfig.ConfigParams
+func (c *HostInfo) GetParameters() *cconfig.ConfigParams {
+	return cconfig.NewConfigParamsFromTuples(
+		HostNameParameter, c.Name,
+		HostFqdnParameter, c.Fqdn,
+		HostIpParameter, c.Ip,
+		HostIpsParameter, strings.Join(c.Ips, ","),
+	)
+}
+
+// Gets host information as context properties.
+// Returns map[string]string
+func (c *HostInfo) GetProperties() map[string]string {
+	return map[string]string{
+		"host.name": c.Name,
+		"host.fqdn": c.Fqdn,
+		"host.ip":   c.Ip,
+		"host.ips":  strings.Join(c.Ips, ","),
+	}
+}
+
+func resolveFqdn(hostname string) string {
+	if hostname == "" {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hostLookupTimeout)
+	defer cancel()
+
+	cname, err := net.DefaultResolver.LookupCNAME(ctx, hostname)
+	if err != nil || cname == "" {
+		return hostname
+	}
+	return strings.TrimSuffix(cname, ".")
+}
+
+// Finds the address of the interface used for outgoing connections.
+// Dialing UDP doesn't send any packets, it only selects the route.
+func resolvePrimaryIp() string {
+	conn, err := net.Dial("udp", "8.8.8.8:80")
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+
+	address, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return ""
+	}
+	return address.IP.String()
+}
+
+func resolveIps() []string {
+	ips := []string{}
+
+	addresses, err := net.InterfaceAddrs()
+	if err != nil {
+		return ips
+	}
+
+	for _, address := range addresses {
+		network, ok := address.(*net.IPNet)
+		if !ok || network.IP.IsLoopback() || network.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		ips = append(ips, network.IP.String())
+	}
+	return ips
+}
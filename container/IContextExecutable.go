@@ -0,0 +1,29 @@
+package container
+
+import (
+	"context"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/run"
+)
+
+/*
+Interface for executable components that accept a context, so long-running executions
+can be cancelled when the command times out or the process is interrupted.
+When a component implements both IContextExecutable and IExecutable, the container calls ExecuteWithContext.
+
+see
+CommandDispatcher
+*/
+type IContextExecutable interface {
+	// Executes the component with arguments and receives execution result.
+	// Parameters:
+	//   - ctx context.Context
+	//   a context with the execution deadline.
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	//   - args *run.Parameters
+	//   execution arguments.
+	// Returns interface{}, error
+	// the execution result or error.
+	ExecuteWithContext(ctx context.Context, correlationId string, args *run.Parameters) (interface{}, error)
+}
@@ -0,0 +1,30 @@
+package container
+
+/*
+Interface for components that keep small pieces of state that shall survive restarts,
+for instance consumer offsets or circuit breaker states.
+
+The container saves the state into the configured state store before components are closed
+and restores it after references are set and before components are opened.
+
+see
+StateStore
+*/
+type IStateful interface {
+	// Gets the current state of the component to be persisted.
+	// Parameters:
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	// Returns []byte, error
+	// the state blob or nil if there is nothing to save.
+	SaveState(correlationId string) ([]byte, error)
+
+	// Restores the state of the component saved before the last restart.
+	// Parameters:
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	//   - state []byte
+	//   the previously saved state blob.
+	// Returns error
+	RestoreState(correlationId string, state []byte) error
+}
@@ -0,0 +1,16 @@
+package container
+
+/*
+Interface for factories and components that declare the library they come from.
+The container uses it at startup to detect incompatible major versions of the same library.
+
+see
+VersionChecker
+*/
+type IVersioned interface {
+	// Gets the module path of the library, for instance "github.com/pip-services3-go/pip-services3-rpc-go".
+	GetLibraryName() string
+
+	// Gets the semantic version of the library, for instance "1.5.2".
+	GetLibraryVersion() string
+}
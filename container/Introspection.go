@@ -0,0 +1,256 @@
+package container
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+)
+
+// States of components reported by introspection.
+const (
+	IntrospectionHealthy   = "healthy"
+	IntrospectionUnhealthy = "unhealthy"
+	IntrospectionDormant   = "dormant"
+)
+
+// Introspected state of a single component.
+type ComponentIntrospection struct {
+	Locator    string `json:"locator"`
+	State      string `json:"state"`
+	ConfigHash string `json:"config_hash,omitempty"`
+}
+
+// Introspected state of the container. It contains no configuration values
+// or parameters, so it is safe to expose to observers outside of the service.
+type ContainerIntrospection struct {
+	Name       string                    `json:"name"`
+	ContextId  string                    `json:"context_id"`
+	State      string                    `json:"state"`
+	StartTime  time.Time                 `json:"start_time"`
+	Uptime     int64                     `json:"uptime_ms"`
+	Components []*ComponentIntrospection `json:"components"`
+	Goroutines map[string]int64          `json:"goroutines"`
+}
+
+/*
+Server of a strictly read-only introspection protocol for sidecar observers and service meshes.
+It lists components, their states, hashes of their configurations, goroutine counts (see Container.GetGoroutineCounts)
+and uptime of the container in milliseconds, and never changes the container. Methods other than GET and HEAD are rejected.
+
+Routes
+  - GET /: the container state with all components
+  - GET /components: the list of components
+  - GET /goroutines: goroutine counts of the process and accountable components
+
+Configuration parameters
+introspection:
+  - enabled: true to start the introspection server (default: false)
+  - host: host to listen at (default: 0.0.0.0)
+  - port: port to listen at (default: 8091)
+
+Example
+  - descriptor: "pip-services:container:default:default:1.0"
+    introspection:
+      enabled: true
+      port: 8091
+
+see
+Container.Introspect
+Container.IntrospectionHandler
+*/
+type IntrospectionServer struct {
+	logger  log.ILogger
+	enabled bool
+	host    string
+	port    int
+	server  *http.Server
+	lock    sync.Mutex
+}
+
+// Creates a new instance of the introspection server.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to report failures of the server.
+// Returns *IntrospectionServer
+func NewIntrospectionServer(logger log.ILogger) *IntrospectionServer {
+	return &IntrospectionServer{
+		logger: logger,
+		host:   "0.0.0.0",
+		port:   8091,
+	}
+}
+
+// Sets the logger used to report failures of the server.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to be set.
+func (c *IntrospectionServer) SetLogger(logger log.ILogger) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.logger = logger
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *IntrospectionServer) Configure(config *cconfig.ConfigParams) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.enabled = config.GetAsBooleanWithDefault("introspection.enabled", c.enabled)
+	c.host = config.GetAsStringWithDefault("introspection.host", c.host)
+	c.port = config.GetAsIntegerWithDefault("introspection.port", c.port)
+}
+
+// Checks if the introspection server is enabled.
+// Returns bool
+func (c *IntrospectionServer) IsEnabled() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.enabled
+}
+
+// Starts listening for introspection requests when the server is enabled.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - handler http.Handler
+//   a handler that serves introspection requests.
+// Returns error
+// an error when the server can't listen at the configured address.
+func (c *IntrospectionServer) Start(correlationId string, handler http.Handler) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.enabled || c.server != nil {
+		return nil
+	}
+
+	address := net.JoinHostPort(c.host, strconv.Itoa(c.port))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Handler: handler}
+	c.server = server
+	logger := c.logger
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Error(correlationId, err, "Introspection server at %s failed", address)
+		}
+	}()
+	logger.Debug(correlationId, "Introspection server listens at %s", address)
+	return nil
+}
+
+// Stops the introspection server.
+func (c *IntrospectionServer) Stop() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.server != nil {
+		c.server.Shutdown(context.Background())
+		c.server = nil
+	}
+}
+
+// Gets the read-only state of the container and its components for external observers.
+// Configuration values are never exposed, components report only hashes of their configurations.
+// Returns *ContainerIntrospection
+func (c *Container) Introspect() *ContainerIntrospection {
+	info := c.Info()
+	result := &ContainerIntrospection{
+		Name:       info.Name,
+		ContextId:  info.ContextId,
+		State:      c.GetState().String(),
+		StartTime:  info.StartTime,
+		Uptime:     info.Uptime(),
+		Components: []*ComponentIntrospection{},
+		Goroutines: c.GetGoroutineCounts(),
+	}
+
+	references := c.getReferences()
+	if references == nil {
+		return result
+	}
+
+	for _, component := range references.GetAll() {
+		name := cconv.StringConverter.ToString(references.GetComponentLocator(component))
+		introspection := &ComponentIntrospection{Locator: name, State: IntrospectionDormant}
+		if !references.Runner.IsExcluded(component) {
+			introspection.State = IntrospectionUnhealthy
+			if c.isComponentHealthy("", name, component) {
+				introspection.State = IntrospectionHealthy
+			}
+		}
+		if componentConfig := references.GetComponentConfig(component); componentConfig != nil {
+			introspection.ConfigHash = hashConfig(componentConfig.Config)
+		}
+		result.Components = append(result.Components, introspection)
+	}
+	return result
+}
+
+// Creates HTTP handler of the read-only introspection protocol, so it can be mounted
+// into an existing server. The handler rejects all methods other than GET and HEAD.
+// Returns http.Handler
+func (c *Container) IntrospectionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Introspection is read-only", http.StatusMethodNotAllowed)
+			return
+		}
+
+		introspection := c.Introspect()
+		var result interface{}
+		switch r.URL.Path {
+		case "", "/":
+			result = introspection
+		case "/components":
+			result = introspection.Components
+		case "/goroutines":
+			result = introspection.Goroutines
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	})
+}
+
+// Calculates a short hash of configuration parameters that changes with any of their values.
+// Values detected as secrets (see DetectSecret) are not hashed, since an unsalted hash
+// of a short password can be reversed by brute force. Only their keys are.
+func hashConfig(config *cconfig.ConfigParams) string {
+	if config == nil {
+		return ""
+	}
+
+	keys := config.Keys()
+	sort.Strings(keys)
+	hash := sha256.New()
+	for _, key := range keys {
+		value := config.GetAsString(key)
+		if DetectSecret(key, value) != "" {
+			value = redactedValue
+		}
+		hash.Write([]byte(key + "=" + value + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
@@ -0,0 +1,456 @@
+package container
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/lock"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+/*
+Elects the leader among containers that share a lock component and opens gated components
+only while the container holds the leadership.
+
+Components are gated by "leader_only" parameter in their configuration, which allows
+to run active/passive consumers next to components that serve requests on every instance.
+In standby mode all components, except the lock with its dependencies, loggers, tracers and counters,
+are gated. They are built and referenced, but stay closed until the container acquires the lock.
+That allows to run primary and standby containers of singleton workers and fail over quickly.
+Two containers in one process can share the lock set by Container.SetLeaderLock.
+
+The leader keeps the lock by acquiring it again every interval before the lock expires.
+To prevent other containers from taking the lock while it is acquired again, every attempt
+is made under a guard lock "<key>.guard", so a standby container never sees the lock free
+while the leader is alive. The leader holds the guard for up to ttl, so it cannot expire between
+releasing the lock and acquiring it again, and standby containers hold it for one interval only. When the lock is lost, gated components are closed and the container returns to standby.
+Gated components are opened and closed through the runner of the container references (see RunReferencesDecorator).
+
+Configuration parameters
+  - standby: true to gate all components by the leadership (default: false)
+  - leader_election:
+    - lock: descriptor of the lock component (default: "*:lock:*:*:1.0")
+    - key: a key of the lock (default: "<container name>.leader")
+    - ttl: time in milliseconds to hold the lock (default: 30000)
+    - interval: time in milliseconds to acquire and keep the lock (default: ttl / 3)
+Example
+  - descriptor: "pip-services:container:default:default:1.0"
+    standby: true
+    leader_election:
+      key: "billing-worker"
+      ttl: 15000
+
+  - descriptor: "pip-services:lock:redis:default:1.0"
+    connection:
+      uri: "redis://redis:6379"
+
+  - descriptor: "mygroup:consumer:kafka:default:1.0"
+    leader_only: true
+
+see
+Container.IsLeader
+*/
+type LeaderElection struct {
+	logger     log.ILogger
+	standby    bool
+	locator    *crefer.Descriptor
+	lock       lock.ILock
+	shared     lock.ILock
+	key        string
+	ttl        time.Duration
+	interval   time.Duration
+	runner     *refer.RunReferencesDecorator
+	components []interface{}
+	leader     bool
+	acquiredAt time.Time
+	stop       chan bool
+	running    sync.WaitGroup
+	onChange   func(correlationId string, leader bool)
+	mtx        sync.Mutex
+}
+
+// Default time to hold the leadership lock.
+const DefaultLeaderTtl = 30 * time.Second
+
+// Creates a new instance of the leader election.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to trace leadership changes.
+// Returns *LeaderElection
+func NewLeaderElection(logger log.ILogger) *LeaderElection {
+	return &LeaderElection{
+		logger:  logger,
+		locator: crefer.NewDescriptor("*", "lock", "*", "*", "1.0"),
+		ttl:     DefaultLeaderTtl,
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *LeaderElection) Configure(config *cconfig.ConfigParams) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.standby = config.GetAsBooleanWithDefault("standby", c.standby)
+
+	election := config.GetSection("leader_election")
+	if value := election.GetAsString("lock"); value != "" {
+		if locator, err := crefer.ParseDescriptorFromString(value); err == nil && locator != nil {
+			c.locator = locator
+		}
+	}
+	c.key = election.GetAsStringWithDefault("key", c.key)
+	c.ttl = time.Duration(election.GetAsLongWithDefault("ttl", c.ttl.Milliseconds())) * time.Millisecond
+	c.interval = time.Duration(election.GetAsLongWithDefault("interval", c.interval.Milliseconds())) * time.Millisecond
+}
+
+// Sets the logger used to trace leadership changes.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to be set.
+func (c *LeaderElection) SetLogger(logger log.ILogger) {
+	c.logger = logger
+}
+
+// Sets the lock shared with other containers, for instance with the standby container
+// in the same process. It takes precedence over the lock component from references.
+// Parameters:
+//   - lock lock.ILock
+//   a lock to elect the leader.
+func (c *LeaderElection) SetLock(lock lock.ILock) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.shared = lock
+}
+
+// Sets a callback invoked when the leadership is acquired or lost.
+// Parameters:
+//   - onChange func(correlationId string, leader bool)
+//   a callback to be set.
+func (c *LeaderElection) SetOnChange(onChange func(correlationId string, leader bool)) {
+	c.onChange = onChange
+}
+
+// Checks if the election is required because some components are gated.
+// Returns bool
+func (c *LeaderElection) IsEnabled() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return len(c.components) > 0
+}
+
+// Checks if the container holds the leadership.
+// Returns bool
+func (c *LeaderElection) IsLeader() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.leader
+}
+
+// Finds gated components in the container references and excludes them from automatic opening.
+// Parameters:
+//   - references *refer.ContainerReferences
+//   the container references.
+//   - name string
+//   a name of the container to compose the default lock key.
+func (c *LeaderElection) Register(references *refer.ContainerReferences, name string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.runner = references.Runner
+	c.components = []interface{}{}
+	c.lock = nil
+	c.leader = false
+	if c.key == "" {
+		c.key = name + ".leader"
+	}
+
+	if !c.standby {
+		for _, component := range references.GetAll() {
+			componentConfig := references.GetComponentConfig(component)
+			if componentConfig == nil || componentConfig.Config == nil || references.Runner.IsExcluded(component) {
+				continue
+			}
+			if componentConfig.Config.GetAsBooleanWithDefault("leader_only", false) {
+				c.components = append(c.components, component)
+				references.Runner.Exclude(component)
+			}
+		}
+		return
+	}
+
+	components := references.GetAll()
+	excluded := make([]bool, len(components))
+	for index, component := range components {
+		excluded[index] = references.Runner.IsExcluded(component)
+	}
+
+	// Keep the lock with its dependencies and telemetry opened to run the election
+	infrastructure := references.WithDependencies(references.GetOptional(c.locator))
+	references.ExcludeAllExcept(infrastructure, "logger", "tracer", "counters", "context-info", "factory")
+
+	for index, component := range components {
+		if !excluded[index] && references.Runner.IsExcluded(component) {
+			c.components = append(c.components, component)
+		}
+	}
+}
+
+// Starts acquiring the leadership.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - references crefer.IReferences
+//   references to locate the lock component.
+// Returns error
+// ReferenceError when the lock component is not found.
+func (c *LeaderElection) Start(correlationId string, references crefer.IReferences) error {
+	c.mtx.Lock()
+	if len(c.components) == 0 || c.stop != nil {
+		c.mtx.Unlock()
+		return nil
+	}
+
+	c.lock = c.shared
+	if c.lock == nil {
+		component, err := references.GetOneRequired(c.locator)
+		if err != nil {
+			c.mtx.Unlock()
+			return err
+		}
+		var ok bool
+		if c.lock, ok = component.(lock.ILock); !ok {
+			c.mtx.Unlock()
+			return cerr.NewConfigError(
+				correlationId, "NOT_LOCK", "Component "+c.locator.String()+" is not a lock",
+			).WithDetails("locator", c.locator.String())
+		}
+	}
+
+	interval := c.interval
+	if interval <= 0 {
+		interval = c.ttl / 3
+	}
+	c.stop = make(chan bool)
+	stop := c.stop
+	// Stop waits for the first check and the loop to finish before releasing the lock
+	c.running.Add(1)
+	c.mtx.Unlock()
+
+	c.logger.Info(correlationId, "Waiting for leadership on %s", c.key)
+	c.check(correlationId)
+	go c.run(correlationId, interval, stop)
+	return nil
+}
+
+func (c *LeaderElection) run(correlationId string, interval time.Duration, stop chan bool) {
+	defer c.running.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.check(correlationId)
+		}
+	}
+}
+
+// Acquires the leadership or keeps it by acquiring the lock again before it expires.
+// Components are opened and closed without holding the mutex, so IsLeader and Stop are not blocked.
+func (c *LeaderElection) check(correlationId string) {
+	c.mtx.Lock()
+	stop := c.stop
+	if stop == nil {
+		c.mtx.Unlock()
+		return
+	}
+	leader := c.leader
+	acquiredAt := c.acquiredAt
+	interval := c.interval
+	if interval <= 0 {
+		interval = c.ttl / 3
+	}
+	c.mtx.Unlock()
+
+	if leader {
+		renewedAt, lost := c.renew(correlationId, stop, acquiredAt, interval)
+		if !lost {
+			c.mtx.Lock()
+			if c.stop == stop && !renewedAt.IsZero() {
+				c.acquiredAt = renewedAt
+			}
+			c.mtx.Unlock()
+			return
+		}
+
+		c.logger.Warn(correlationId, "Lost leadership on %s, closing components", c.key)
+		c.closeComponents(correlationId, c.components)
+		c.setLeader(correlationId, stop, false, time.Time{})
+		return
+	}
+
+	acquiredAt = time.Now()
+	acquired, err := c.acquire(correlationId, interval)
+	if err != nil {
+		c.logger.Error(correlationId, err, "Failed to acquire leadership lock %s", c.key)
+	}
+	if !acquired {
+		return
+	}
+
+	c.logger.Info(correlationId, "Acquired leadership on %s, opening components", c.key)
+	for index, component := range c.components {
+		err = c.runner.OpenComponent(context.Background(), correlationId, component)
+		if err != nil {
+			c.closeComponents(correlationId, c.components[:index])
+			c.lock.ReleaseLock(correlationId, c.key)
+			c.logger.Error(correlationId, err, "Failed to open components after acquiring leadership")
+			return
+		}
+	}
+
+	if !c.setLeader(correlationId, stop, true, acquiredAt) {
+		// The election was stopped while components were opening
+		c.lock.ReleaseLock(correlationId, c.key)
+	}
+}
+
+// Acquires the lock under the guard, so it cannot be taken while the leader acquires it again.
+func (c *LeaderElection) acquire(correlationId string, interval time.Duration) (bool, error) {
+	guard := c.key + ".guard"
+	guarded, err := c.lock.TryAcquireLock(correlationId, guard, interval.Milliseconds())
+	if err != nil || !guarded {
+		return false, err
+	}
+	defer c.lock.ReleaseLock(correlationId, guard)
+
+	return c.lock.TryAcquireLock(correlationId, c.key, c.ttl.Milliseconds())
+}
+
+// Keeps the leadership by acquiring the lock again under the guard before it expires.
+// Returns the time the lock was acquired again or zero time when the guard is busy
+// and the lock is still held, and true when the leadership is lost.
+// The lock is not acquired again once the election is stopped, so Stop releases it for good.
+func (c *LeaderElection) renew(correlationId string, stop chan bool, acquiredAt time.Time,
+	interval time.Duration) (time.Time, bool) {
+	// The expired lock may be held by another container already, so it must not be released
+	remaining := c.ttl - time.Since(acquiredAt)
+	if remaining <= 0 {
+		return time.Time{}, true
+	}
+	if interval < remaining {
+		remaining = interval
+	}
+
+	guard := c.key + ".guard"
+	err := c.lock.AcquireLock(correlationId, guard, c.ttl.Milliseconds(), remaining.Milliseconds())
+	if err != nil {
+		c.logger.Warn(correlationId, "Failed to guard leadership lock %s: %v", c.key, err)
+		return time.Time{}, false
+	}
+	defer c.lock.ReleaseLock(correlationId, guard)
+
+	c.mtx.Lock()
+	stopped := c.stop != stop
+	c.mtx.Unlock()
+	if stopped {
+		return time.Time{}, false
+	}
+
+	renewedAt := time.Now()
+	c.lock.ReleaseLock(correlationId, c.key)
+	acquired, err := c.lock.TryAcquireLock(correlationId, c.key, c.ttl.Milliseconds())
+	if err != nil {
+		c.logger.Error(correlationId, err, "Failed to acquire leadership lock %s", c.key)
+	}
+	if !acquired {
+		return time.Time{}, true
+	}
+	return renewedAt, false
+}
+
+// Sets the leadership and notifies about the change.
+// Returns false when the election was stopped in the meantime.
+func (c *LeaderElection) setLeader(correlationId string, stop chan bool, leader bool, acquiredAt time.Time) bool {
+	c.mtx.Lock()
+	if c.stop != stop {
+		c.mtx.Unlock()
+		return false
+	}
+	c.leader = leader
+	c.acquiredAt = acquiredAt
+	onChange := c.onChange
+	c.mtx.Unlock()
+
+	if onChange != nil {
+		onChange(correlationId, leader)
+	}
+	return true
+}
+
+// Closes components in reverse order, so dependencies are closed last.
+func (c *LeaderElection) closeComponents(correlationId string, components []interface{}) {
+	for index := len(components) - 1; index >= 0; index-- {
+		err := c.runner.CloseComponent(context.Background(), correlationId, components[index])
+		if err != nil {
+			c.logger.Error(correlationId, err, "Failed to close components after losing leadership")
+		}
+	}
+}
+
+// Stops the election and releases the leadership. Opened components
+// are closed together with the rest of the container.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+func (c *LeaderElection) Stop(correlationId string) {
+	c.mtx.Lock()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+	c.mtx.Unlock()
+
+	// Wait for the check in progress, so the lock is not acquired again after it is released
+	c.running.Wait()
+
+	c.mtx.Lock()
+	leader := c.leader
+	lck := c.lock
+	c.leader = false
+	c.mtx.Unlock()
+
+	if leader {
+		lck.ReleaseLock(correlationId, c.key)
+	}
+}
+
+// Checks if the container holds the leadership and runs gated components.
+// Returns bool
+// true if the container is the leader and false if it is in standby or the election is not used.
+func (c *Container) IsLeader() bool {
+	return c.leadership.IsLeader()
+}
+
+// Sets the lock shared with other containers to elect the leader,
+// for instance to run primary and standby containers in one process.
+// Parameters:
+//   - lock lock.ILock
+//   a lock to elect the leader.
+func (c *Container) SetLeaderLock(lock lock.ILock) {
+	c.leadership.SetLock(lock)
+}
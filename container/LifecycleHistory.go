@@ -0,0 +1,228 @@
+package container
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+)
+
+// Names of history events recorded in addition to container events.
+const (
+	// A component failed to open or close.
+	HistoryComponentFailed = "component_failed"
+	// A component was restarted by Container.RestartComponent.
+	HistoryComponentRestarted = "component_restarted"
+)
+
+// Record of a lifecycle event kept in the container history.
+type HistoryEntry struct {
+	Time          time.Time         `json:"time"`
+	Event         string            `json:"event"`
+	CorrelationId string            `json:"correlation_id,omitempty"`
+	Component     string            `json:"component,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	Details       map[string]string `json:"details,omitempty"`
+}
+
+/*
+Bounded in-memory history of container lifecycle events: opening, closing, reloads, degradation,
+leadership changes, failures and restarts of components. When the history is full the oldest
+events are overwritten, so it shows what happened recently in the running process.
+
+Configuration parameters
+  - history:
+    - size: maximum number of kept events, 0 to disable the history (default: 1000)
+
+see
+Container.GetHistory
+Container.HistoryHandler
+*/
+type LifecycleHistory struct {
+	entries []HistoryEntry
+	next    int
+	count   int
+	lock    sync.Mutex
+}
+
+// Creates a new instance of the history.
+// Parameters:
+//   - size int
+//   maximum number of kept events.
+// Returns *LifecycleHistory
+func NewLifecycleHistory(size int) *LifecycleHistory {
+	c := &LifecycleHistory{}
+	c.SetSize(size)
+	return c
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *LifecycleHistory) Configure(config *cconfig.ConfigParams) {
+	c.SetSize(config.GetAsIntegerWithDefault("history.size", c.GetSize()))
+}
+
+// Gets the maximum number of kept events.
+// Returns int
+func (c *LifecycleHistory) GetSize() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.entries)
+}
+
+// Sets the maximum number of kept events. The most recent events are kept when the history shrinks.
+// Parameters:
+//   - size int
+//   maximum number of kept events, 0 to disable the history.
+func (c *LifecycleHistory) SetSize(size int) {
+	if size < 0 {
+		size = 0
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if size == len(c.entries) {
+		return
+	}
+
+	entries := c.getEntries(time.Time{})
+	if len(entries) > size {
+		entries = entries[len(entries)-size:]
+	}
+	c.entries = make([]HistoryEntry, size)
+	copy(c.entries, entries)
+	c.count = len(entries)
+	c.next = 0
+	if size > 0 {
+		c.next = c.count % size
+	}
+}
+
+// Adds the event to the history, overwriting the oldest event when the history is full.
+// Parameters:
+//   - entry HistoryEntry
+//   the event to be added. Zero time is replaced with the current time.
+func (c *LifecycleHistory) Add(entry HistoryEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.entries) == 0 {
+		return
+	}
+	c.entries[c.next] = entry
+	c.next = (c.next + 1) % len(c.entries)
+	if c.count < len(c.entries) {
+		c.count++
+	}
+}
+
+// Gets events that happened after the time, oldest first.
+// Parameters:
+//   - since time.Time
+//   the time to get events after or zero time to get all events.
+// Returns []HistoryEntry
+func (c *LifecycleHistory) GetEntries(since time.Time) []HistoryEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.getEntries(since)
+}
+
+func (c *LifecycleHistory) getEntries(since time.Time) []HistoryEntry {
+	result := []HistoryEntry{}
+	start := c.next - c.count
+	if start < 0 {
+		start += len(c.entries)
+	}
+	for index := 0; index < c.count; index++ {
+		entry := c.entries[(start+index)%len(c.entries)]
+		if entry.Time.After(since) {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// Records failures of components. Transitions of the container itself are recorded from its events.
+func (c *LifecycleHistory) OnOpening(correlationId string, locator interface{}) {}
+
+func (c *LifecycleHistory) OnOpened(correlationId string, locator interface{}) {}
+
+func (c *LifecycleHistory) OnClosing(correlationId string, locator interface{}) {}
+
+func (c *LifecycleHistory) OnClosed(correlationId string, locator interface{}) {}
+
+func (c *LifecycleHistory) OnFailed(correlationId string, locator interface{}, err error) {
+	if locator == nil {
+		return
+	}
+	entry := HistoryEntry{
+		Event:         HistoryComponentFailed,
+		CorrelationId: correlationId,
+		Component:     cconv.StringConverter.ToString(locator),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	c.Add(entry)
+}
+
+// Gets recent lifecycle events of the container and its components, oldest first.
+// Returns []HistoryEntry
+func (c *Container) GetHistory() []HistoryEntry {
+	return c.history.GetEntries(time.Time{})
+}
+
+// Gets lifecycle events of the container and its components that happened
+// after the time, oldest first, for instance within the last hour.
+// Parameters:
+//   - since time.Time
+//   the time to get events after.
+// Returns []HistoryEntry
+func (c *Container) GetHistorySince(since time.Time) []HistoryEntry {
+	return c.history.GetEntries(since)
+}
+
+// Creates HTTP handler that responds with the container history as JSON array,
+// so it can be mounted into an admin or status endpoint. Optional "since" query parameter
+// limits events to a period, for instance "?since=1h".
+// Returns http.Handler
+func (c *Container) HistoryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since := time.Time{}
+		if value := r.URL.Query().Get("since"); value != "" {
+			period, err := time.ParseDuration(value)
+			if err != nil {
+				http.Error(w, "Invalid since period "+value, http.StatusBadRequest)
+				return
+			}
+			since = time.Now().Add(-period)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(c.GetHistorySince(since))
+	})
+}
+
+// Records the container event in the history.
+func (c *Container) recordHistory(correlationId string, event string, component string, err error, details map[string]string) {
+	entry := HistoryEntry{
+		Event:         event,
+		CorrelationId: correlationId,
+		Component:     component,
+		Details:       details,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	c.history.Add(entry)
+}
@@ -0,0 +1,151 @@
+package container
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+/*
+Writes marker files that reflect the container state. They support file-based health checks
+in environments without HTTP probes, such as batch schedulers.
+
+The live file is created when the container starts and is touched periodically while the container runs.
+The ready file is created when the container is opened and removed when it starts closing.
+
+Configuration parameters
+  - markers:
+    - ready: path to the readiness file (default: none)
+    - live: path to the liveness file (default: none)
+    - heartbeat_interval: interval in milliseconds to touch the liveness file (default: 10000)
+Example
+  - descriptor: "pip-services:container:default:default:1.0"
+    markers:
+      ready: /tmp/ready
+      live: /tmp/live
+      heartbeat_interval: 5000
+*/
+type LifecycleMarkers struct {
+	readyPath         string
+	livePath          string
+	heartbeatInterval time.Duration
+	stop              chan bool
+	lock              sync.Mutex
+}
+
+// Creates a new instance of lifecycle markers.
+// Returns *LifecycleMarkers
+func NewLifecycleMarkers() *LifecycleMarkers {
+	return &LifecycleMarkers{
+		heartbeatInterval: 10 * time.Second,
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *LifecycleMarkers) Configure(config *cconfig.ConfigParams) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.readyPath = config.GetAsStringWithDefault("markers.ready", c.readyPath)
+	c.livePath = config.GetAsStringWithDefault("markers.live", c.livePath)
+	interval := config.GetAsLongWithDefault("markers.heartbeat_interval", int64(c.heartbeatInterval/time.Millisecond))
+	c.heartbeatInterval = time.Duration(interval) * time.Millisecond
+}
+
+// Sets paths to marker files.
+// Parameters:
+//   - readyPath string
+//   a path to the readiness file or empty string to disable it.
+//   - livePath string
+//   a path to the liveness file or empty string to disable it.
+//   - heartbeatInterval time.Duration
+//   an interval to touch the liveness file.
+func (c *LifecycleMarkers) SetPaths(readyPath string, livePath string, heartbeatInterval time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.readyPath = readyPath
+	c.livePath = livePath
+	c.heartbeatInterval = heartbeatInterval
+}
+
+// Creates the liveness file and starts touching it periodically.
+func (c *LifecycleMarkers) MarkLive() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.livePath == "" || c.stop != nil {
+		return
+	}
+
+	touchFile(c.livePath)
+
+	if c.heartbeatInterval > 0 {
+		c.stop = make(chan bool)
+		go c.heartbeat(c.livePath, c.heartbeatInterval, c.stop)
+	}
+}
+
+func (c *LifecycleMarkers) heartbeat(path string, interval time.Duration, stop chan bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			touchFile(path)
+		}
+	}
+}
+
+// Creates the readiness file.
+func (c *LifecycleMarkers) MarkReady() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.readyPath != "" {
+		touchFile(c.readyPath)
+	}
+}
+
+// Removes the readiness file.
+func (c *LifecycleMarkers) UnmarkReady() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.readyPath != "" {
+		os.Remove(c.readyPath)
+	}
+}
+
+// Stops the heartbeat and removes all marker files.
+func (c *LifecycleMarkers) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+	if c.readyPath != "" {
+		os.Remove(c.readyPath)
+	}
+	if c.livePath != "" {
+		os.Remove(c.livePath)
+	}
+}
+
+func touchFile(path string) {
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		ioutil.WriteFile(path, []byte(now.UTC().Format(time.RFC3339)), 0644)
+	}
+}
@@ -0,0 +1,176 @@
+package container
+
+import (
+	"strings"
+
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+// Gets the name of the component used in findings.
+func getLintComponentName(componentConfig *config.ComponentConfig) string {
+	if componentConfig.Descriptor != nil {
+		return componentConfig.Descriptor.String()
+	}
+	if componentConfig.Type != nil {
+		return componentConfig.Type.String()
+	}
+	return ""
+}
+
+// Checks that registered factories can create components defined by descriptors.
+type unknownDescriptorRule struct{}
+
+func (c *unknownDescriptorRule) Name() string {
+	return "unknown-descriptor"
+}
+
+func (c *unknownDescriptorRule) Check(context *LintContext) []*LintFinding {
+	result := []*LintFinding{}
+	if context.Factory == nil {
+		return result
+	}
+
+	for _, componentConfig := range context.Config {
+		// Components defined by types are created by reflection
+		if componentConfig.Descriptor == nil {
+			continue
+		}
+		if context.Factory.CanCreate(componentConfig.Descriptor) == nil {
+			result = append(result, &LintFinding{
+				Severity:  LintError,
+				Component: componentConfig.Descriptor.String(),
+				Message:   "No registered factory can create the component",
+			})
+		}
+	}
+	return result
+}
+
+// Checks that components don't share the same descriptor.
+type duplicateDescriptorRule struct{}
+
+func (c *duplicateDescriptorRule) Name() string {
+	return "duplicate-descriptor"
+}
+
+func (c *duplicateDescriptorRule) Check(context *LintContext) []*LintFinding {
+	result := []*LintFinding{}
+	counts := map[string]int{}
+	for _, componentConfig := range context.Config {
+		if componentConfig.Descriptor == nil {
+			continue
+		}
+
+		name := componentConfig.Descriptor.String()
+		counts[name]++
+		if counts[name] == 2 {
+			result = append(result, &LintFinding{
+				Severity:  LintWarning,
+				Component: name,
+				Message:   "Several components have the same descriptor, use different names to tell them apart",
+			})
+		}
+	}
+	return result
+}
+
+// Checks that all passed parameters are referenced in the configuration.
+type unusedParameterRule struct{}
+
+func (c *unusedParameterRule) Name() string {
+	return "unused-parameter"
+}
+
+func (c *unusedParameterRule) Check(context *LintContext) []*LintFinding {
+	result := []*LintFinding{}
+	if context.Parameters == nil {
+		return result
+	}
+
+	referenced := map[string]bool{}
+	for _, parameter := range config.ScanConfigParameters(context.Template, nil) {
+		referenced[parameter.Name] = true
+	}
+
+	for _, name := range context.Parameters.Keys() {
+		// Host parameters are added by the container itself
+		if strings.HasPrefix(name, "HOST_") || referenced[name] {
+			continue
+		}
+		result = append(result, &LintFinding{
+			Severity: LintWarning,
+			Message:  "Parameter " + name + " is not referenced in the configuration",
+		})
+	}
+	return result
+}
+
+// Checks that the configuration has a logger.
+type missingLoggerRule struct{}
+
+func (c *missingLoggerRule) Name() string {
+	return "missing-logger"
+}
+
+func (c *missingLoggerRule) Check(context *LintContext) []*LintFinding {
+	for _, componentConfig := range context.Config {
+		if componentConfig.Descriptor != nil && componentConfig.Descriptor.Type() == "logger" {
+			return []*LintFinding{}
+		}
+	}
+	return []*LintFinding{
+		{
+			Severity: LintWarning,
+			Message:  "Configuration has no logger, messages of components will be lost",
+		},
+	}
+}
+
+// Checks that secrets are passed as parameters or value provider references.
+type plaintextSecretRule struct{}
+
+func (c *plaintextSecretRule) Name() string {
+	return "plaintext-secret"
+}
+
+func (c *plaintextSecretRule) Check(context *LintContext) []*LintFinding {
+	result := []*LintFinding{}
+
+	for _, componentConfig := range context.Config {
+		if componentConfig.Config == nil {
+			continue
+		}
+
+		for _, key := range componentConfig.Config.Keys() {
+			value := componentConfig.Config.GetAsString(key)
+			if value == "" || !c.isLiteral(context, value) {
+				continue
+			}
+
+			isSecret := isSensitiveKey(key)
+			for _, pattern := range secretValuePatterns {
+				isSecret = isSecret || pattern.MatchString(value)
+			}
+			if isSecret {
+				result = append(result, &LintFinding{
+					Severity:  LintError,
+					Component: getLintComponentName(componentConfig),
+					Message:   "Value of " + key + " looks like a secret written in plain text, pass it as a parameter",
+				})
+			}
+		}
+	}
+	return result
+}
+
+// Checks if the value is written in the template as is, not substituted from a parameter
+// or referenced from a value provider.
+func (c *plaintextSecretRule) isLiteral(context *LintContext, value string) bool {
+	if strings.Contains(value, "{{") {
+		return false
+	}
+	if context.ValueProviders != nil && context.ValueProviders.IsReference(value) {
+		return false
+	}
+	return strings.Contains(context.Template, value)
+}
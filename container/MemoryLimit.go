@@ -0,0 +1,12 @@
+//go:build go1.19
+// +build go1.19
+
+package container
+
+import "runtime/debug"
+
+// Sets the soft memory limit of the Go runtime.
+func setMemoryLimit(limit int64) bool {
+	debug.SetMemoryLimit(limit)
+	return true
+}
@@ -0,0 +1,9 @@
+//go:build !go1.19
+// +build !go1.19
+
+package container
+
+// Memory limit is not supported by Go runtime before 1.19.
+func setMemoryLimit(limit int64) bool {
+	return false
+}
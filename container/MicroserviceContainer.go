@@ -0,0 +1,67 @@
+package container
+
+import (
+	"os"
+
+	cbuild "github.com/pip-services3-go/pip-services3-components-go/build"
+)
+
+// Conventional configuration of generated microservices: console logger, log counters,
+// gRPC health service (see health package) and read-only introspection server as the status endpoint.
+// It is used when the service has no configuration file.
+const MicroserviceConfig = `
+- descriptor: "pip-services:container:default:default:1.0"
+  introspection:
+    enabled: true
+
+- descriptor: "pip-services:logger:console:default:1.0"
+  level: info
+
+- descriptor: "pip-services:counters:log:default:1.0"
+
+- descriptor: "pip-services:health-service:grpc:default:1.0"
+`
+
+// Creates a process container assembled by conventions of generated microservices,
+// so the service only lists its modules. Factories of the modules are registered in the container.
+// When the configuration file "./config/config.yml" is missing, the container runs
+// with the conventional configuration (see MicroserviceConfig).
+//
+// Example
+//   func main() {
+//       container.NewMicroserviceContainer("beacons", NewBeaconsFactory()).Run(os.Args)
+//   }
+// Parameters:
+//   - name string
+//   a container name (accessible via ContextInfo)
+//   - modules ...cbuild.IFactory
+//   factories of components of the service.
+// Returns *ProcessContainer
+func NewMicroserviceContainer(name string, modules ...cbuild.IFactory) *ProcessContainer {
+	c := NewProcessContainer(name, "")
+	for _, module := range modules {
+		if module != nil {
+			c.AddFactory(module)
+		}
+	}
+	c.SetDefaultConfig([]byte(MicroserviceConfig))
+	return c
+}
+
+// Sets YAML configuration used when the configuration file was not set with command line
+// arguments and the default file doesn't exist.
+// Parameters:
+//   - data []byte
+//   the default configuration.
+func (c *ProcessContainer) SetDefaultConfig(data []byte) {
+	c.defaultConfig = data
+}
+
+// Checks if the default configuration shall be used instead of the configuration file.
+func (c *ProcessContainer) useDefaultConfig(path string) bool {
+	if c.defaultConfig == nil || path != c.configPath {
+		return false
+	}
+	_, err := os.Stat(path)
+	return os.IsNotExist(err)
+}
@@ -0,0 +1,67 @@
+package container
+
+import (
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+// Checks if the container runs in degraded mode because some non-critical components
+// failed to open with "fail_fast" option disabled, or were quarantined by the supervisor.
+// Returns bool
+// true if the container is degraded and false otherwise.
+func (c *Container) IsDegraded() bool {
+	return len(c.GetOpenFailures()) > 0 || c.supervisor.IsDegraded()
+}
+
+// Gets failures of non-critical components that were skipped when the container was opened
+// with "fail_fast" option disabled.
+// Returns []*refer.OpenFailure
+// the failures or empty list if all components were opened.
+func (c *Container) GetOpenFailures() []*refer.OpenFailure {
+	references := c.getReferences()
+	if references == nil {
+		return []*refer.OpenFailure{}
+	}
+	return references.Runner.GetOpenFailures()
+}
+
+// Gets progress of opening components: how many components and batches are opened and how long it took.
+// It is updated while the container is being opened.
+// Returns refer.OpenProgress
+func (c *Container) GetOpenProgress() refer.OpenProgress {
+	references := c.getReferences()
+	if references == nil {
+		return refer.OpenProgress{}
+	}
+	return references.Runner.GetOpenProgress()
+}
+
+// Checks if the component is marked with "critical" parameter, so its failure stops opening
+// the container even when "fail_fast" option is disabled.
+func (c *Container) isCriticalComponent(component interface{}) bool {
+	references := c.getReferences()
+	if references == nil {
+		return false
+	}
+	componentConfig := references.GetComponentConfig(component)
+	return componentConfig != nil && componentConfig.Config != nil &&
+		componentConfig.Config.GetAsBooleanWithDefault("critical", false)
+}
+
+// Logs failures of skipped components and notifies that the container runs in degraded mode.
+func (c *Container) reportOpenFailures(correlationId string) {
+	failures := c.GetOpenFailures()
+	if len(failures) == 0 {
+		return
+	}
+
+	names := []string{}
+	for _, failure := range failures {
+		name := cconv.StringConverter.ToString(failure.Locator)
+		names = append(names, name)
+		c.logger.Error(correlationId, failure.Error, "Component %s failed to open and was skipped", name)
+	}
+
+	c.logger.Warn(correlationId, "Container %s runs in degraded mode", c.info.Name)
+	c.notify(correlationId, EventDegraded, "components", names)
+}
@@ -1,6 +1,8 @@
 package container
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -10,17 +12,43 @@ import (
 
 	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
 	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
 	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-commons-go/run"
 	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
 )
 
 /*
 Inversion of control (IoC) container that runs as a system process. It processes command line arguments and handles unhandled exceptions and Ctrl-C signal to gracefully shutdown the container.
 
 Command line arguments
-  --config / -c path to JSON or YAML file with container configuration (default: "./config/config.yml")
+  --config / -c path to JSON, YAML or TOML file, directory with such files, HTTP(S) URL,
+    consul://<host>:<port>/<key> or etcd://<host>:<port>/<key> URI with container configuration
+    or "-" to read YAML or JSON configuration from stdin
+    (default: "./config/config.yml" or the default configuration when the file is missing, see SetDefaultConfig).
+    When ENVIRONMENT parameter is set, for instance to "prod",
+    the overlay file "./config/config.prod.yml" is merged on top of the configuration
   --param / --params / -p value(s) to parameterize the container configuration
+  --param-set / -s name of a parameter set to read from the parameters file. Values of the set
+    are overridden by environment variables and --param values (see ReadParameterSetFromFile)
+  --param-file path to JSON, YAML or TOML file with parameter sets (default: "./config/parameters.yml")
+  --env-file path to dotenv (.env) file with parameters. Its values are overridden by environment variables
+    and --param values and override values of the parameter set (default: none, see SetDotenvPath)
+  --test / -t runs the container in test mode, swapping component implementations with fakes listed
+    in the test overrides file (default: "./config/test_overrides.yml", see TestOverrides)
   --help / -h prints the container usage help
+  exec <command> --param <key>=<value> opens components required by the command, executes it,
+    prints the result as JSON and exits (see CommandDispatcher)
+  params lists parameters referenced in the configuration as {{NAME}}, shows which of them
+    are required and which are set, and exits with code 1 when required parameters are missing
+  lint checks the configuration with lint rules (see ConfigLinter), prints findings as
+    "<severity> [<rule>] <component>: <message>" lines and exits with code 1 when errors are found.
+    Only parameters passed with --param and --param-set are used, environment variables are ignored
+
+When the process terminates because of a fatal error, in addition to the log record
+it writes a single-line JSON object to stderr:
+  {"category":"Internal","code":"REF_ERROR","correlation_id":"...","descriptor":"...","message":"..."}
 see
 Container
 
@@ -32,15 +60,21 @@ Example
 */
 type ProcessContainer struct {
 	Container
-	configPath string
+	configPath    string
+	paramPath     string
+	overridesPath string
+	dotenvPath    string
+	defaultConfig []byte
 }
 
 // Creates a new empty instance of the container.
 // Returns ProcessContainer
 func NewEmptyProcessContainer() *ProcessContainer {
 	c := &ProcessContainer{
-		Container:  *NewEmptyContainer(),
-		configPath: "./config/config.yml",
+		Container:     *NewEmptyContainer(),
+		configPath:    "./config/config.yml",
+		paramPath:     "./config/parameters.yml",
+		overridesPath: "./config/test_overrides.yml",
 	}
 	c.SetLogger(log.NewConsoleLogger())
 	return c
@@ -55,8 +89,10 @@ func NewEmptyProcessContainer() *ProcessContainer {
 // Returns ProcessContainer
 func NewProcessContainer(name string, description string) *ProcessContainer {
 	c := &ProcessContainer{
-		Container:  *NewContainer(name, description),
-		configPath: "./config/config.yml",
+		Container:     *NewContainer(name, description),
+		configPath:    "./config/config.yml",
+		paramPath:     "./config/parameters.yml",
+		overridesPath: "./config/test_overrides.yml",
 	}
 	c.SetLogger(log.NewConsoleLogger())
 	return c
@@ -74,8 +110,10 @@ func NewProcessContainer(name string, description string) *ProcessContainer {
 func InheritProcessContainer(name string, description string,
 	referenceable crefer.IReferenceable) *ProcessContainer {
 	c := &ProcessContainer{
-		Container:  *InheritContainer(name, description, referenceable),
-		configPath: "./config/config.yml",
+		Container:     *InheritContainer(name, description, referenceable),
+		configPath:    "./config/config.yml",
+		paramPath:     "./config/parameters.yml",
+		overridesPath: "./config/test_overrides.yml",
 	}
 	c.SetLogger(log.NewConsoleLogger())
 	return c
@@ -86,12 +124,40 @@ func (c *ProcessContainer) SetConfigPath(configPath string) {
 	c.configPath = configPath
 }
 
+// Set path for the file with parameter sets
+func (c *ProcessContainer) SetParamPath(paramPath string) {
+	c.paramPath = paramPath
+}
+
+// Set path for the file with test overrides loaded in test mode
+func (c *ProcessContainer) SetTestOverridesPath(overridesPath string) {
+	c.overridesPath = overridesPath
+}
+
+// Set path for the dotenv (.env) file with parameters loaded when it exists,
+// for instance "./.env" for local development
+func (c *ProcessContainer) SetDotenvPath(dotenvPath string) {
+	c.dotenvPath = dotenvPath
+}
+
+// Gets a value of the command line option or empty string when the option is not set.
+func (c *ProcessContainer) getOption(args []string, names ...string) string {
+	for index := 0; index < len(args)-1; index++ {
+		for _, name := range names {
+			if args[index] == name && !strings.HasPrefix(args[index+1], "-") {
+				return args[index+1]
+			}
+		}
+	}
+	return ""
+}
+
 func (c *ProcessContainer) getConfigPath(args []string) string {
 	for index, arg := range args {
 		nextArg := ""
 		if index < len(args)-1 {
 			nextArg = args[index+1]
-			if strings.HasPrefix(nextArg, "-") {
+			if strings.HasPrefix(nextArg, "-") && nextArg != config.StdinPath {
 				nextArg = ""
 			}
 		}
@@ -104,7 +170,85 @@ func (c *ProcessContainer) getConfigPath(args []string) string {
 	return c.configPath
 }
 
-func (c *ProcessContainer) getParameters(args []string) *cconfig.ConfigParams {
+func (c *ProcessContainer) getParameters(correlationId string, args []string) (*cconfig.ConfigParams, error) {
+	parameters := cconfig.NewConfigParamsFromString(c.getParamLine(args))
+
+	dotenv, err := c.getDotenv(correlationId, args)
+	if err != nil {
+		return nil, err
+	}
+	if dotenv != nil {
+		parameters = dotenv.Override(parameters)
+	}
+
+	for _, e := range os.Environ() {
+		env := strings.Split(e, "=")
+		parameters.SetAsObject(env[0], env[1])
+	}
+
+	return c.overrideParameterSet(correlationId, args, parameters)
+}
+
+// Reads the dotenv file set with --env-file argument or SetDotenvPath.
+// The file set with the argument must exist, the file set in code is skipped when it is missing.
+func (c *ProcessContainer) getDotenv(correlationId string, args []string) (*cconfig.ConfigParams, error) {
+	dotenvPath := c.getOption(args, "--env-file")
+	if dotenvPath == "" {
+		if c.dotenvPath == "" {
+			return nil, nil
+		}
+		if _, err := os.Stat(c.dotenvPath); err != nil {
+			return nil, nil
+		}
+		dotenvPath = c.dotenvPath
+	}
+	return config.ContainerConfigReader.ReadDotenvFromFile(correlationId, dotenvPath)
+}
+
+// Gets parameters passed with --param and --param-set arguments, without environment variables.
+func (c *ProcessContainer) getExplicitParameters(correlationId string, args []string) (*cconfig.ConfigParams, error) {
+	parameters := cconfig.NewConfigParamsFromString(c.getParamLine(args))
+	return c.overrideParameterSet(correlationId, args, parameters)
+}
+
+// Reads the parameter set chosen with --param-set argument and overrides it with the parameters.
+func (c *ProcessContainer) overrideParameterSet(correlationId string, args []string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, error) {
+	setName := c.getOption(args, "--param-set", "-s")
+	if setName == "" {
+		return parameters, nil
+	}
+
+	paramPath := c.getOption(args, "--param-file")
+	if paramPath == "" {
+		paramPath = c.paramPath
+	}
+
+	set, err := config.ContainerConfigReader.ReadParameterSetFromFile(correlationId, paramPath, setName)
+	if err != nil {
+		return nil, err
+	}
+	return set.Override(parameters), nil
+}
+
+// Reads test overrides when the container runs in test mode.
+func (c *ProcessContainer) getTestOverrides(correlationId string, args []string) (config.TestOverrides, error) {
+	testMode := false
+	for _, arg := range args {
+		testMode = testMode || arg == "--test" || arg == "-t"
+	}
+	if !testMode {
+		return nil, nil
+	}
+
+	overridesPath := c.getOption(args, "--test", "-t")
+	if overridesPath == "" {
+		overridesPath = c.overridesPath
+	}
+	return config.ContainerConfigReader.ReadTestOverridesFromFile(correlationId, overridesPath)
+}
+
+func (c *ProcessContainer) getParamLine(args []string) string {
 	line := ""
 
 	for index := 0; index < len(args); index++ {
@@ -128,14 +272,54 @@ func (c *ProcessContainer) getParameters(args []string) *cconfig.ConfigParams {
 		}
 	}
 
-	parameters := cconfig.NewConfigParamsFromString(line)
+	return line
+}
 
-	for _, e := range os.Environ() {
-		env := strings.Split(e, "=")
-		parameters.SetAsObject(env[0], env[1])
+// Finds "exec <command>" in command line arguments. Parameters that follow
+// the command are passed to the command, the rest of arguments are left to the container.
+// Returns the command name or empty string, the command parameters and the container arguments.
+func (c *ProcessContainer) getCommand(args []string) (string, *run.Parameters, []string) {
+	for index, arg := range args {
+		if arg != "exec" || index >= len(args)-1 {
+			continue
+		}
+
+		command := args[index+1]
+		commandArgs := []string{}
+		restArgs := append([]string{}, args[:index]...)
+		for next := index + 2; next < len(args); next++ {
+			arg := args[next]
+			if (arg == "--param" || arg == "--params" || arg == "-p") && next < len(args)-1 {
+				commandArgs = append(commandArgs, arg, args[next+1])
+				next++
+			} else {
+				restArgs = append(restArgs, arg)
+			}
+		}
+
+		config := cconfig.NewConfigParamsFromString(c.getParamLine(commandArgs))
+		return command, run.NewParametersFromConfig(config), restArgs
 	}
 
-	return parameters
+	return "", nil, args
+}
+
+func (c *ProcessContainer) showParams(args []string) bool {
+	for _, arg := range args {
+		if arg == "params" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ProcessContainer) showLint(args []string) bool {
+	for _, arg := range args {
+		if arg == "lint" {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *ProcessContainer) showHelp(args []string) bool {
@@ -149,7 +333,54 @@ func (c *ProcessContainer) showHelp(args []string) bool {
 
 func (c *ProcessContainer) printHelp() {
 	fmt.Println("Pip.Services process container - http://www.github.com/pip-services/pip-services")
-	fmt.Println("run [-h] [-c <config file>] [-p <param>=<value>]*")
+	fmt.Println("run [-h] [-c <config file>] [--param-file <file>] [-s <param set>] [--env-file <file>] [-t [<test overrides file>]] [-p <param>=<value>]*")
+	fmt.Println("run [-c <config file>] [-s <param set>] [-p <param>=<value>]* exec <command> [-p <param>=<value>]*")
+	fmt.Println("run [-c <config file>] [-s <param set>] [-p <param>=<value>]* params")
+	fmt.Println("run [-c <config file>] [-s <param set>] [-p <param>=<value>]* lint")
+}
+
+// Writes a machine-readable description of a fatal error to stderr
+// so wrapper scripts and orchestrators can detect the failure cause without parsing logs.
+// The error is written as a single JSON line with category, code, message, descriptor and correlation_id fields.
+func (c *ProcessContainer) printFatalError(correlationId string, err error) {
+	report := map[string]interface{}{
+		"category":       "Unknown",
+		"code":           "UNKNOWN",
+		"message":        err.Error(),
+		"correlation_id": correlationId,
+	}
+
+	// The application error may be wrapped by the component that returned it
+	var appErr *cerr.ApplicationError
+	if errors.As(err, &appErr) {
+		if appErr.Category != "" {
+			report["category"] = appErr.Category
+		}
+		if appErr.Code != "" {
+			report["code"] = appErr.Code
+		}
+		if appErr.CorrelationId != "" {
+			report["correlation_id"] = appErr.CorrelationId
+		}
+		for _, key := range []string{"descriptor", "locator"} {
+			if value, ok := appErr.Details[key]; ok && value != nil {
+				report["descriptor"] = cconv.StringConverter.ToString(value)
+				break
+			}
+		}
+	}
+
+	data, jsonErr := json.Marshal(report)
+	if jsonErr == nil {
+		fmt.Fprintln(os.Stderr, string(data))
+	}
+}
+
+func (c *ProcessContainer) terminate(correlationId string, err error) {
+	c.Logger().Fatal(correlationId, err, "Process is terminated")
+	c.FlushTelemetry(correlationId)
+	c.printFatalError(correlationId, err)
+	os.Exit(1)
 }
 
 func (c *ProcessContainer) captureErrors(correlationId string) {
@@ -159,15 +390,14 @@ func (c *ProcessContainer) captureErrors(correlationId string) {
 			msg := cconv.StringConverter.ToString(r)
 			err = errors.New(msg)
 		}
-		c.Logger().Fatal(correlationId, err, "Process is terminated")
-		os.Exit(1)
+		c.terminate(correlationId, err)
 	}
 }
 
 func (c *ProcessContainer) captureExit(correlationId string) {
 	c.Logger().Info(correlationId, "Press Control-C to stop the microservice...")
 
-	ch := make(chan os.Signal)
+	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
@@ -192,27 +422,146 @@ func (c *ProcessContainer) Run(args []string) {
 		return
 	}
 
+	command, commandArgs, args := c.getCommand(args)
+
 	correlationId := c.Info().Name
 	path := c.getConfigPath(args)
-	parameters := c.getParameters(args)
+	parameters, err := c.getParameters(correlationId, args)
+	if err != nil {
+		c.terminate(correlationId, err)
+		return
+	}
+
+	if command == "" && c.showParams(args) {
+		c.printParams(correlationId, path, parameters)
+		return
+	}
 
-	err := c.ReadConfigFromFile(correlationId, path, parameters)
+	if command == "" && c.showLint(args) {
+		c.printLint(correlationId, path, args)
+		return
+	}
+
+	if c.useDefaultConfig(path) {
+		err = c.ReadConfigFromBytes(correlationId, c.defaultConfig, ".yml", parameters)
+	} else {
+		err = c.ReadConfigFromFile(correlationId, path, parameters)
+	}
 	if err != nil {
-		c.Logger().Fatal(correlationId, err, "Process is terminated")
-		os.Exit(1)
+		c.terminate(correlationId, err)
+		return
+	}
+
+	overrides, err := c.getTestOverrides(correlationId, args)
+	if err != nil {
+		c.terminate(correlationId, err)
 		return
 	}
+	c.SetTestOverrides(overrides)
 
 	defer c.captureErrors(correlationId)
+
+	if command != "" {
+		c.runCommand(correlationId, command, commandArgs)
+		return
+	}
+
 	c.captureExit(correlationId)
 
 	err = c.Open(correlationId)
 	if err != nil {
-		c.Logger().Fatal(correlationId, err, "Process is terminated")
-		os.Exit(1)
+		c.terminate(correlationId, err)
 		return
 	}
 
 	ch := make(chan bool)
 	<-ch
 }
+
+// Opens components required by the command, executes it, prints the result as JSON and exits.
+func (c *ProcessContainer) runCommand(correlationId string, command string, args *run.Parameters) {
+	c.command = command
+
+	err := c.Open(correlationId)
+	if err != nil {
+		c.terminate(correlationId, err)
+		return
+	}
+
+	// Ctrl-C cancels the execution instead of killing the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	result, err := c.ExecuteCommandWithContext(ctx, correlationId, command, args)
+	stop()
+	c.Close(correlationId)
+	if err != nil {
+		c.terminate(correlationId, err)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		c.terminate(correlationId, err)
+		return
+	}
+	fmt.Println(string(data))
+	os.Exit(0)
+}
+
+// Prints parameters referenced in the configuration file and exits.
+// The process exits with code 1 when some required parameters are not set.
+func (c *ProcessContainer) printParams(correlationId string, path string, parameters *cconfig.ConfigParams) {
+	params, err := config.ContainerConfigReader.ScanParametersFromFile(correlationId, path, parameters)
+	if err != nil {
+		c.terminate(correlationId, err)
+		return
+	}
+
+	missing := 0
+	fmt.Printf("%-32s %-10s %s\n", "PARAMETER", "REQUIRED", "STATUS")
+	for _, param := range params {
+		required := "optional"
+		if param.Required {
+			required = "required"
+		}
+		status := "set"
+		if !param.Set {
+			status = "not set"
+			if param.Required {
+				status = "missing"
+				missing++
+			}
+		}
+		fmt.Printf("%-32s %-10s %s\n", param.Name, required, status)
+	}
+
+	if missing > 0 {
+		fmt.Printf("%d required parameter(s) are missing\n", missing)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// Checks the configuration file with lint rules, prints findings and exits.
+// The process exits with code 1 when some findings are errors.
+func (c *ProcessContainer) printLint(correlationId string, path string, args []string) {
+	parameters, err := c.getExplicitParameters(correlationId, args)
+	if err != nil {
+		c.terminate(correlationId, err)
+		return
+	}
+
+	findings, err := c.LintConfig(correlationId, path, parameters)
+	if err != nil {
+		c.terminate(correlationId, err)
+		return
+	}
+
+	for _, finding := range findings {
+		fmt.Println(finding.String())
+	}
+
+	if HasLintErrors(findings) {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+// +build !windows
+
+package container
+
+import (
+	"syscall"
+	"time"
+)
+
+// Gets CPU time consumed by the process in user and system modes.
+func processCpuTime() time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
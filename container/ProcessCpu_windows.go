@@ -0,0 +1,26 @@
+//go:build windows
+// +build windows
+
+package container
+
+import (
+	"syscall"
+	"time"
+)
+
+// Gets CPU time consumed by the process in user and system modes.
+func processCpuTime() time.Duration {
+	handle, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return 0
+	}
+
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(handle, &creation, &exit, &kernel, &user); err != nil {
+		return 0
+	}
+	// Filetime counts 100-nanosecond intervals
+	ticks := int64(kernel.HighDateTime)<<32 + int64(kernel.LowDateTime) +
+		int64(user.HighDateTime)<<32 + int64(user.LowDateTime)
+	return time.Duration(ticks * 100)
+}
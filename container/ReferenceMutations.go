@@ -0,0 +1,116 @@
+package container
+
+import (
+	"sort"
+	"strings"
+
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-commons-go/run"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+// Name of the event sent to subscribers when references of the running container are mutated
+// after the initial build. Event parameters contain "source" of the mutation, comma-separated
+// locators "before" and "after" it, and locators "added" and "removed" by it.
+const EventReferencesChanged = "references_changed"
+
+// Sources of reference mutations.
+const (
+	ReferenceMutationReload  = "reload"
+	ReferenceMutationRestart = "restart"
+	ReferenceMutationPlugin  = "plugin"
+)
+
+// Mutates references of the running container outside of the initial build, for instance
+// to load a plugin, and reports the mutation with "references_changed" event when components
+// were added or removed, so unexpected wiring changes in production are traceable.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - source string
+//   a source of the mutation, like ReferenceMutationPlugin.
+//   - mutate func(references *refer.ContainerReferences) error
+//   a function that mutates the references.
+// Returns error
+// InvalidStateError when the container is not opened or the error of the mutation.
+//
+// Example
+//   err := container.MutateReferences("123", container.ReferenceMutationPlugin,
+//       func(references *refer.ContainerReferences) error {
+//           return references.AddFromConfig("123", pluginConfig)
+//       })
+func (c *Container) MutateReferences(correlationId string, source string,
+	mutate func(references *refer.ContainerReferences) error) error {
+	references := c.getReferences()
+	if references == nil {
+		return cerr.NewInvalidStateError(
+			correlationId, "NOT_OPENED", "Container is not opened",
+		)
+	}
+
+	before := c.getReferenceLocators()
+	err := mutate(references)
+	c.publishReferenceMutation(correlationId, source, before, err)
+	return err
+}
+
+// Gets sorted locators of all components in the references.
+func (c *Container) getReferenceLocators() []string {
+	result := []string{}
+	references := c.getReferences()
+	if references == nil {
+		return result
+	}
+
+	for _, locator := range references.GetAllLocators() {
+		result = append(result, cconv.StringConverter.ToString(locator))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Reports the mutation of references with locators before and after it. Nothing is reported
+// when no component was added or removed, for instance when a component is restarted in place.
+// Details are logged at debug level when "references.log_mutations" option is set.
+func (c *Container) publishReferenceMutation(correlationId string, source string, before []string, err error) {
+	after := c.getReferenceLocators()
+	added := subtractLocators(after, before)
+	removed := subtractLocators(before, after)
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	if c.logMutations {
+		c.logger.Debug(correlationId, "References mutated by %s: added [%s], removed [%s]",
+			source, strings.Join(added, ", "), strings.Join(removed, ", "))
+	}
+
+	args := run.NewParametersFromTuples(
+		"event", EventReferencesChanged,
+		"source", source,
+		"before", strings.Join(before, ","),
+		"after", strings.Join(after, ","),
+		"added", strings.Join(added, ","),
+		"removed", strings.Join(removed, ","),
+	)
+	c.publish(correlationId, EventReferencesChanged, err, args)
+}
+
+// Gets locators that are not in the other list, counting duplicates.
+func subtractLocators(locators []string, other []string) []string {
+	counts := map[string]int{}
+	for _, locator := range other {
+		counts[locator]++
+	}
+
+	result := []string{}
+	for _, locator := range locators {
+		if counts[locator] > 0 {
+			counts[locator]--
+			continue
+		}
+		result = append(result, locator)
+	}
+	return result
+}
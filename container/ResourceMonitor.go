@@ -0,0 +1,349 @@
+package container
+
+import (
+	"io/ioutil"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-components-go/count"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+/*
+Estimated resource usage of a component group in a container.
+Components without "group" parameter belong to "default" group.
+*/
+type ResourceUsage struct {
+	Container   string  `json:"container"`
+	Group       string  `json:"group"`
+	Components  int     `json:"components"`
+	Goroutines  int64   `json:"goroutines"`
+	Share       float64 `json:"share"`
+	CpuCores    float64 `json:"cpu_cores"`
+	MemoryBytes int64   `json:"memory_bytes"`
+}
+
+/*
+Resource usage of the process with estimates attributed to containers and their component groups.
+
+Go runtime doesn't account CPU and memory per goroutine, so the process usage is split between groups
+of all containers in the process in proportion to their weight: the number of components plus goroutines
+reported by components that implement IGoroutineAccountable. The estimates are intended to compare
+groups when deciding which services to consolidate, not for exact accounting.
+
+When the process runs in a cgroup (Docker, Kubernetes), limits and memory usage are read from the cgroup.
+*/
+type ResourceReport struct {
+	Time        time.Time        `json:"time"`
+	CpuLimit    float64          `json:"cpu_limit"`
+	CpuCores    float64          `json:"cpu_cores"`
+	MemoryLimit int64            `json:"memory_limit"`
+	MemoryBytes int64            `json:"memory_bytes"`
+	Groups      []*ResourceUsage `json:"groups"`
+}
+
+/*
+Samples resource usage of the process and attributes it to component groups of the container.
+Samples are reported to counters as "container.resources.cpu_cores.<container>.<group>"
+and "container.resources.memory_bytes.<container>.<group>".
+
+Configuration parameters
+  - resources:
+    - enabled: true to sample resource usage periodically (default: false)
+    - interval: sampling interval in milliseconds (default: 10000)
+
+see
+ResourceReport
+Container.GetResourceUsage
+*/
+type ResourceMonitor struct {
+	enabled    bool
+	interval   time.Duration
+	name       string
+	references *refer.ContainerReferences
+	lastCpu    time.Duration
+	lastTime   time.Time
+	report     *ResourceReport
+	stop       chan struct{}
+	lock       sync.Mutex
+}
+
+// Monitors of all containers in the process, used to split the process usage between them.
+var resourceMonitors = map[*ResourceMonitor]bool{}
+var resourceMonitorsLock sync.Mutex
+
+// Creates a new instance of resource monitor.
+// Returns *ResourceMonitor
+func NewResourceMonitor() *ResourceMonitor {
+	return &ResourceMonitor{
+		interval: 10 * time.Second,
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *ResourceMonitor) Configure(config *cconfig.ConfigParams) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.enabled = config.GetAsBooleanWithDefault("resources.enabled", c.enabled)
+	interval := config.GetAsLongWithDefault("resources.interval", int64(c.interval/time.Millisecond))
+	c.interval = time.Duration(interval) * time.Millisecond
+}
+
+// Registers components of the container, so their usage is accounted.
+// Parameters:
+//   - name string
+//   the container name.
+//   - references *refer.ContainerReferences
+//   the container references.
+func (c *ResourceMonitor) Register(name string, references *refer.ContainerReferences) {
+	c.lock.Lock()
+	c.name = name
+	c.references = references
+	c.lastCpu = processCpuTime()
+	c.lastTime = time.Now()
+	c.lock.Unlock()
+
+	resourceMonitorsLock.Lock()
+	resourceMonitors[c] = true
+	resourceMonitorsLock.Unlock()
+}
+
+// Starts periodic sampling when it is enabled.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+func (c *ResourceMonitor) Start(correlationId string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.enabled || c.interval <= 0 || c.stop != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	c.stop = stop
+	interval := c.interval
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				c.Sample()
+			}
+		}
+	}()
+}
+
+// Stops sampling and unregisters the container components.
+func (c *ResourceMonitor) Stop() {
+	c.lock.Lock()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+	c.references = nil
+	c.report = nil
+	c.lock.Unlock()
+
+	resourceMonitorsLock.Lock()
+	delete(resourceMonitors, c)
+	resourceMonitorsLock.Unlock()
+}
+
+// Gets the last sample or samples the usage when periodic sampling is disabled.
+// Returns *ResourceReport
+func (c *ResourceMonitor) GetReport() *ResourceReport {
+	c.lock.Lock()
+	report := c.report
+	c.lock.Unlock()
+
+	if report != nil {
+		return report
+	}
+	return c.Sample()
+}
+
+// Samples resource usage of the process and estimates usage of the container groups.
+// CPU usage is averaged since the previous sample.
+// Returns *ResourceReport
+func (c *ResourceMonitor) Sample() *ResourceReport {
+	c.lock.Lock()
+	now := time.Now()
+	cpu := processCpuTime()
+	cpuCores := 0.0
+	if elapsed := now.Sub(c.lastTime); !c.lastTime.IsZero() && elapsed > 0 {
+		cpuCores = float64(cpu-c.lastCpu) / float64(elapsed)
+	}
+	c.lastCpu = cpu
+	c.lastTime = now
+	name := c.name
+	references := c.references
+	c.lock.Unlock()
+
+	report := &ResourceReport{
+		Time:        now.UTC(),
+		CpuLimit:    readCgroupCpuLimit(),
+		CpuCores:    cpuCores,
+		MemoryLimit: readCgroupMemory("memory.max", "memory/memory.limit_in_bytes"),
+		MemoryBytes: readCgroupMemory("memory.current", "memory/memory.usage_in_bytes"),
+		Groups:      []*ResourceUsage{},
+	}
+	if report.CpuLimit == 0 {
+		report.CpuLimit = float64(runtime.NumCPU())
+	}
+	if report.MemoryBytes == 0 {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		report.MemoryBytes = int64(stats.Sys - stats.HeapReleased)
+	}
+
+	// Weights of all containers in the process define the share of this container groups
+	totalWeight := 0.0
+	resourceMonitorsLock.Lock()
+	for monitor := range resourceMonitors {
+		if monitor == c {
+			continue
+		}
+		for _, usage := range monitor.getGroups() {
+			totalWeight += float64(usage.Components) + float64(usage.Goroutines)
+		}
+	}
+	resourceMonitorsLock.Unlock()
+
+	groups := []*ResourceUsage{}
+	if references != nil {
+		groups = c.getGroups()
+	}
+	for _, usage := range groups {
+		totalWeight += float64(usage.Components) + float64(usage.Goroutines)
+	}
+
+	for _, usage := range groups {
+		if totalWeight > 0 {
+			usage.Share = (float64(usage.Components) + float64(usage.Goroutines)) / totalWeight
+		}
+		usage.CpuCores = report.CpuCores * usage.Share
+		usage.MemoryBytes = int64(math.Round(float64(report.MemoryBytes) * usage.Share))
+		report.Groups = append(report.Groups, usage)
+	}
+
+	if references != nil {
+		counters := count.NewCompositeCountersFromReferences(references)
+		for _, usage := range report.Groups {
+			counters.Last("container.resources.cpu_cores."+name+"."+usage.Group, float32(usage.CpuCores))
+			counters.Last("container.resources.memory_bytes."+name+"."+usage.Group, float32(usage.MemoryBytes))
+		}
+	}
+
+	c.lock.Lock()
+	c.report = report
+	c.lock.Unlock()
+	return report
+}
+
+// Gets resource usage of the process with estimates attributed to component groups of the container.
+// When periodic sampling is enabled by "resources" option it returns the last sample.
+// Returns *ResourceReport
+func (c *Container) GetResourceUsage() *ResourceReport {
+	return c.resources.GetReport()
+}
+
+// Counts components and goroutines in groups of the container.
+func (c *ResourceMonitor) getGroups() []*ResourceUsage {
+	c.lock.Lock()
+	name := c.name
+	references := c.references
+	c.lock.Unlock()
+
+	if references == nil {
+		return []*ResourceUsage{}
+	}
+
+	groups := map[string]*ResourceUsage{}
+	for _, component := range references.GetAll() {
+		group := "default"
+		if componentConfig := references.GetComponentConfig(component); componentConfig != nil &&
+			componentConfig.Config != nil {
+			group = componentConfig.Config.GetAsStringWithDefault("group", group)
+		}
+
+		usage, ok := groups[group]
+		if !ok {
+			usage = &ResourceUsage{Container: name, Group: group}
+			groups[group] = usage
+		}
+		usage.Components++
+		if accountable, ok := component.(IGoroutineAccountable); ok {
+			usage.Goroutines += accountable.GoroutineCount()
+		}
+	}
+
+	result := []*ResourceUsage{}
+	for _, usage := range groups {
+		result = append(result, usage)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Group < result[j].Group })
+	return result
+}
+
+// Root of cgroup file system.
+const cgroupRoot = "/sys/fs/cgroup/"
+
+func readCgroupFile(name string) string {
+	data, err := ioutil.ReadFile(cgroupRoot + name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Reads the CPU limit in cores from cgroup v2 or v1, or 0 when the CPU is not limited.
+func readCgroupCpuLimit() float64 {
+	// cgroup v2: "<quota> <period>" or "max <period>"
+	if fields := strings.Fields(readCgroupFile("cpu.max")); len(fields) == 2 {
+		quota, err1 := strconv.ParseFloat(fields[0], 64)
+		period, err2 := strconv.ParseFloat(fields[1], 64)
+		if err1 == nil && err2 == nil && period > 0 {
+			return quota / period
+		}
+		return 0
+	}
+
+	// cgroup v1: quota is -1 when the CPU is not limited
+	quota, err1 := strconv.ParseFloat(readCgroupFile("cpu/cpu.cfs_quota_us"), 64)
+	period, err2 := strconv.ParseFloat(readCgroupFile("cpu/cpu.cfs_period_us"), 64)
+	if err1 == nil && err2 == nil && quota > 0 && period > 0 {
+		return quota / period
+	}
+	return 0
+}
+
+// Reads memory value in bytes from cgroup v2 or v1 file, or 0 when it is not available or not limited.
+func readCgroupMemory(v2Name string, v1Name string) int64 {
+	value := readCgroupFile(v2Name)
+	if value == "" {
+		value = readCgroupFile(v1Name)
+	}
+
+	bytes, err := strconv.ParseInt(value, 10, 64)
+	// cgroup v1 reports a huge number instead of "max" when memory is not limited
+	if err != nil || bytes <= 0 || bytes >= math.MaxInt64/2 {
+		return 0
+	}
+	return bytes
+}
@@ -0,0 +1,142 @@
+package container
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+)
+
+/*
+Applies Go runtime settings defined in the container configuration when the container opens,
+so performance tuning is kept alongside component configuration and can vary per environment.
+Settings that are not defined keep values set by GOMAXPROCS, GOGC and GOMEMLIMIT environment variables.
+
+Configuration parameters
+runtime:
+  - gomaxprocs: maximum number of CPUs executing goroutines simultaneously
+  - gogc: garbage collection target percentage or "off" to disable the collector
+  - gomemlimit: soft memory limit in bytes or with B, KiB, MiB, GiB or TiB suffix, like "512MiB" (requires Go 1.19)
+
+Example
+  - descriptor: "pip-services:container:default:default:1.0"
+    runtime:
+      gomaxprocs: 4
+      gogc: 50
+      gomemlimit: "{{MEMORY_LIMIT|1GiB}}"
+*/
+type RuntimeSettings struct {
+	logger     log.ILogger
+	gomaxprocs string
+	gogc       string
+	gomemlimit string
+}
+
+// Creates a new instance of the runtime settings.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to report applied settings.
+// Returns *RuntimeSettings
+func NewRuntimeSettings(logger log.ILogger) *RuntimeSettings {
+	return &RuntimeSettings{
+		logger: logger,
+	}
+}
+
+// Sets the logger used to report applied settings.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to be set.
+func (c *RuntimeSettings) SetLogger(logger log.ILogger) {
+	c.logger = logger
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *RuntimeSettings) Configure(config *cconfig.ConfigParams) {
+	c.gomaxprocs = config.GetAsStringWithDefault("runtime.gomaxprocs", c.gomaxprocs)
+	c.gogc = config.GetAsStringWithDefault("runtime.gogc", c.gogc)
+	c.gomemlimit = config.GetAsStringWithDefault("runtime.gomemlimit", c.gomemlimit)
+}
+
+// Applies configured settings to the Go runtime.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+// ConfigError when a setting has invalid value.
+func (c *RuntimeSettings) Apply(correlationId string) error {
+	if c.gomaxprocs != "" {
+		procs, err := strconv.Atoi(strings.TrimSpace(c.gomaxprocs))
+		if err != nil || procs < 1 {
+			return newRuntimeSettingError(correlationId, "gomaxprocs", c.gomaxprocs)
+		}
+		runtime.GOMAXPROCS(procs)
+		c.logger.Debug(correlationId, "Set GOMAXPROCS to %d", procs)
+	}
+
+	if c.gogc != "" {
+		percent := -1
+		if value := strings.TrimSpace(c.gogc); !strings.EqualFold(value, "off") {
+			var err error
+			percent, err = strconv.Atoi(value)
+			if err != nil || percent < 0 {
+				return newRuntimeSettingError(correlationId, "gogc", c.gogc)
+			}
+		}
+		debug.SetGCPercent(percent)
+		c.logger.Debug(correlationId, "Set GOGC to %s", c.gogc)
+	}
+
+	if c.gomemlimit != "" {
+		limit, ok := parseMemoryLimit(c.gomemlimit)
+		if !ok {
+			return newRuntimeSettingError(correlationId, "gomemlimit", c.gomemlimit)
+		}
+		if !setMemoryLimit(limit) {
+			return cerr.NewConfigError(
+				correlationId, "UNSUPPORTED_RUNTIME_SETTING", "Memory limit requires Go 1.19 or later",
+			).WithDetails("setting", "gomemlimit")
+		}
+		c.logger.Debug(correlationId, "Set GOMEMLIMIT to %s", c.gomemlimit)
+	}
+
+	return nil
+}
+
+// Parses memory limit in bytes with optional B, KiB, MiB, GiB or TiB suffix.
+func parseMemoryLimit(value string) (int64, bool) {
+	value = strings.TrimSpace(value)
+	multiplier := int64(1)
+	suffixes := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}, {"B", 1},
+	}
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(value, suffix.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, suffix.suffix))
+			multiplier = suffix.multiplier
+			break
+		}
+	}
+
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || limit < 0 {
+		return 0, false
+	}
+	return limit * multiplier, true
+}
+
+func newRuntimeSettingError(correlationId string, setting string, value string) error {
+	return cerr.NewConfigError(
+		correlationId, "BAD_RUNTIME_SETTING", "Runtime setting "+setting+" has invalid value "+value,
+	).WithDetails("setting", setting).WithDetails("value", value)
+}
@@ -0,0 +1,182 @@
+package container
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+// Reasons why a configuration value is detected as a secret.
+const (
+	SecretSensitiveKey = "sensitive key"
+	SecretPattern      = "secret pattern"
+	SecretHighEntropy  = "high entropy"
+)
+
+// Keys that hold sensitive values. The same definition is used to redact values
+// and to lint plaintext secrets, so both agree on what a secret is.
+var secretKeyPattern = regexp.MustCompile(
+	`(?i)(password|passwd|secret|token|credential|authorization|api_?key|access_?key|private_?key)`)
+
+var secretValuePatterns = []*regexp.Regexp{
+	// Credentials in connection URIs
+	regexp.MustCompile(`://[^:/@\s{}]+:[^@/\s{}]+@`),
+	// AWS access key id
+	regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),
+	// PEM private keys
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`),
+}
+
+// Minimal length and Shannon entropy in bits per character of values that look like random tokens.
+const (
+	secretMinLength  = 20
+	secretMinEntropy = 4.0
+)
+
+// Secret detected in a configuration value.
+type SecretFinding struct {
+	Component string `json:"component,omitempty"`
+	Key       string `json:"key"`
+	Reason    string `json:"reason"`
+}
+
+// Gets the finding in "<component>: <key> (<reason>)" format.
+// Returns string
+func (c *SecretFinding) String() string {
+	result := c.Key + " (" + c.Reason + ")"
+	if c.Component != "" {
+		result = c.Component + ": " + result
+	}
+	return result
+}
+
+// Detects if the configuration value is a secret. Keys consumed by the container are never secrets.
+// In addition to keys that look sensitive,
+// like "password" or "token", the value is checked for known secret patterns, like credentials in URIs,
+// AWS access keys and PEM private keys, and for high entropy typical for random tokens.
+// Parameters:
+//   - key string
+//   a configuration key.
+//   - value string
+//   a configuration value.
+// Returns string
+// the reason why the value is a secret or empty string when it is not detected.
+func DetectSecret(key string, value string) string {
+	if value == "" || strings.Contains(value, "{{") || config.IsContainerKey(key) {
+		return ""
+	}
+	if isSensitiveKey(key) {
+		return SecretSensitiveKey
+	}
+	for _, pattern := range secretValuePatterns {
+		if pattern.MatchString(value) {
+			return SecretPattern
+		}
+	}
+	if isRandomToken(value) {
+		return SecretHighEntropy
+	}
+	return ""
+}
+
+// Checks if the key may hold a sensitive value.
+func isSensitiveKey(key string) bool {
+	return secretKeyPattern.MatchString(key)
+}
+
+// Masks values detected as secrets (see DetectSecret).
+// Parameters:
+//   - values map[string]string
+//   values to be masked.
+// Returns map[string]string, []*SecretFinding
+// a copy of the values with masked secrets and findings sorted by key.
+func MaskSecrets(values map[string]string) (map[string]string, []*SecretFinding) {
+	result := map[string]string{}
+	findings := []*SecretFinding{}
+	for key, value := range values {
+		if reason := DetectSecret(key, value); reason != "" {
+			value = redactedValue
+			findings = append(findings, &SecretFinding{Key: key, Reason: reason})
+		}
+		result[key] = value
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Key < findings[j].Key
+	})
+	return result, findings
+}
+
+// Gets the container configuration with masked secrets, to be logged, exported or sent to admin tools.
+// A warning is logged for every value masked by patterns or entropy rather than by a sensitive key,
+// since secrets shall be kept under sensitive keys and passed as parameters or value provider references.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns config.ContainerConfig
+// a copy of the configuration with masked secrets.
+func (c *Container) ExportConfig(correlationId string) config.ContainerConfig {
+	result, findings := c.exportConfig()
+	for _, finding := range findings {
+		// Values of sensitive keys are expected, other secrets are likely left in plain text by mistake
+		if finding.Reason != SecretSensitiveKey {
+			c.logger.Warn(correlationId, "Configuration value %s looks like a plaintext secret and is masked",
+				finding.String())
+		}
+	}
+	return result
+}
+
+// Masks secrets in the container configuration without reporting them.
+func (c *Container) exportConfig() (config.ContainerConfig, []*SecretFinding) {
+	result := config.ContainerConfig{}
+	findings := []*SecretFinding{}
+	for _, componentConfig := range c.config {
+		exported := *componentConfig
+		if componentConfig.Config != nil {
+			values, componentFindings := MaskSecrets(componentConfig.Config.Value())
+			for _, finding := range componentFindings {
+				finding.Component = getLintComponentName(componentConfig)
+			}
+			findings = append(findings, componentFindings...)
+			exported.Config = cconfig.NewConfigParams(values)
+		}
+		result = append(result, &exported)
+	}
+	return result, findings
+}
+
+var secretTokenPattern = regexp.MustCompile(`^[A-Za-z0-9+_\-][A-Za-z0-9+/=_.\-]*$`)
+
+// Checks if the value looks like a random token: long, made of base64, hex or JWT characters,
+// with digits and letters and high entropy of characters. Descriptors, URIs and paths are not tokens.
+func isRandomToken(value string) bool {
+	if len(value) < secretMinLength || !secretTokenPattern.MatchString(value) {
+		return false
+	}
+	if !strings.ContainsAny(value, "0123456789") ||
+		strings.IndexFunc(value, func(r rune) bool { return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' }) < 0 {
+		return false
+	}
+	return shannonEntropy(value) >= secretMinEntropy
+}
+
+// Calculates Shannon entropy of the value in bits per character.
+func shannonEntropy(value string) float64 {
+	counts := map[rune]int{}
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+
+	result := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		result -= p * math.Log2(p)
+	}
+	return result
+}
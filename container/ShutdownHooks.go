@@ -0,0 +1,98 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+// Function that performs process-level cleanup after all components are closed,
+// like removing PID files, deregistering from discovery or flushing crash handlers.
+// Parameters:
+//   - ctx context.Context
+//   a context that is cancelled when the hook timeout is exceeded.
+// Returns error
+type ShutdownHook func(ctx context.Context) error
+
+// Adds the shutdown hook. Hooks are executed each time the container closes, after all
+// components are closed, in reverse order of their registration. Each hook is given
+// the timeout set by "shutdown_hooks.timeout" option or SetShutdownHookTimeout,
+// and hooks that exceed it are abandoned.
+// Parameters:
+//   - hook ShutdownHook
+//   a hook to be added.
+//
+// Example
+//   container.AddShutdownHook(func(ctx context.Context) error {
+//       return os.Remove("/var/run/myservice.pid")
+//   })
+func (c *Container) AddShutdownHook(hook ShutdownHook) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.shutdownHooks = append(c.shutdownHooks, hook)
+}
+
+// Sets timeout to execute each shutdown hook.
+// Parameters:
+//   - timeout time.Duration
+//   a timeout of a hook or 0 to wait indefinitely.
+func (c *Container) SetShutdownHookTimeout(timeout time.Duration) {
+	c.hookTimeout = timeout
+}
+
+// Executes shutdown hooks in reverse order. Failures of hooks don't stop the rest of them.
+// Returns the first error.
+func (c *Container) runShutdownHooks(correlationId string) error {
+	c.lock.Lock()
+	hooks := c.shutdownHooks
+	c.lock.Unlock()
+
+	var result error
+	for index := len(hooks) - 1; index >= 0; index-- {
+		err := c.runShutdownHook(correlationId, hooks[index])
+		if err != nil {
+			c.logger.Error(correlationId, err, "Shutdown hook %d failed", index)
+			if result == nil {
+				result = err
+			}
+		}
+	}
+	return result
+}
+
+// Executes the shutdown hook with timeout and recovers its panic.
+func (c *Container) runShutdownHook(correlationId string, hook ShutdownHook) error {
+	ctx := context.Background()
+	if c.hookTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.hookTimeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				recoverErr, ok := r.(error)
+				if !ok {
+					recoverErr = errors.New(cconv.StringConverter.ToString(r))
+				}
+				done <- recoverErr
+			}
+		}()
+		done <- hook(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return cerr.NewInvalidStateError(
+			correlationId, "SHUTDOWN_HOOK_TIMEOUT", "Shutdown hook was not executed in time",
+		).WithDetails("timeout", c.hookTimeout.Milliseconds())
+	}
+}
@@ -0,0 +1,220 @@
+package container
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/cache"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+/*
+Persists state of components that implement IStateful interface across container restarts.
+The state is kept in files in a local directory or in a cache component registered in the container.
+
+Configuration parameters
+  - state:
+    - backend: "file" or "cache" (default: "file" when path is set)
+    - path: directory to keep state files
+    - timeout: expiration timeout of cached state in milliseconds (default: 0, never)
+Example
+  - descriptor: "pip-services:container:default:default:1.0"
+    state:
+      backend: file
+      path: /var/lib/myservice/state
+
+see
+IStateful
+*/
+type StateStore struct {
+	logger  log.ILogger
+	backend string
+	path    string
+	timeout int64
+	loaded  bool
+}
+
+var stateKeyPattern = regexp.MustCompile("[^A-Za-z0-9_.-]+")
+
+// Creates a new instance of the state store.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to report failures.
+// Returns *StateStore
+func NewStateStore(logger log.ILogger) *StateStore {
+	return &StateStore{
+		logger: logger,
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *StateStore) Configure(config *cconfig.ConfigParams) {
+	c.path = config.GetAsStringWithDefault("state.path", c.path)
+	backend := c.backend
+	if backend == "" && c.path != "" {
+		backend = "file"
+	}
+	c.backend = config.GetAsStringWithDefault("state.backend", backend)
+	c.timeout = config.GetAsLongWithDefault("state.timeout", c.timeout)
+}
+
+// Sets the logger used to report failures.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to be set.
+func (c *StateStore) SetLogger(logger log.ILogger) {
+	c.logger = logger
+}
+
+// Checks if the state store is enabled.
+// Returns bool
+func (c *StateStore) IsEnabled() bool {
+	return c.backend != ""
+}
+
+func (c *StateStore) toKey(locator interface{}) string {
+	return stateKeyPattern.ReplaceAllString(cconv.StringConverter.ToString(locator), "_")
+}
+
+func (c *StateStore) getCache(references crefer.IReferences) (cache.ICache, error) {
+	store, ok := references.GetOneOptional(
+		crefer.NewDescriptor("*", "cache", "*", "*", "*"),
+	).(cache.ICache)
+	if !ok {
+		return nil, cerr.NewConfigError(
+			"", "NO_STATE_CACHE", "Cache component to keep the state is not found",
+		)
+	}
+	return store, nil
+}
+
+func (c *StateStore) load(correlationId string, references crefer.IReferences, key string) ([]byte, error) {
+	switch c.backend {
+	case "file":
+		data, err := ioutil.ReadFile(filepath.Join(c.path, key+".state"))
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return data, err
+	case "cache":
+		store, err := c.getCache(references)
+		if err != nil {
+			return nil, err
+		}
+		value, err := store.Retrieve(correlationId, "state."+key)
+		switch v := value.(type) {
+		case []byte:
+			return v, err
+		case string:
+			return []byte(v), err
+		default:
+			return nil, err
+		}
+	default:
+		return nil, cerr.NewConfigError(
+			correlationId, "BAD_STATE_BACKEND", "State backend "+c.backend+" is not supported",
+		).WithDetails("backend", c.backend)
+	}
+}
+
+func (c *StateStore) save(correlationId string, references crefer.IReferences, key string, state []byte) error {
+	switch c.backend {
+	case "file":
+		err := os.MkdirAll(c.path, 0755)
+		if err == nil {
+			err = ioutil.WriteFile(filepath.Join(c.path, key+".state"), state, 0644)
+		}
+		return err
+	case "cache":
+		store, err := c.getCache(references)
+		if err == nil {
+			_, err = store.Store(correlationId, "state."+key, string(state), c.timeout)
+		}
+		return err
+	default:
+		return cerr.NewConfigError(
+			correlationId, "BAD_STATE_BACKEND", "State backend "+c.backend+" is not supported",
+		).WithDetails("backend", c.backend)
+	}
+}
+
+// Restores state of all stateful components in the references.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - references *refer.ContainerReferences
+//   the container references.
+// Returns error
+// the first error returned by a component when it fails to restore its state.
+func (c *StateStore) Restore(correlationId string, references *refer.ContainerReferences) error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	for _, component := range references.GetAll() {
+		stateful, ok := component.(IStateful)
+		if !ok {
+			continue
+		}
+
+		locator := references.GetComponentLocator(component)
+		state, err := c.load(correlationId, references, c.toKey(locator))
+		if err != nil {
+			// Missing state shall not prevent the container from starting
+			c.logger.Warn(correlationId, "Failed to load state of component %v: %v", locator, err)
+			continue
+		}
+		if state == nil {
+			continue
+		}
+
+		err = stateful.RestoreState(correlationId, state)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.loaded = true
+	return nil
+}
+
+// Saves state of all stateful components in the references.
+// The state is saved only when it was restored before, so a failed startup doesn't overwrite it.
+// Failures are logged and do not interrupt saving state of other components.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - references *refer.ContainerReferences
+//   the container references.
+func (c *StateStore) Save(correlationId string, references *refer.ContainerReferences) {
+	if !c.IsEnabled() || !c.loaded {
+		return
+	}
+	c.loaded = false
+
+	for _, component := range references.GetAll() {
+		stateful, ok := component.(IStateful)
+		if !ok {
+			continue
+		}
+
+		locator := references.GetComponentLocator(component)
+		state, err := stateful.SaveState(correlationId)
+		if err == nil && state != nil {
+			err = c.save(correlationId, references, c.toKey(locator), state)
+		}
+		if err != nil {
+			c.logger.Error(correlationId, err, "Failed to save state of component %v", locator)
+		}
+	}
+}
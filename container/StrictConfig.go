@@ -0,0 +1,176 @@
+package container
+
+import (
+	"sort"
+	"strings"
+
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+// Modes of checking configuration keys that components don't consume.
+const (
+	StrictConfigOff  = "off"
+	StrictConfigWarn = "warn"
+	StrictConfigFail = "fail"
+)
+
+/*
+Interface for components that declare configuration keys they consume.
+In strict configuration mode the container reports keys of their configurations
+that are not declared, to catch typos like "conection.host". Components that don't
+implement the interface are not checked.
+
+Example
+  func (c *MyPersistence) GetConfigKeys() []string {
+      return []string{"connection", "credential", "options.max_page_size"}
+  }
+*/
+type IConfigKeysProvider interface {
+	// Gets configuration keys consumed by the component. A key also covers
+	// all nested keys, for instance "connection" covers "connection.host".
+	// Returns []string
+	GetConfigKeys() []string
+}
+
+// Configuration key that is not consumed by the component.
+type UnusedConfigKey struct {
+	Component  string `json:"component"`
+	Key        string `json:"key"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Gets the unused key in "<component>: <key>" format with a suggested key, if any.
+// Returns string
+func (c *UnusedConfigKey) String() string {
+	result := c.Component + ": " + c.Key
+	if c.Suggestion != "" {
+		result += " (did you mean " + c.Suggestion + "?)"
+	}
+	return result
+}
+
+// Gets configuration keys of created components that the components don't consume.
+// Only components that implement IConfigKeysProvider are checked. Keys consumed by the container,
+// like "descriptor" and "depends_on", are always consumed.
+// Returns []*UnusedConfigKey
+// all unused keys or empty list when all keys are consumed.
+func (c *Container) GetUnusedConfigKeys() []*UnusedConfigKey {
+	result := []*UnusedConfigKey{}
+	references := c.getReferences()
+	if references == nil {
+		return result
+	}
+
+	for _, component := range references.GetAll() {
+		provider, ok := component.(IConfigKeysProvider)
+		if !ok {
+			continue
+		}
+		componentConfig := references.GetComponentConfig(component)
+		if componentConfig == nil || componentConfig.Config == nil {
+			continue
+		}
+
+		declared := provider.GetConfigKeys()
+		name := cconv.StringConverter.ToString(references.GetComponentLocator(component))
+		keys := componentConfig.Config.Keys()
+		sort.Strings(keys)
+		for _, key := range keys {
+			if config.IsContainerKey(key) || isDeclaredKey(declared, key) {
+				continue
+			}
+			result = append(result, &UnusedConfigKey{
+				Component:  name,
+				Key:        key,
+				Suggestion: suggestKey(declared, key),
+			})
+		}
+	}
+	return result
+}
+
+// Checks that components consume all keys of their configurations according to "strict_config" option.
+func (c *Container) checkUnusedConfigKeys(correlationId string) error {
+	if c.strictConfig != StrictConfigWarn && c.strictConfig != StrictConfigFail {
+		return nil
+	}
+
+	unused := c.GetUnusedConfigKeys()
+	if len(unused) == 0 {
+		return nil
+	}
+
+	if c.strictConfig == StrictConfigWarn {
+		for _, key := range unused {
+			c.logger.Warn(correlationId, "Configuration key %s is not consumed", key.String())
+		}
+		return nil
+	}
+
+	messages := []string{}
+	for _, key := range unused {
+		messages = append(messages, key.String())
+	}
+	return cerr.NewConfigError(
+		correlationId, "UNUSED_CONFIG_KEYS", "Configuration keys are not consumed: "+strings.Join(messages, "; "),
+	).WithDetails("keys", unused)
+}
+
+// Checks if the key or its parent section is declared.
+func isDeclaredKey(declared []string, key string) bool {
+	for _, name := range declared {
+		if key == name || strings.HasPrefix(key, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggests the declared key closest to the mistyped key, or empty string when none is close enough.
+func suggestKey(declared []string, key string) string {
+	suggestion := ""
+	best := len(key)/3 + 1
+	for _, name := range declared {
+		// Compare against the nested key of the section, like "conection.host" against "connection.host"
+		candidate := name
+		if index := strings.Index(key, "."); index >= 0 && !strings.Contains(name, ".") {
+			candidate = name + key[index:]
+		}
+		if distance := editDistance(key, candidate); distance < best {
+			best = distance
+			suggestion = candidate
+		}
+	}
+	return suggestion
+}
+
+// Calculates the Levenshtein distance between two strings.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,103 @@
+package container
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+/*
+Diagnostic snapshot of the container environment that users can attach to issues.
+It contains configuration parameters, environment variables, context properties,
+the container configuration, registered factories, created components, network listeners they declare, progress of opening them, resource usage and recent lifecycle events. Values of sensitive keys are redacted.
+
+see
+Container.ExportSupportBundle
+*/
+type SupportBundle struct {
+	Name        string                 `json:"name"`
+	ContextId   string                 `json:"context_id"`
+	StartTime   time.Time              `json:"start_time"`
+	CreateTime  time.Time              `json:"create_time"`
+	GoVersion   string                 `json:"go_version"`
+	Platform    string                 `json:"platform"`
+	Opened      bool                   `json:"opened"`
+	Parameters  map[string]string      `json:"parameters"`
+	Environment map[string]string      `json:"environment"`
+	Properties  map[string]string      `json:"properties"`
+	Config      config.ContainerConfig `json:"config"`
+	Factories   []string               `json:"factories"`
+	Components  []string               `json:"components"`
+	Listeners   []string               `json:"listeners"`
+	Progress    refer.OpenProgress     `json:"progress"`
+	Resources   *ResourceReport        `json:"resources"`
+	History     []HistoryEntry         `json:"history"`
+}
+
+const redactedValue = "***"
+
+// Redacts values of sensitive keys and values detected as secrets by the scanner (see DetectSecret).
+func redactValues(values map[string]string) map[string]string {
+	result, _ := MaskSecrets(values)
+	return result
+}
+
+// Creates a diagnostic snapshot of the container environment.
+// Values of keys that look sensitive (passwords, secrets, tokens, keys) and values detected as secrets are redacted.
+// Returns *SupportBundle
+func (c *Container) ExportSupportBundle() *SupportBundle {
+	bundle := &SupportBundle{
+		Name:        c.info.Name,
+		ContextId:   c.info.ContextId,
+		StartTime:   c.info.StartTime,
+		CreateTime:  time.Now().UTC(),
+		GoVersion:   runtime.Version(),
+		Platform:    runtime.GOOS + "/" + runtime.GOARCH,
+		Opened:      c.IsOpen(),
+		Parameters:  map[string]string{},
+		Environment: map[string]string{},
+		Properties:  map[string]string{},
+		Factories:   append([]string{}, c.factoryNames...),
+		Components:  []string{},
+		Listeners:   []string{},
+	}
+
+	if c.parameters != nil {
+		bundle.Parameters = redactValues(c.parameters.Value())
+	}
+
+	env := map[string]string{}
+	for _, e := range os.Environ() {
+		pair := strings.SplitN(e, "=", 2)
+		if len(pair) == 2 {
+			env[pair[0]] = pair[1]
+		}
+	}
+	bundle.Environment = redactValues(env)
+	bundle.Config, _ = c.exportConfig()
+
+	if c.info.Properties != nil {
+		bundle.Properties = redactValues(c.info.Properties)
+	}
+
+	if references := c.getReferences(); references != nil {
+		for _, locator := range references.GetAllLocators() {
+			bundle.Components = append(bundle.Components, cconv.StringConverter.ToString(locator))
+		}
+	}
+
+	for _, listener := range c.GetListeners() {
+		bundle.Listeners = append(bundle.Listeners, listener.Component+" "+listener.String())
+	}
+
+	bundle.Progress = c.GetOpenProgress()
+	bundle.Resources = c.GetResourceUsage()
+	bundle.History = c.GetHistory()
+
+	return bundle
+}
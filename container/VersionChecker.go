@@ -0,0 +1,154 @@
+package container
+
+import (
+	"regexp"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+)
+
+/*
+Checks that the same library is not mixed in different major versions, for instance
+when pip-services3 packages collide with their "/v3" module paths.
+Versions of pip-services modules are collected from the build information of the process,
+versions of other libraries are collected from factories and components that implement IVersioned interface.
+
+Configuration parameters
+  - versions:
+    - strict: true to fail the container startup on incompatible versions instead of logging a warning (default: false)
+
+see
+IVersioned
+*/
+type VersionChecker struct {
+	logger log.ILogger
+	strict bool
+}
+
+var majorPathSuffix = regexp.MustCompile(`/v([0-9]+)$`)
+
+// Creates a new instance of the version checker.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to report incompatible versions.
+// Returns *VersionChecker
+func NewVersionChecker(logger log.ILogger) *VersionChecker {
+	return &VersionChecker{
+		logger: logger,
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *VersionChecker) Configure(config *cconfig.ConfigParams) {
+	c.strict = config.GetAsBooleanWithDefault("versions.strict", c.strict)
+}
+
+// Sets the logger used to report incompatible versions.
+// Parameters:
+//   - logger log.ILogger
+//   a logger to be set.
+func (c *VersionChecker) SetLogger(logger log.ILogger) {
+	c.logger = logger
+}
+
+// Splits module path into the path without major version suffix and the major version.
+func splitLibrary(name string, version string) (string, string) {
+	major := strings.TrimPrefix(version, "v")
+	if index := strings.Index(major, "."); index >= 0 {
+		major = major[:index]
+	}
+
+	if match := majorPathSuffix.FindStringSubmatch(name); match != nil {
+		return strings.TrimSuffix(name, match[0]), match[1]
+	}
+	if major == "" {
+		major = "0"
+	}
+	return name, major
+}
+
+// Collects declared library versions.
+// Parameters:
+//   - sources []interface{}
+//   factories and components that may implement IVersioned interface.
+// Returns map[string]map[string][]string
+// versions of libraries indexed by library path and major version.
+func (c *VersionChecker) Collect(sources []interface{}) map[string]map[string][]string {
+	result := map[string]map[string][]string{}
+
+	add := func(name string, version string) {
+		if name == "" {
+			return
+		}
+		library, major := splitLibrary(name, version)
+		if result[library] == nil {
+			result[library] = map[string][]string{}
+		}
+		result[library][major] = append(result[library][major], name+"@"+version)
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if strings.Contains(dep.Path, "pip-services") {
+				add(dep.Path, dep.Version)
+			}
+		}
+	}
+
+	for _, source := range sources {
+		if versioned, ok := source.(IVersioned); ok {
+			add(versioned.GetLibraryName(), versioned.GetLibraryVersion())
+		}
+	}
+
+	return result
+}
+
+// Checks that libraries are not mixed in different major versions.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - sources []interface{}
+//   factories and components that may implement IVersioned interface.
+// Returns error
+// InvalidStateError when incompatible versions are found in strict mode.
+func (c *VersionChecker) Check(correlationId string, sources []interface{}) error {
+	conflicts := []string{}
+
+	for library, majors := range c.Collect(sources) {
+		if len(majors) < 2 {
+			continue
+		}
+
+		versions := []string{}
+		for _, names := range majors {
+			versions = append(versions, names[0])
+		}
+		sort.Strings(versions)
+		conflicts = append(conflicts, library+" ("+strings.Join(versions, ", ")+")")
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	sort.Strings(conflicts)
+	err := cerr.NewInvalidStateError(
+		correlationId, "INCOMPATIBLE_VERSIONS",
+		"Libraries are mixed in incompatible major versions: "+strings.Join(conflicts, "; "),
+	).WithDetails("conflicts", conflicts)
+
+	if c.strict {
+		return err
+	}
+
+	c.logger.Warn(correlationId, "%s", err.Message)
+	return nil
+}
@@ -6,4 +6,6 @@ require (
 	github.com/pip-services3-go/pip-services3-commons-go v1.1.0
 	github.com/pip-services3-go/pip-services3-components-go v1.2.0
 	github.com/stretchr/testify v1.7.0
+	google.golang.org/grpc v1.38.0
+	gopkg.in/yaml.v2 v2.4.0
 )
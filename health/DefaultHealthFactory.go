@@ -0,0 +1,16 @@
+package health
+
+import (
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	cbuild "github.com/pip-services3-go/pip-services3-components-go/build"
+)
+
+var GrpcHealthServiceDescriptor = crefer.NewDescriptor("pip-services", "health-service", "grpc", "*", "1.0")
+
+// Create a new instance of the factory that creates health checking components.
+// Returns *cbuild.Factory
+func NewDefaultHealthFactory() *cbuild.Factory {
+	factory := cbuild.NewFactory()
+	factory.RegisterType(GrpcHealthServiceDescriptor, NewGrpcHealthService)
+	return factory
+}
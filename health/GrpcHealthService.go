@@ -0,0 +1,231 @@
+package health
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+/*
+Service that implements the standard grpc.health.v1.Health protocol backed by the container state
+and health of its components, so gRPC load balancers and Kubernetes gRPC probes check the container natively.
+
+The overall status (empty service name) is SERVING while the container is opened and doesn't shed traffic.
+Named services are SERVING when, in addition, all components matching their descriptors are healthy
+(see IHealthy and Container.GetDegradation). Statuses are polled with the configured interval
+and pushed to clients that watch them.
+
+Configuration parameters
+  - connection:
+    - host: host name or IP address to listen on (default: 0.0.0.0)
+    - port: port to listen on (default: 8090)
+  - interval: time in milliseconds between status updates (default: 1000)
+  - services: list of named services
+    - name: a service name, usually a full gRPC service name
+    - components: a descriptor of components that serve it, may contain wildcards
+References
+  - *:logger:*:*:1.0 (optional) ILogger components to pass log messages
+  - *:health-source:*:*:1.0 IHealthSource component that reports the container health (put by the container)
+Example
+  - descriptor: "pip-services:health-service:grpc:default:1.0"
+    connection:
+      port: 8090
+    services:
+      - name: "mygroup.billing.v1.Billing"
+        components: "mygroup:*:*:billing:1.0"
+
+  grpc_health_probe -addr=localhost:8090 -service=mygroup.billing.v1.Billing
+*/
+type GrpcHealthService struct {
+	logger   *log.CompositeLogger
+	host     string
+	port     int
+	interval time.Duration
+	services map[string]*crefer.Descriptor
+	source   IHealthSource
+	server   *grpc.Server
+	health   *health.Server
+	stop     chan struct{}
+	lock     sync.Mutex
+}
+
+// Creates a new instance of the service.
+// Returns *GrpcHealthService
+func NewGrpcHealthService() *GrpcHealthService {
+	return &GrpcHealthService{
+		logger:   log.NewCompositeLogger(),
+		host:     "0.0.0.0",
+		port:     8090,
+		interval: time.Second,
+		services: map[string]*crefer.Descriptor{},
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   configuration parameters to be set.
+func (c *GrpcHealthService) Configure(config *cconfig.ConfigParams) {
+	c.host = config.GetAsStringWithDefault("connection.host", c.host)
+	c.port = config.GetAsIntegerWithDefault("connection.port", c.port)
+	interval := config.GetAsLongWithDefault("interval", int64(c.interval/time.Millisecond))
+	c.interval = time.Duration(interval) * time.Millisecond
+
+	services := config.GetSection("services")
+	for _, index := range services.GetSectionNames() {
+		service := services.GetSection(index)
+		name := service.GetAsString("name")
+		descriptor, err := crefer.ParseDescriptorFromString(service.GetAsString("components"))
+		if name == "" || err != nil || descriptor == nil {
+			c.logger.Error("", err, "Invalid health service %s", index)
+			continue
+		}
+		c.services[name] = descriptor
+	}
+}
+
+// Sets references to dependent components.
+// Parameters:
+//   - references crefer.IReferences
+//   references to locate the component dependencies.
+func (c *GrpcHealthService) SetReferences(references crefer.IReferences) {
+	c.logger.SetReferences(references)
+	c.source, _ = references.GetOneOptional(
+		crefer.NewDescriptor("*", "health-source", "*", "*", "1.0"),
+	).(IHealthSource)
+}
+
+// Checks if the component is opened.
+// Returns bool
+func (c *GrpcHealthService) IsOpen() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.server != nil
+}
+
+// Opens the component and starts serving health checks.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+// ConnectionError when the port cannot be listened.
+func (c *GrpcHealthService) Open(correlationId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.server != nil {
+		return nil
+	}
+
+	address := net.JoinHostPort(c.host, strconv.Itoa(c.port))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return cerr.NewConnectionError(
+			correlationId, "CANNOT_LISTEN", "Failed to listen on "+address,
+		).WithDetails("address", address).WithCause(err)
+	}
+
+	c.health = health.NewServer()
+	c.server = grpc.NewServer()
+	healthpb.RegisterHealthServer(c.server, c.health)
+	c.update(correlationId)
+
+	server := c.server
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			c.logger.Error(correlationId, err, "gRPC health service stopped")
+		}
+	}()
+
+	stop := make(chan struct{})
+	c.stop = stop
+	go c.poll(correlationId, stop)
+
+	c.logger.Info(correlationId, "gRPC health service is listening on %s", address)
+	return nil
+}
+
+// Closes component and stops serving health checks. Watching clients receive NOT_SERVING status.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+func (c *GrpcHealthService) Close(correlationId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.server == nil {
+		return nil
+	}
+
+	close(c.stop)
+	c.health.Shutdown()
+	// Watch streams never end by themselves, so graceful stop would block
+	c.server.Stop()
+
+	c.server = nil
+	c.health = nil
+	c.stop = nil
+	return nil
+}
+
+// Updates statuses with the configured interval until the service is closed.
+func (c *GrpcHealthService) poll(correlationId string, stop chan struct{}) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.lock.Lock()
+			if c.server != nil {
+				c.update(correlationId)
+			}
+			c.lock.Unlock()
+		}
+	}
+}
+
+// Sets statuses of the overall server and named services from the health source.
+func (c *GrpcHealthService) update(correlationId string) {
+	serving := c.source != nil && c.source.IsServing(correlationId)
+	c.health.SetServingStatus("", toServingStatus(serving))
+
+	if len(c.services) == 0 {
+		return
+	}
+
+	components := map[string]bool{}
+	if serving {
+		components = c.source.GetComponentHealth(correlationId)
+	}
+
+	for name, descriptor := range c.services {
+		healthy := serving
+		for locator, componentHealthy := range components {
+			component, err := crefer.ParseDescriptorFromString(locator)
+			if err == nil && component != nil && descriptor.Match(component) {
+				healthy = healthy && componentHealthy
+			}
+		}
+		c.health.SetServingStatus(name, toServingStatus(healthy))
+	}
+}
+
+func toServingStatus(serving bool) healthpb.HealthCheckResponse_ServingStatus {
+	if serving {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
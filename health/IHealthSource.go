@@ -0,0 +1,22 @@
+package health
+
+/*
+Interface for sources of health information, implemented by the container.
+The container puts itself into references as "pip-services:health-source:container:default:1.0".
+*/
+type IHealthSource interface {
+	// Checks if the container is opened and serves requests.
+	// Parameters:
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	// Returns bool
+	IsServing(correlationId string) bool
+
+	// Gets health of components.
+	// Parameters:
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	// Returns map[string]bool
+	// health of components indexed by their locators.
+	GetComponentHealth(correlationId string) map[string]bool
+}
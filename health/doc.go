@@ -0,0 +1,6 @@
+/*
+Health checking components that report state of the container and its components
+through standard protocols, so load balancers and orchestrators check it without custom code.
+*/
+
+package health
@@ -0,0 +1,103 @@
+package refer
+
+import (
+	"context"
+	"time"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/convert"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+)
+
+// Default time to wait for asynchronous construction of a component.
+const DefaultCreateTimeout = 60 * time.Second
+
+/*
+Interface for handles returned by factories for components that require asynchronous construction,
+for instance fetching remote schemas. The container awaits the handle with a timeout
+during the build phase instead of blocking inside factory Create method.
+
+see
+AsyncComponent
+*/
+type IAsyncComponent interface {
+	// Waits until the component is constructed.
+	// Parameters:
+	//   - ctx context.Context
+	//   a context to cancel waiting.
+	// Returns interface{}, error
+	// the constructed component or construction error.
+	Await(ctx context.Context) (interface{}, error)
+}
+
+/*
+Handle of a component constructed asynchronously in a separate goroutine.
+
+Example
+  factory.Register(MySchemaDescriptor, func(locator interface{}) interface{} {
+      return refer.NewAsyncComponent(func() (interface{}, error) {
+          return LoadRemoteSchema()
+      })
+  })
+*/
+type AsyncComponent struct {
+	done      chan struct{}
+	component interface{}
+	err       error
+}
+
+// Creates a new handle and starts construction of the component.
+// Parameters:
+//   - create func() (interface{}, error)
+//   a function that constructs the component.
+// Returns *AsyncComponent
+func NewAsyncComponent(create func() (interface{}, error)) *AsyncComponent {
+	c := &AsyncComponent{
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(c.done)
+		defer func() {
+			if r := recover(); r != nil {
+				c.err = panicToError("", r)
+			}
+		}()
+		c.component, c.err = create()
+	}()
+
+	return c
+}
+
+// Waits until the component is constructed.
+// Parameters:
+//   - ctx context.Context
+//   a context to cancel waiting.
+// Returns interface{}, error
+// the constructed component or construction error.
+func (c *AsyncComponent) Await(ctx context.Context) (interface{}, error) {
+	select {
+	case <-c.done:
+		return c.component, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Waits for asynchronous construction of the component if it is returned as IAsyncComponent handle.
+func awaitComponent(locator interface{}, component interface{}, timeout time.Duration) (interface{}, error) {
+	async, ok := component.(IAsyncComponent)
+	if !ok {
+		return component, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	component, err := async.Await(ctx)
+	if err == context.DeadlineExceeded {
+		return nil, build.NewCreateError(
+			"CREATE_TIMEOUT", "Component "+convert.StringConverter.ToString(locator)+" was not created in time",
+		).WithDetails("locator", locator).WithDetails("timeout", timeout.Milliseconds())
+	}
+	return component, err
+}
@@ -0,0 +1,144 @@
+package refer
+
+import (
+	"context"
+	"time"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Progress of opening components. It is updated while components are opened,
+so supervisors can see how far the container got when opening is slow or fails.
+
+see
+RunReferencesDecorator.GetOpenProgress
+*/
+type OpenProgress struct {
+	Total   int           `json:"total"`
+	Opened  int           `json:"opened"`
+	Batch   int           `json:"batch"`
+	Batches int           `json:"batches"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// Sets the time budget to open all components and the size of batches they are opened in.
+// Each batch gets a deadline with a share of the remaining budget proportional to its size,
+// so time saved by fast batches is carried over to the next ones. When the context passed to Open
+// has an earlier deadline, for instance set by an orchestrator, the budget is limited by it.
+// Parameters:
+//   - budget time.Duration
+//     the time to open all components or 0 to use only the deadline of the context.
+//   - batchSize int
+//     the number of components in a batch or 0 to open all components in one batch.
+func (c *RunReferencesDecorator) SetOpenBudget(budget time.Duration, batchSize int) {
+	c.openBudget = budget
+	c.batchSize = batchSize
+}
+
+// Gets progress of the last or the current Open call.
+// Returns OpenProgress
+func (c *RunReferencesDecorator) GetOpenProgress() OpenProgress {
+	c.progressLock.Lock()
+	defer c.progressLock.Unlock()
+
+	progress := c.progress
+	if c.opening {
+		progress.Elapsed = time.Since(c.progressStarted)
+	}
+	return progress
+}
+
+func (c *RunReferencesDecorator) startProgress(total int, batches int) {
+	c.progressLock.Lock()
+	defer c.progressLock.Unlock()
+
+	c.progress = OpenProgress{Total: total, Batches: batches}
+	c.progressStarted = time.Now()
+	c.opening = true
+}
+
+// Adds opened components and completed batch to the progress of the current Open call.
+// Components opened later, for instance by restarts, are not counted.
+func (c *RunReferencesDecorator) updateProgress(opened int, batch int) {
+	c.progressLock.Lock()
+	defer c.progressLock.Unlock()
+
+	if !c.opening {
+		return
+	}
+	c.progress.Opened += opened
+	if batch > c.progress.Batch {
+		c.progress.Batch = batch
+	}
+	c.progress.Elapsed = time.Since(c.progressStarted)
+}
+
+func (c *RunReferencesDecorator) finishProgress() {
+	c.progressLock.Lock()
+	defer c.progressLock.Unlock()
+
+	if c.opening {
+		c.progress.Elapsed = time.Since(c.progressStarted)
+		c.opening = false
+	}
+}
+
+// Opens components in batches with deadlines derived from the open budget.
+func (c *RunReferencesDecorator) openBatches(ctx context.Context, correlationId string,
+	components []interface{}, locators []interface{}) error {
+	batchSize := c.batchSize
+	if batchSize <= 0 || batchSize > len(components) {
+		batchSize = len(components)
+	}
+	batches := 0
+	if batchSize > 0 {
+		batches = (len(components) + batchSize - 1) / batchSize
+	}
+	c.startProgress(len(components), batches)
+
+	deadline, hasDeadline := ctx.Deadline()
+	if c.openBudget > 0 {
+		budgetDeadline := time.Now().Add(c.openBudget)
+		if !hasDeadline || budgetDeadline.Before(deadline) {
+			deadline, hasDeadline = budgetDeadline, true
+		}
+	}
+
+	for start, batch := 0, 1; start < len(components); start, batch = start+batchSize, batch+1 {
+		end := start + batchSize
+		if end > len(components) {
+			end = len(components)
+		}
+
+		batchCtx, cancel := ctx, context.CancelFunc(func() {})
+		if hasDeadline {
+			remaining := time.Until(deadline)
+			share := remaining * time.Duration(end-start) / time.Duration(len(components)-start)
+			batchCtx, cancel = context.WithTimeout(ctx, share)
+		}
+
+		err := c.openSequential(batchCtx, correlationId, components[start:end], locators[start:end])
+		batchErr := batchCtx.Err()
+		cancel()
+
+		c.updateProgress(0, batch)
+		progress := c.GetOpenProgress()
+		if c.logger != nil {
+			c.logger.Debug(correlationId, "Opened batch %d of %d, %d of %d components in %v",
+				batch, batches, progress.Opened, progress.Total, progress.Elapsed)
+		}
+
+		if err != nil && batchErr == context.DeadlineExceeded && ctx.Err() == nil {
+			return errors.NewInternalError(
+				correlationId, "OPEN_BUDGET_EXCEEDED", "Components were not opened within the open budget",
+			).WithDetails("batch", batch).WithDetails("batches", batches).
+				WithDetails("opened", progress.Opened).WithDetails("total", progress.Total).
+				WithDetails("locator", c.failedLocator).WithCause(err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package refer
 
 import (
+	"time"
+
 	"github.com/pip-services3-go/pip-services3-commons-go/refer"
 	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
 	"github.com/pip-services3-go/pip-services3-components-go/build"
@@ -62,20 +64,37 @@ func (c *BuildReferencesDecorator) FindFactory(locator interface{}) build.IFacto
 // the created component.
 func (c *BuildReferencesDecorator) Create(locator interface{},
 	factory build.IFactory) interface{} {
+	result, _ := c.CreateWithTimeout(locator, factory, DefaultCreateTimeout)
+	return result
+}
+
+// Creates a component identified by given locator. When the factory returns IAsyncComponent handle
+// it waits for the component construction no longer than the timeout.
+// Parameters:
+//   - locator interface{}
+//   a locator to identify component to be created.
+//   - factory build.IFactory
+//   a factory that shall create the component.
+//   - timeout time.Duration
+//   time to wait for asynchronous construction.
+// Returns interface{}, error
+// the created component or nil and CreateError when the component was not created in time.
+func (c *BuildReferencesDecorator) CreateWithTimeout(locator interface{},
+	factory build.IFactory, timeout time.Duration) (result interface{}, err error) {
 
 	if factory == nil {
-		return nil
+		return nil, nil
 	}
 
-	var result interface{}
-
 	defer func() {
-		recover()
+		if r := recover(); r != nil {
+			result = nil
+		}
 	}()
 
 	result, _ = factory.Create(locator)
 
-	return result
+	return awaitComponent(locator, result, timeout)
 }
 
 // Clarifies a component locator by merging two descriptors into one to replace missing fields.
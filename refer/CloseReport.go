@@ -0,0 +1,47 @@
+package refer
+
+import (
+	"time"
+)
+
+/*
+Result of closing a single component.
+*/
+type ComponentCloseReport struct {
+	Locator  interface{}   `json:"locator"`
+	Duration time.Duration `json:"duration"`
+	Error    error         `json:"-"`
+	TimedOut bool          `json:"timed_out"`
+}
+
+/*
+Report of closing components in the references. It contains close duration and outcome for every
+component and the total shutdown time. It is used to tune shutdown timeouts in orchestration platforms.
+
+see
+RunReferencesDecorator.CloseWithReport
+*/
+type CloseReport struct {
+	Components []*ComponentCloseReport `json:"components"`
+	Duration   time.Duration           `json:"duration"`
+}
+
+// Creates a new empty close report.
+// Returns *CloseReport
+func NewCloseReport() *CloseReport {
+	return &CloseReport{
+		Components: []*ComponentCloseReport{},
+	}
+}
+
+// Gets the first error that happened during close.
+// Returns error
+// the first error or nil if all components were closed successfully.
+func (c *CloseReport) FirstError() error {
+	for _, component := range c.Components {
+		if component.Error != nil {
+			return component.Error
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package refer
+
+import (
+	"os"
+	"sync"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+)
+
+// Serializes changes of process environment made for components.
+var environmentLock sync.Mutex
+
+// Reads environment variables of the component from "env" section of its configuration.
+func readEnvironment(config *cconfig.ConfigParams) map[string]string {
+	if config == nil {
+		return nil
+	}
+	section := config.GetSection("env")
+	keys := section.Keys()
+	if len(keys) == 0 {
+		return nil
+	}
+
+	env := map[string]string{}
+	for _, key := range keys {
+		env[key] = section.GetAsString(key)
+	}
+	return env
+}
+
+// Runs the action with environment variables of the component set in the process environment,
+// and restores their previous values afterwards. Actions of components with environment variables
+// run one at a time, so they don't see variables of each other.
+func withEnvironment(env map[string]string, action func() error) error {
+	if len(env) == 0 {
+		return action()
+	}
+
+	environmentLock.Lock()
+	defer environmentLock.Unlock()
+
+	previous := map[string]*string{}
+	for name, value := range env {
+		if old, ok := os.LookupEnv(name); ok {
+			previous[name] = &old
+		} else {
+			previous[name] = nil
+		}
+		os.Setenv(name, value)
+	}
+
+	defer func() {
+		for name, old := range previous {
+			if old != nil {
+				os.Setenv(name, *old)
+			} else {
+				os.Unsetenv(name)
+			}
+		}
+	}()
+
+	return action()
+}
+
+// Sets environment variables applied while the component is opened, for components
+// like third-party SDKs that read their configuration only from the process environment.
+// Parameters:
+//   - component interface{}
+//   a component to set the environment.
+//   - env map[string]string
+//   environment variables of the component.
+func (c *RunReferencesDecorator) SetEnvironment(component interface{}, env map[string]string) {
+	index := indexOfComponent(c.environed, component)
+	if index >= 0 {
+		c.environments[index] = env
+		return
+	}
+	c.environed = append(c.environed, component)
+	c.environments = append(c.environments, env)
+}
+
+// Gets environment variables applied while the component is opened.
+// Parameters:
+//   - component interface{}
+//   a component to get the environment.
+// Returns map[string]string
+// environment variables or nil when they were not set.
+func (c *RunReferencesDecorator) GetEnvironment(component interface{}) map[string]string {
+	index := indexOfComponent(c.environed, component)
+	if index < 0 {
+		return nil
+	}
+	return c.environments[index]
+}
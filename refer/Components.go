@@ -0,0 +1,43 @@
+package refer
+
+import (
+	"reflect"
+
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+// Checks if two references point to the same component.
+// Components of non-comparable types are never considered equal to avoid runtime panics.
+func sameComponent(component1 interface{}, component2 interface{}) bool {
+	if component1 == nil || component2 == nil {
+		return component1 == nil && component2 == nil
+	}
+
+	typ := reflect.TypeOf(component1)
+	if typ != reflect.TypeOf(component2) || !typ.Comparable() {
+		return false
+	}
+
+	return component1 == component2
+}
+
+// Finds position of the component in the list or returns -1 if it is not found.
+func indexOfComponent(components []interface{}, component interface{}) int {
+	for index, item := range components {
+		if sameComponent(item, component) {
+			return index
+		}
+	}
+	return -1
+}
+
+// Converts a value recovered from panic into error.
+func panicToError(correlationId string, r interface{}) error {
+	if err, ok := r.(error); ok {
+		return err
+	}
+	return cerr.NewInternalError(
+		correlationId, "PANIC", "Component panicked: "+cconv.StringConverter.ToString(r),
+	)
+}
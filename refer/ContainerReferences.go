@@ -1,12 +1,19 @@
 package refer
 
 import (
+	"context"
 	"fmt"
+	goreflect "reflect"
+	"sort"
+	"strings"
+	"time"
 
 	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
 	"github.com/pip-services3-go/pip-services3-commons-go/refer"
 	"github.com/pip-services3-go/pip-services3-commons-go/reflect"
 	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
 	"github.com/pip-services3-go/pip-services3-container-go/config"
 )
 
@@ -15,6 +22,11 @@ Container managed references that can be created from container configuration.
 */
 type ContainerReferences struct {
 	ManagedReferences
+	components []interface{}
+	configs    []*config.ComponentConfig
+	skipped    []*config.ComponentConfig
+	lenient    bool
+	logger     log.ILogger
 }
 
 // Creates a new instance of the references
@@ -25,7 +37,33 @@ func NewContainerReferences() *ContainerReferences {
 	}
 }
 
+// Sets the logger used to trace creation of components.
+// Parameters:
+//  - logger log.ILogger
+//  a logger to be set.
+func (c *ContainerReferences) SetLogger(logger log.ILogger) {
+	c.logger = logger
+	c.Runner.logger = logger
+}
+
+// Sets lenient mode, that skips all components with descriptors no registered factory can create,
+// like components marked with "optional" parameter, instead of failing.
+// Parameters:
+//  - lenient bool
+//  true to skip unresolvable components.
+func (c *ContainerReferences) SetLenient(lenient bool) {
+	c.lenient = lenient
+}
+
+// Gets configurations of components that were skipped because no registered factory can create them.
+// Returns []*config.ComponentConfig
+func (c *ContainerReferences) GetSkippedComponents() []*config.ComponentConfig {
+	return c.skipped
+}
+
 // Puts components into the references from container configuration.
+// Components marked with "optional" parameter are skipped with a warning when no registered factory
+// can create them, so one configuration can be shared across deployments with different factories.
 // Parameters:
 //  - config config.ContainerConfig
 //  a container configuration with information of components to be added.
@@ -42,20 +80,52 @@ func (c *ContainerReferences) PutFromConfig(config config.ContainerConfig) error
 		}
 	}()
 
+	err = config.Validate("")
+	if err != nil {
+		return err
+	}
+
+	// Order components by their declared dependencies
+	config, err = config.SortByDependencies()
+	if err != nil {
+		return err
+	}
+
 	for _, componentConfig := range config {
-		if componentConfig.Type != nil {
-			// Create component dynamically
-			locator = componentConfig.Type
-			component, err = reflect.TypeReflector.CreateInstanceByDescriptor(componentConfig.Type)
-		} else if componentConfig.Descriptor != nil {
-			// Or create component statically
-			locator = componentConfig.Descriptor
-			factory := c.ManagedReferences.Builder.FindFactory(locator)
-			component = c.ManagedReferences.Builder.Create(locator, factory)
-			if component == nil {
-				return refer.NewReferenceError("", locator)
+		// Skip optional components that no registered factory can create
+		if (c.lenient || isOptional(componentConfig)) && c.isUnresolvable(componentConfig) {
+			if c.logger != nil {
+				c.logger.Warn("", "Skipped component %v that no factory can create", componentConfig.Descriptor)
+			} else {
+				fmt.Printf("Skipped component %v that no factory can create\n", componentConfig.Descriptor)
+			}
+			c.skipped = append(c.skipped, componentConfig)
+			continue
+		}
+
+		sharedKey := ""
+		created := true
+		if componentConfig.Config != nil && componentConfig.Config.GetAsBooleanWithDefault("shared", false) {
+			// Take the component created by another container in the process
+			sharedKey = getSharedKey(componentConfig)
+			var shared interface{}
+			shared, created, err = SharedComponents.Acquire(sharedKey, func() (interface{}, error) {
+				var instance interface{}
+				instance, locator, err = c.createComponent(componentConfig)
+				return instance, err
+			})
+			if err != nil {
+				return err
+			}
+			if !created {
+				locator = getComponentLocator(componentConfig)
+			}
+			component = shared
+		} else {
+			component, locator, err = c.createComponent(componentConfig)
+			if err != nil {
+				return err
 			}
-			locator = c.ManagedReferences.Builder.ClarifyLocator(locator, factory)
 		}
 
 		// Check that component was created
@@ -66,15 +136,77 @@ func (c *ContainerReferences) PutFromConfig(config config.ContainerConfig) error
 			).WithDetails("config", config)
 		}
 
-		fmt.Printf("Created component %v\n", locator)
+		if !created {
+			if c.logger != nil {
+				c.logger.Debug("", "Referenced shared component %v", locator)
+			}
+		} else if c.logger != nil {
+			c.logger.Debug("", "Created component %v", locator)
+		} else {
+			fmt.Printf("Created component %v\n", locator)
+		}
 
 		// Add component to the list
 		c.ManagedReferences.References.Put(locator, component)
+		c.components = append(c.components, component)
+		c.configs = append(c.configs, componentConfig)
 
-		// Configure component
+		if sharedKey != "" {
+			c.Runner.SetShared(component, sharedKey)
+			c.Linker.SetShared(component, sharedKey)
+		}
+		if !created {
+			// Shared component is already configured by the container that created it
+			continue
+		}
+
+		if componentConfig.Config != nil {
+			// Set priority to resolve the component among multiple matches
+			priority := componentConfig.Config.GetAsIntegerWithDefault("resolution_priority", 0)
+			if priority != 0 {
+				c.SetResolutionPriority(component, priority)
+			}
+
+			// Set deadlines for context-aware components
+			openTimeout := componentConfig.Config.GetAsLongWithDefault("open_timeout", 0)
+			closeTimeout := componentConfig.Config.GetAsLongWithDefault("close_timeout", 0)
+			if openTimeout > 0 || closeTimeout > 0 {
+				c.Runner.SetTimeouts(component,
+					time.Duration(openTimeout)*time.Millisecond, time.Duration(closeTimeout)*time.Millisecond)
+			}
+
+			// Retry components that fail with transient errors
+			if policy := ReadRetryPolicyFromConfig(componentConfig.Config); policy != nil {
+				c.Runner.SetRetryPolicy(component, policy)
+			}
+		}
+
+		// Configure component with its environment variables
+		env := readEnvironment(componentConfig.Config)
+		if env != nil {
+			c.Runner.SetEnvironment(component, env)
+		}
 		configurable, ok := component.(cconfig.IConfigurable)
 		if ok {
-			configurable.Configure(componentConfig.Config)
+			withEnvironment(env, func() error {
+				configurable.Configure(componentConfig.Config)
+				return nil
+			})
+		}
+
+		// Inject dependencies declared in the configuration
+		err = c.injectDependencyResolver(component, componentConfig)
+		if err != nil {
+			return err
+		}
+
+		// Warn about configuration that the component ignores
+		for _, warning := range checkConformance(component, componentConfig) {
+			if c.logger != nil {
+				c.logger.Warn("", "Component %v: %s", locator, warning)
+			} else {
+				fmt.Printf("Component %v: %s\n", locator, warning)
+			}
 		}
 
 		// Set references to factories
@@ -89,3 +221,488 @@ func (c *ContainerReferences) PutFromConfig(config config.ContainerConfig) error
 
 	return err
 }
+
+// Creates the component from its configuration by type or by descriptor with registered factories.
+// Environment variables of the component are set while it is created.
+func (c *ContainerReferences) createComponent(componentConfig *config.ComponentConfig) (component interface{},
+	locator interface{}, err error) {
+	withEnvironment(readEnvironment(componentConfig.Config), func() error {
+		component, locator, err = c.newComponent(componentConfig)
+		return err
+	})
+	return component, locator, err
+}
+
+func (c *ContainerReferences) newComponent(componentConfig *config.ComponentConfig) (interface{}, interface{}, error) {
+	if componentConfig.Type != nil {
+		// Create component dynamically
+		component, err := reflect.TypeReflector.CreateInstanceByDescriptor(componentConfig.Type)
+		return component, componentConfig.Type, err
+	}
+	if componentConfig.Descriptor == nil {
+		return nil, nil, nil
+	}
+
+	// Or create component statically
+	var locator interface{} = componentConfig.Descriptor
+	factory := c.ManagedReferences.Builder.FindFactory(locator)
+	timeout := DefaultCreateTimeout
+	if componentConfig.Config != nil {
+		timeout = time.Duration(componentConfig.Config.GetAsLongWithDefault(
+			"create_timeout", timeout.Milliseconds())) * time.Millisecond
+	}
+	component, err := c.ManagedReferences.Builder.CreateWithTimeout(locator, factory, timeout)
+	if err != nil {
+		return nil, locator, err
+	}
+	if component == nil {
+		return nil, locator, refer.NewReferenceError("", locator)
+	}
+	return component, c.ManagedReferences.Builder.ClarifyLocator(locator, factory), nil
+}
+
+// Checks if the component is defined by a descriptor that no registered factory can create.
+func (c *ContainerReferences) isUnresolvable(componentConfig *config.ComponentConfig) bool {
+	return componentConfig.Type == nil && componentConfig.Descriptor != nil &&
+		c.ManagedReferences.Builder.FindFactory(componentConfig.Descriptor) == nil
+}
+
+// Checks if the component is marked with "optional" parameter.
+func isOptional(componentConfig *config.ComponentConfig) bool {
+	return componentConfig.Config != nil && componentConfig.Config.GetAsBooleanWithDefault("optional", false)
+}
+
+// Gets the locator of the component configuration.
+func getComponentLocator(componentConfig *config.ComponentConfig) interface{} {
+	if componentConfig.Type != nil {
+		return componentConfig.Type
+	}
+	return componentConfig.Descriptor
+}
+
+// Gets the key of the shared component in the registry of shared components.
+func getSharedKey(componentConfig *config.ComponentConfig) string {
+	if componentConfig.Type != nil {
+		return "type:" + componentConfig.Type.String()
+	}
+	return componentConfig.Descriptor.String()
+}
+
+// Checks that the component implements interfaces required by its configuration,
+// to catch configuration silently ignored by the component.
+func checkConformance(component interface{}, componentConfig *config.ComponentConfig) []string {
+	warnings := []string{}
+	if componentConfig.Config == nil {
+		return warnings
+	}
+
+	if _, ok := component.(cconfig.IConfigurable); !ok {
+		keys := []string{}
+		for _, key := range componentConfig.Config.Keys() {
+			if !config.IsContainerKey(key) {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 {
+			sort.Strings(keys)
+			warnings = append(warnings, "configuration parameters "+strings.Join(keys, ", ")+
+				" are ignored because the component does not implement IConfigurable")
+		}
+	}
+
+	_, referenceable := component.(refer.IReferenceable)
+	_, resolvable := component.(IDependencyResolvable)
+	if !referenceable && !resolvable {
+		if len(componentConfig.Config.GetSection("dependencies").Keys()) > 0 {
+			warnings = append(warnings, "dependencies are ignored because the component does not implement IReferenceable")
+		}
+	}
+
+	return warnings
+}
+
+// Builds DependencyResolver from "dependencies" section of the component configuration
+// and injects it into the component that implements IDependencyResolvable.
+func (c *ContainerReferences) injectDependencyResolver(component interface{},
+	componentConfig *config.ComponentConfig) error {
+	resolvable, ok := component.(IDependencyResolvable)
+	if !ok || componentConfig.Config == nil {
+		return nil
+	}
+
+	resolver := refer.NewDependencyResolver()
+	dependencies := componentConfig.Config.GetSection("dependencies")
+	for _, name := range dependencies.Keys() {
+		value := dependencies.GetAsString(name)
+		descriptor, err := refer.ParseDescriptorFromString(value)
+		if err != nil || descriptor == nil {
+			return cerr.NewConfigError(
+				"", "BAD_DEPENDENCY", "Dependency "+name+" has invalid descriptor "+value,
+			).WithDetails("name", name).WithDetails("descriptor", value).WithCause(err)
+		}
+		resolver.Put(name, descriptor)
+	}
+	resolver.SetReferences(c)
+
+	resolvable.SetDependencyResolver(resolver)
+	return nil
+}
+
+// Creates an immutable snapshot of the references that is safe to hand over
+// to request-handling goroutines. Components added or removed after the call
+// are not visible in the snapshot.
+// Returns *ReadOnlyReferences
+func (c *ContainerReferences) CloneReadOnly() *ReadOnlyReferences {
+	return NewReadOnlyReferences(c)
+}
+
+// Gets configuration of the component that was created from container configuration.
+// Parameters:
+//   - component interface{}
+//   a component created by PutFromConfig.
+// Returns *config.ComponentConfig
+// the component configuration or nil if the component was added directly.
+func (c *ContainerReferences) GetComponentConfig(component interface{}) *config.ComponentConfig {
+	index := indexOfComponent(c.components, component)
+	if index < 0 {
+		return nil
+	}
+	return c.configs[index]
+}
+
+// Gets locator under which the component was registered in the references.
+// Parameters:
+//   - component interface{}
+//   a registered component.
+// Returns interface{}
+// the component locator or nil if the component is not found.
+func (c *ContainerReferences) GetComponentLocator(component interface{}) interface{} {
+	index := indexOfComponent(c.GetAll(), component)
+	locators := c.GetAllLocators()
+	if index < 0 || index >= len(locators) {
+		return nil
+	}
+	return locators[index]
+}
+
+// Checks that components declared in "depends_on" of component configurations are present in the references.
+// Returns error
+// ReferenceError for the first missing dependency.
+func (c *ContainerReferences) ValidateDependencies(correlationId string) error {
+	for _, componentConfig := range c.configs {
+		for _, dependency := range componentConfig.DependsOn {
+			if len(c.GetOptional(dependency)) == 0 {
+				err := refer.NewReferenceError(correlationId, dependency)
+				if componentConfig.Descriptor != nil {
+					err = err.WithDetails("descriptor", componentConfig.Descriptor.String())
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Gets components the component depends on. Dependencies are declared in "depends_on"
+// or in "dependencies" section of the component configuration used by DependencyResolver.
+// Parameters:
+//   - component interface{}
+//   a component to get dependencies.
+// Returns []interface{}
+// a list of components the component depends on.
+func (c *ContainerReferences) GetDependencies(component interface{}) []interface{} {
+	result := []interface{}{}
+
+	componentConfig := c.GetComponentConfig(component)
+	if componentConfig == nil {
+		return result
+	}
+
+	locators := []interface{}{}
+	for _, dependency := range componentConfig.DependsOn {
+		locators = append(locators, dependency)
+	}
+	if componentConfig.Config != nil {
+		dependencies := componentConfig.Config.GetSection("dependencies")
+		for _, key := range dependencies.Keys() {
+			descriptor, err := refer.ParseDescriptorFromString(dependencies.GetAsString(key))
+			if err == nil && descriptor != nil {
+				locators = append(locators, descriptor)
+			}
+		}
+	}
+
+	for _, locator := range locators {
+		for _, dependency := range c.GetOptional(locator) {
+			if !sameComponent(dependency, component) && indexOfComponent(result, dependency) < 0 {
+				result = append(result, dependency)
+			}
+		}
+	}
+	return result
+}
+
+// Gets components that depend on the component, answering "what breaks if the component is restarted".
+// Parameters:
+//   - component interface{}
+//   a component to get dependents.
+//   - transitive bool
+//   true to include components that depend on the component indirectly.
+// Returns []interface{}
+// a list of dependent components.
+func (c *ContainerReferences) GetDependents(component interface{}, transitive bool) []interface{} {
+	result := []interface{}{}
+	targets := []interface{}{component}
+
+	for index := 0; index < len(targets); index++ {
+		for _, candidate := range c.components {
+			if sameComponent(candidate, component) || indexOfComponent(result, candidate) >= 0 {
+				continue
+			}
+			if indexOfComponent(c.GetDependencies(candidate), targets[index]) >= 0 {
+				result = append(result, candidate)
+				if transitive {
+					targets = append(targets, candidate)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// Adds dependencies of the given components, transitively.
+// Parameters:
+//   - components []interface{}
+//   components to start from.
+// Returns []interface{}
+// the given components followed by all their dependencies.
+func (c *ContainerReferences) WithDependencies(components []interface{}) []interface{} {
+	result := append([]interface{}{}, components...)
+	for index := 0; index < len(result); index++ {
+		for _, dependency := range c.GetDependencies(result[index]) {
+			if indexOfComponent(result, dependency) < 0 {
+				result = append(result, dependency)
+			}
+		}
+	}
+	return result
+}
+
+// Excludes from automatic opening all components except the given ones and components of the given types.
+// Parameters:
+//   - components []interface{}
+//   components that shall be opened.
+//   - types ...string
+//   descriptor types of components that shall be opened as well, for instance "logger".
+func (c *ContainerReferences) ExcludeAllExcept(components []interface{}, types ...string) {
+	locators := c.GetAllLocators()
+	for index, component := range c.GetAll() {
+		if indexOfComponent(components, component) >= 0 {
+			continue
+		}
+
+		keep := false
+		if index < len(locators) {
+			if descriptor, ok := locators[index].(*refer.Descriptor); ok {
+				for _, typ := range types {
+					keep = keep || descriptor.Type() == typ
+				}
+			}
+		}
+
+		if !keep {
+			c.Runner.Exclude(component)
+		}
+	}
+}
+
+// Puts components into the opened references from container configuration.
+// New components receive references and are opened, unless they are excluded from automatic opening.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - config config.ContainerConfig
+//  configurations of components to be added.
+// Returns error
+// CreateError when one of component cannot be created or an error of the component that failed to open.
+func (c *ContainerReferences) AddFromConfig(correlationId string, config config.ContainerConfig) error {
+	count := len(c.components)
+	err := c.PutFromConfig(config)
+	added := c.components[count:]
+
+	for _, component := range added {
+		if c.Linker.IsOpen() && !c.Linker.IsExcluded(component) {
+			c.Linker.link(component)
+		}
+	}
+
+	if err == nil && c.Runner.IsOpen() {
+		for _, component := range added {
+			if c.Runner.IsExcluded(component) {
+				continue
+			}
+			err = c.Runner.openComponent(context.Background(), correlationId, c.GetComponentLocator(component), component)
+			if err != nil {
+				break
+			}
+		}
+	}
+
+	return err
+}
+
+// Removes the component created from container configuration.
+// The component is closed and its references are unset when the references are opened.
+// Parameters:
+//  - component interface{}
+//  a component to be removed.
+func (c *ContainerReferences) RemoveComponent(component interface{}) {
+	c.Remove(component)
+	if !c.Runner.IsOpen() {
+		c.Runner.releaseShared(component)
+	}
+
+	index := indexOfComponent(c.components, component)
+	if index >= 0 {
+		c.components = append(c.components[:index:index], c.components[index+1:]...)
+		c.configs = append(c.configs[:index:index], c.configs[index+1:]...)
+	}
+}
+
+// Applies the updated configuration to the component. The opened component is closed,
+// configured with new parameters and opened again, so it keeps references held by other components.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - component interface{}
+//  a component created from container configuration.
+//  - componentConfig *config.ComponentConfig
+//  the updated component configuration.
+// Returns error
+// an error of the component that failed to close or open.
+func (c *ContainerReferences) ReconfigureComponent(correlationId string,
+	component interface{}, componentConfig *config.ComponentConfig) error {
+	index := indexOfComponent(c.components, component)
+	if index >= 0 {
+		c.configs[index] = componentConfig
+	}
+
+	// Shared components keep the configuration of the container that created them
+	if c.Runner.GetSharedKey(component) != "" {
+		return nil
+	}
+
+	locator := c.GetComponentLocator(component)
+	opened := c.Runner.IsOpen() && !c.Runner.IsExcluded(component)
+	if opened {
+		err := c.Runner.closeComponent(context.Background(), correlationId, locator, component)
+		if err != nil {
+			return err
+		}
+	}
+
+	env := readEnvironment(componentConfig.Config)
+	c.Runner.SetEnvironment(component, env)
+	if configurable, ok := component.(cconfig.IConfigurable); ok {
+		withEnvironment(env, func() error {
+			configurable.Configure(componentConfig.Config)
+			return nil
+		})
+	}
+	err := c.injectDependencyResolver(component, componentConfig)
+	if err != nil {
+		return err
+	}
+
+	if opened {
+		return c.Runner.openComponent(context.Background(), correlationId, locator, component)
+	}
+	return nil
+}
+
+/*
+Fingerprint of a component created from container configuration: its configuration
+and the components it depends on. Components with unchanged fingerprints can be reused
+as they are when the container reloads its configuration.
+*/
+type ComponentFingerprint struct {
+	Config       *config.ComponentConfig
+	Dependencies []interface{}
+}
+
+// Checks if the fingerprints have equal configuration parameters and the same dependencies.
+// Parameters:
+//  - other *ComponentFingerprint
+//  a fingerprint to compare with.
+// Returns bool
+// true if the fingerprints are equal.
+func (c *ComponentFingerprint) Equals(other *ComponentFingerprint) bool {
+	if other == nil || len(c.Dependencies) != len(other.Dependencies) {
+		return false
+	}
+	for index, dependency := range c.Dependencies {
+		if !sameComponent(dependency, other.Dependencies[index]) {
+			return false
+		}
+	}
+
+	if c.Config == nil || other.Config == nil {
+		return c.Config == other.Config
+	}
+	if c.Config.Config == nil || other.Config.Config == nil {
+		return c.Config.Config == other.Config.Config
+	}
+	return goreflect.DeepEqual(c.Config.Config.Value(), other.Config.Config.Value())
+}
+
+// Gets the fingerprint of the component created from container configuration.
+// Parameters:
+//  - component interface{}
+//  a component created by PutFromConfig.
+// Returns *ComponentFingerprint
+// the fingerprint or nil if the component was added directly.
+func (c *ContainerReferences) GetFingerprint(component interface{}) *ComponentFingerprint {
+	componentConfig := c.GetComponentConfig(component)
+	if componentConfig == nil {
+		return nil
+	}
+	return &ComponentFingerprint{
+		Config:       componentConfig,
+		Dependencies: c.GetDependencies(component),
+	}
+}
+
+// Links the component with the current references again, for instance when components it
+// depends on were replaced. The opened component is closed, relinked and opened again.
+// Parameters:
+//  - correlationId string
+//  transaction id to trace execution through call chain.
+//  - component interface{}
+//  a component to be relinked.
+// Returns error
+// an error of the component that failed to close or open.
+func (c *ContainerReferences) RelinkComponent(correlationId string, component interface{}) error {
+	// Shared components are linked by the container that created them
+	if c.Runner.GetSharedKey(component) != "" || c.Linker.IsExcluded(component) {
+		return nil
+	}
+
+	locator := c.GetComponentLocator(component)
+	opened := c.Runner.IsOpen() && !c.Runner.IsExcluded(component)
+	if opened {
+		err := c.Runner.closeComponent(context.Background(), correlationId, locator, component)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Linker.IsOpen() {
+		unsetReferences(component)
+		refer.Referencer.SetReferencesForOne(c, component)
+	}
+
+	if opened {
+		return c.Runner.openComponent(context.Background(), correlationId, locator, component)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package refer
+
+import (
+	"context"
+	goerrors "errors"
+	"time"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/convert"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-commons-go/run"
+)
+
+/*
+Interface for components that accept a context when they are opened,
+so blocking operations inside, like DNS lookups or connection handshakes, can be cancelled.
+When a component implements both IContextOpenable and IOpenable, the container calls OpenWithContext.
+
+see
+RunReferencesDecorator.SetTimeouts
+*/
+type IContextOpenable interface {
+	// Opens the component.
+	// Parameters:
+	//   - ctx context.Context
+	//   a context with the open deadline.
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	// Returns error
+	OpenWithContext(ctx context.Context, correlationId string) error
+}
+
+/*
+Interface for components that accept a context when they are closed.
+When a component implements both IContextClosable and IClosable, the container calls CloseWithContext.
+
+see
+RunReferencesDecorator.SetTimeouts
+*/
+type IContextClosable interface {
+	// Closes the component and frees used resources.
+	// Parameters:
+	//   - ctx context.Context
+	//   a context with the close deadline.
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	// Returns error
+	CloseWithContext(ctx context.Context, correlationId string) error
+}
+
+// Opens the component with a context derived from the parent context.
+// When the deadline is exceeded it returns an error with OPEN_TIMEOUT code.
+func openWithContext(ctx context.Context, correlationId string, locator interface{},
+	component interface{}, timeout time.Duration) error {
+	openable, ok := component.(IContextOpenable)
+	if !ok {
+		return run.Opener.OpenOne(correlationId, component)
+	}
+
+	ctx, cancel := withOptionalTimeout(ctx, timeout)
+	defer cancel()
+
+	err := openable.OpenWithContext(ctx, correlationId)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return errors.NewInternalError(
+			correlationId, "OPEN_TIMEOUT",
+			"Component "+convert.StringConverter.ToString(locator)+" was not opened in time",
+		).WithDetails("locator", locator).WithDetails("timeout", timeout.Milliseconds()).WithCause(err)
+	}
+	return err
+}
+
+// Closes the component with a context derived from the parent context.
+// When the deadline is exceeded it returns an error with CLOSE_TIMEOUT code.
+func closeWithContext(ctx context.Context, correlationId string, locator interface{},
+	component interface{}, timeout time.Duration) error {
+	closable, ok := component.(IContextClosable)
+	if !ok {
+		return run.Closer.CloseOne(correlationId, component)
+	}
+
+	ctx, cancel := withOptionalTimeout(ctx, timeout)
+	defer cancel()
+
+	err := closable.CloseWithContext(ctx, correlationId)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return errors.NewInternalError(
+			correlationId, "CLOSE_TIMEOUT",
+			"Component "+convert.StringConverter.ToString(locator)+" was not closed in time",
+		).WithDetails("locator", locator).WithDetails("timeout", timeout.Milliseconds()).WithCause(err)
+	}
+	return err
+}
+
+// Creates an error for the component abandoned when closing deadline was exceeded.
+func newAbandonedError(correlationId string, locator interface{}) error {
+	return errors.NewInternalError(
+		correlationId, "CLOSE_TIMEOUT",
+		"Component "+convert.StringConverter.ToString(locator)+" was abandoned at shutdown deadline",
+	).WithDetails("locator", locator)
+}
+
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Checks if the error was returned because the component exceeded its open or close deadline.
+func isTimeoutError(err error) bool {
+	var appErr *errors.ApplicationError
+	return goerrors.As(err, &appErr) && (appErr.Code == "OPEN_TIMEOUT" || appErr.Code == "CLOSE_TIMEOUT")
+}
@@ -0,0 +1,133 @@
+package refer
+
+import (
+	"sync"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+)
+
+// Function called to open a component.
+type OpenFunc func(correlationId string) error
+
+// Function called to close a component.
+type CloseFunc func(correlationId string) error
+
+// Function called to configure a component.
+type ConfigureFunc func(config *config.ConfigParams)
+
+// Function called to pass references of other components.
+type SetReferencesFunc func(references refer.IReferences)
+
+/*
+Component implemented by plain functions. It allows to add small glue components
+to container configurations without declaring structs that implement IConfigurable,
+IReferenceable, IOpenable and IClosable interfaces. Functions that are not set are skipped.
+
+Example
+  factory.Register(MyHookDescriptor, func(locator interface{}) interface{} {
+      var message string
+      return &refer.FuncComponent{
+          OnConfigure: func(config *cconf.ConfigParams) {
+              message = config.GetAsString("message")
+          },
+          OnOpen: func(correlationId string) error {
+              fmt.Println(message)
+              return nil
+          },
+      }
+  })
+*/
+type FuncComponent struct {
+	OnConfigure  ConfigureFunc
+	OnReferences SetReferencesFunc
+	OnOpen       OpenFunc
+	OnClose      CloseFunc
+	opened       bool
+	lock         sync.Mutex
+}
+
+// Creates a new component with open and close functions.
+// Parameters:
+//   - open OpenFunc
+//   a function called to open the component or nil.
+//   - close CloseFunc
+//   a function called to close the component or nil.
+// Returns *FuncComponent
+func NewFuncComponent(open OpenFunc, close CloseFunc) *FuncComponent {
+	return &FuncComponent{
+		OnOpen:  open,
+		OnClose: close,
+	}
+}
+
+// Configures component by passing configuration parameters.
+// Parameters:
+//   - config *config.ConfigParams
+//   configuration parameters to be set.
+func (c *FuncComponent) Configure(config *config.ConfigParams) {
+	if c.OnConfigure != nil {
+		c.OnConfigure(config)
+	}
+}
+
+// Sets references to dependent components.
+// Parameters:
+//   - references refer.IReferences
+//   references to locate the component dependencies.
+func (c *FuncComponent) SetReferences(references refer.IReferences) {
+	if c.OnReferences != nil {
+		c.OnReferences(references)
+	}
+}
+
+// Checks if the component is opened.
+// Returns bool
+func (c *FuncComponent) IsOpen() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.opened
+}
+
+// Opens the component.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+func (c *FuncComponent) Open(correlationId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.opened {
+		return nil
+	}
+	if c.OnOpen != nil {
+		if err := c.OnOpen(correlationId); err != nil {
+			return err
+		}
+	}
+	c.opened = true
+	return nil
+}
+
+// Closes the component.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+func (c *FuncComponent) Close(correlationId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.opened {
+		return nil
+	}
+	if c.OnClose != nil {
+		if err := c.OnClose(correlationId); err != nil {
+			return err
+		}
+	}
+	c.opened = false
+	return nil
+}
@@ -0,0 +1,38 @@
+package refer
+
+import (
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+)
+
+/*
+Interface for components that receive a DependencyResolver built by the container
+from "dependencies" section of their configuration. Components declare which descriptor
+satisfies each named dependency in configuration instead of hard-coding descriptors.
+
+The resolver is injected after the component is configured and before its references are set,
+so the component can resolve its dependencies in SetReferences.
+
+Example
+  - descriptor: "mygroup:controller:default:default:1.0"
+    dependencies:
+      persistence: "mygroup:persistence:mongodb:default:1.0"
+
+  func (c *MyController) SetDependencyResolver(resolver *refer.DependencyResolver) {
+      c.dependencyResolver = resolver
+  }
+
+  func (c *MyController) SetReferences(references refer.IReferences) {
+      persistence, err := c.dependencyResolver.GetOneRequired("persistence")
+      ...
+  }
+
+see
+DependencyResolver (in the PipServices "Commons" package)
+*/
+type IDependencyResolvable interface {
+	// Sets the dependency resolver configured with dependencies of the component.
+	// Parameters:
+	//   - resolver *refer.DependencyResolver
+	//   the resolver that locates dependencies in the container references.
+	SetDependencyResolver(resolver *refer.DependencyResolver)
+}
@@ -0,0 +1,18 @@
+package refer
+
+/*
+Interface for components that can report their required dependencies
+that were not resolved after references were set.
+
+The container checks all components right after wiring and fails before any component
+is opened, listing all missing dependencies at once.
+
+see
+ManagedReferences.ValidateReferences
+*/
+type IDependencyValidatable interface {
+	// Gets locators of required dependencies that were not resolved.
+	// Returns []interface{}
+	// a list of missing locators or empty list if all dependencies are resolved.
+	GetMissingDependencies() []interface{}
+}
@@ -0,0 +1,62 @@
+package refer
+
+/*
+Interface for listeners of lifecycle transitions of components.
+It is used to emit custom metrics or notify orchestration systems.
+Listeners are called synchronously and shall return quickly.
+When components are opened in parallel, listeners are called concurrently.
+
+see
+RunReferencesDecorator.AddListener
+*/
+type ILifecycleListener interface {
+	// Called before the component is opened.
+	// Parameters:
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	//   - locator interface{}
+	//   a locator of the component or nil for the container itself.
+	OnOpening(correlationId string, locator interface{})
+
+	// Called after the component is opened.
+	// Parameters:
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	//   - locator interface{}
+	//   a locator of the component or nil for the container itself.
+	OnOpened(correlationId string, locator interface{})
+
+	// Called before the component is closed.
+	// Parameters:
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	//   - locator interface{}
+	//   a locator of the component or nil for the container itself.
+	OnClosing(correlationId string, locator interface{})
+
+	// Called after the component is closed.
+	// Parameters:
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	//   - locator interface{}
+	//   a locator of the component or nil for the container itself.
+	OnClosed(correlationId string, locator interface{})
+
+	// Called when the component failed to open or close.
+	// Parameters:
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	//   - locator interface{}
+	//   a locator of the component or nil for the container itself.
+	//   - err error
+	//   the error of the component.
+	OnFailed(correlationId string, locator interface{}, err error)
+}
+
+// Calls the listener and ignores its panics, so a faulty listener cannot break the lifecycle.
+func callListener(call func()) {
+	defer func() {
+		recover()
+	}()
+	call()
+}
@@ -0,0 +1,20 @@
+package refer
+
+/*
+Interface for components that shall run once before the container starts serving,
+for instance database schema migrations.
+
+Migrations are opened and executed sequentially after references are set and before
+the rest of the components are opened. A failed migration aborts the container startup.
+
+see
+ManagedReferences.Open
+*/
+type IMigration interface {
+	// Executes the migration.
+	// Parameters:
+	//   - correlationId string
+	//   transaction id to trace execution through call chain.
+	// Returns error
+	Migrate(correlationId string) error
+}
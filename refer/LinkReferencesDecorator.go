@@ -7,10 +7,18 @@ import (
 /*
 References decorator that automatically sets references to newly added components that implement IReferenceable
 interface and unsets references from removed components that implement IUnreferenceable interface.
+Excluded components are not linked.
+
+Shared components (see SharedComponentRegistry) are linked with references of the first container that opens them.
+When that container closes, they are linked again with references of the next container that holds them,
+and their references are unset only when the last container closes.
 */
 type LinkReferencesDecorator struct {
 	ReferencesDecorator
-	opened bool
+	opened     bool
+	excluded   []interface{}
+	shared     []interface{}
+	sharedKeys []string
 }
 
 // Creates a new instance of the decorator.
@@ -42,8 +50,9 @@ func (c *LinkReferencesDecorator) IsOpen() bool {
 func (c *LinkReferencesDecorator) Open(correlationId string) error {
 	if !c.opened {
 		c.opened = true
-		components := c.GetAll()
-		crefer.Referencer.SetReferences(c.ReferencesDecorator.TopReferences, components)
+		for _, component := range c.getLinked() {
+			c.link(component)
+		}
 	}
 	return nil
 }
@@ -56,12 +65,111 @@ func (c *LinkReferencesDecorator) Open(correlationId string) error {
 func (c *LinkReferencesDecorator) Close(correlationId string) error {
 	if c.opened {
 		c.opened = false
-		components := c.GetAll()
-		crefer.Referencer.UnsetReferences(components)
+		for _, component := range c.getLinked() {
+			c.unlink(component)
+		}
 	}
 	return nil
 }
 
+// Excludes the component from linking, so its references are neither set nor unset.
+// Parameters:
+//   - component interface{}
+//   a component to be excluded.
+func (c *LinkReferencesDecorator) Exclude(component interface{}) {
+	if !c.IsExcluded(component) {
+		c.excluded = append(c.excluded, component)
+	}
+}
+
+// Marks the component as shared with other containers (see SharedComponentRegistry),
+// so it is linked with references of one container at a time.
+// Parameters:
+//   - component interface{}
+//   a component acquired from the registry of shared components.
+//   - key string
+//   a key of the component in the registry.
+func (c *LinkReferencesDecorator) SetShared(component interface{}, key string) {
+	index := indexOfComponent(c.shared, component)
+	if index >= 0 {
+		c.sharedKeys[index] = key
+		return
+	}
+	c.shared = append(c.shared, component)
+	c.sharedKeys = append(c.sharedKeys, key)
+}
+
+// Checks if the component is excluded from linking.
+// Parameters:
+//   - component interface{}
+//   a component to be checked.
+// Returns bool
+// true if the component is excluded and false otherwise.
+func (c *LinkReferencesDecorator) IsExcluded(component interface{}) bool {
+	return indexOfComponent(c.excluded, component) >= 0
+}
+
+// Gets all components that are not excluded from linking.
+func (c *LinkReferencesDecorator) getLinked() []interface{} {
+	components := []interface{}{}
+	for _, component := range c.GetAll() {
+		if !c.IsExcluded(component) {
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+// Hands shared components over to other containers that hold them without unsetting
+// references of the rest of the components, for instance when some components failed to close.
+func (c *LinkReferencesDecorator) detachShared() {
+	if !c.opened {
+		return
+	}
+	for _, component := range c.shared {
+		if !c.IsExcluded(component) {
+			c.unlink(component)
+		}
+	}
+}
+
+// Sets references to a single component. Shared components are linked only
+// when these references are the first to hold them.
+func (c *LinkReferencesDecorator) link(component interface{}) {
+	index := indexOfComponent(c.shared, component)
+	if index >= 0 && !SharedComponents.Attach(c.sharedKeys[index], c.ReferencesDecorator.TopReferences) {
+		return
+	}
+	crefer.Referencer.SetReferencesForOne(c.ReferencesDecorator.TopReferences, component)
+}
+
+// Unsets references of a single component. Shared components that are still held
+// by other containers are linked with references of the next container instead.
+func (c *LinkReferencesDecorator) unlink(component interface{}) {
+	index := indexOfComponent(c.shared, component)
+	if index < 0 {
+		unsetReferences(component)
+		return
+	}
+
+	next, remaining := SharedComponents.Detach(c.sharedKeys[index], c.ReferencesDecorator.TopReferences)
+	if next != nil {
+		crefer.Referencer.SetReferencesForOne(next, component)
+	} else if remaining == 0 {
+		unsetReferences(component)
+	}
+}
+
+// Unsets references of a single component ignoring its panics,
+// so one failing component cannot prevent the rest from unlinking.
+func unsetReferences(component interface{}) {
+	defer func() {
+		recover()
+	}()
+
+	crefer.Referencer.UnsetReferencesForOne(component)
+}
+
 // Puts a new reference into this reference map.
 // Parameters:
 //   - locator intrface{}
@@ -71,8 +179,8 @@ func (c *LinkReferencesDecorator) Close(correlationId string) error {
 func (c *LinkReferencesDecorator) Put(locator interface{}, component interface{}) {
 	c.ReferencesDecorator.Put(locator, component)
 
-	if c.opened {
-		crefer.Referencer.SetReferencesForOne(c.ReferencesDecorator.TopReferences, component)
+	if c.opened && !c.IsExcluded(component) {
+		c.link(component)
 	}
 }
 
@@ -88,8 +196,8 @@ func (c *LinkReferencesDecorator) Put(locator interface{}, component interface{}
 func (c *LinkReferencesDecorator) Remove(locator interface{}) interface{} {
 	component := c.ReferencesDecorator.Remove(locator)
 
-	if c.opened {
-		crefer.Referencer.UnsetReferencesForOne(component)
+	if c.opened && component != nil && !c.IsExcluded(component) {
+		c.unlink(component)
 	}
 
 	return component
@@ -105,7 +213,11 @@ func (c *LinkReferencesDecorator) RemoveAll(locator interface{}) []interface{} {
 	components := c.NextReferences.RemoveAll(locator)
 
 	if c.opened {
-		crefer.Referencer.UnsetReferences(components)
+		for _, component := range components {
+			if !c.IsExcluded(component) {
+				c.unlink(component)
+			}
+		}
 	}
 
 	return components
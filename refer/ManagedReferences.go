@@ -1,6 +1,12 @@
 package refer
 
 import (
+	"context"
+	"sort"
+	"strings"
+
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
 	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
 )
 
@@ -11,13 +17,19 @@ Auto-creation of missing component using available factories
 Auto-linking newly added components
 Auto-opening newly added components
 Auto-closing removed components
+
+When a locator matches multiple components they are returned in order of their resolution priority
+(higher priority first), so GetOneRequired and GetOneOptional return a deterministic winner.
+Components with equal priority keep their insertion order.
 */
 type ManagedReferences struct {
 	ReferencesDecorator
-	References *crefer.References
-	Builder    *BuildReferencesDecorator
-	Linker     *LinkReferencesDecorator
-	Runner     *RunReferencesDecorator
+	References  *crefer.References
+	Builder     *BuildReferencesDecorator
+	Linker      *LinkReferencesDecorator
+	Runner      *RunReferencesDecorator
+	prioritized []interface{}
+	priorities  []int
 }
 
 // Creates a new instance of the references
@@ -69,22 +81,247 @@ func (c *ManagedReferences) IsOpen() bool {
 //   transaction id to trace execution through call chain.
 // Returns error
 func (c *ManagedReferences) Open(correlationId string) error {
+	return c.OpenWithContext(context.Background(), correlationId)
+}
+
+// Opens the component. Opening of managed components stops when the context is cancelled.
+// Parameters:
+//   - ctx context.Context
+//   a context to cancel opening.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+func (c *ManagedReferences) OpenWithContext(ctx context.Context, correlationId string) error {
 	err := c.Linker.Open(correlationId)
 	if err == nil {
-		err = c.Runner.Open(correlationId)
+		err = c.ValidateReferences(correlationId)
+	}
+	if err == nil {
+		err = c.migrate(ctx, correlationId)
+	}
+	if err == nil {
+		err = c.Runner.OpenWithContext(ctx, correlationId)
 	}
 	return err
 }
 
+// Checks that components implementing IDependencyValidatable interface have all their
+// required dependencies resolved.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+// ReferenceError that lists all missing dependencies of all components.
+func (c *ManagedReferences) ValidateReferences(correlationId string) error {
+	missing := []string{}
+
+	for _, component := range c.GetAll() {
+		validatable, ok := component.(IDependencyValidatable)
+		if !ok {
+			continue
+		}
+		for _, locator := range validatable.GetMissingDependencies() {
+			missing = append(missing, cconv.StringConverter.ToString(locator))
+		}
+	}
+
+	if len(missing) > 0 {
+		return crefer.NewReferenceError(correlationId, strings.Join(missing, ", ")).
+			WithDetails("missing", missing)
+	}
+	return nil
+}
+
+// Opens and executes components that implement IMigration interface one by one.
+// Migrations are opened by the runner, so their timeouts, retry policies and lifecycle listeners apply.
+// Executed migrations are left opened and excluded from automatic opening.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+// the error of the first failed migration.
+func (c *ManagedReferences) Migrate(correlationId string) error {
+	return c.migrate(context.Background(), correlationId)
+}
+
+func (c *ManagedReferences) migrate(ctx context.Context, correlationId string) error {
+	for _, component := range c.GetAll() {
+		migration, ok := component.(IMigration)
+		if !ok || c.Runner.IsExcluded(component) {
+			continue
+		}
+
+		err := c.Runner.OpenComponent(ctx, correlationId, component)
+		if err == nil {
+			err = migration.Migrate(correlationId)
+		}
+		if err != nil {
+			return err
+		}
+
+		c.Runner.Exclude(component)
+	}
+	return nil
+}
+
 // Closes component and frees used resources.
 // Parameters:
 //   - correlationId string
 //   transaction id to trace execution through call chain.
 // Returns error
 func (c *ManagedReferences) Close(correlationId string) error {
-	err := c.Runner.Close(correlationId)
+	return c.CloseWithContext(context.Background(), correlationId)
+}
+
+// Closes component and frees used resources.
+// Parameters:
+//   - ctx context.Context
+//   a context passed to components that implement IContextClosable interface.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+func (c *ManagedReferences) CloseWithContext(ctx context.Context, correlationId string) error {
+	_, err := c.CloseWithReportContext(ctx, correlationId)
+	return err
+}
+
+// Closes the component and reports close duration and outcome of each managed component.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns *CloseReport, error
+// the close report and the first error that happened during close.
+func (c *ManagedReferences) CloseWithReport(correlationId string) (*CloseReport, error) {
+	return c.CloseWithReportContext(context.Background(), correlationId)
+}
+
+// Closes the component and reports close duration and outcome of each managed component.
+// Parameters:
+//   - ctx context.Context
+//   a context passed to components that implement IContextClosable interface.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns *CloseReport, error
+// the close report and the first error that happened during close.
+func (c *ManagedReferences) CloseWithReportContext(ctx context.Context, correlationId string) (*CloseReport, error) {
+	report := c.Runner.CloseWithReportContext(ctx, correlationId)
+	err := report.FirstError()
 	if err == nil {
 		err = c.Linker.Close(correlationId)
+	} else {
+		// Other containers must not keep shared components linked with these references
+		c.Linker.detachShared()
 	}
-	return err
+	return report, err
+}
+
+// Sets resolution priority of the component. When a locator matches multiple components,
+// components with higher priority are returned first. Default priority is 0.
+// Parameters:
+//   - component interface{}
+//   a component to set the priority.
+//   - priority int
+//   the resolution priority.
+func (c *ManagedReferences) SetResolutionPriority(component interface{}, priority int) {
+	index := indexOfComponent(c.prioritized, component)
+	if index >= 0 {
+		c.priorities[index] = priority
+		return
+	}
+	c.prioritized = append(c.prioritized, component)
+	c.priorities = append(c.priorities, priority)
+}
+
+// Gets resolution priority of the component.
+// Parameters:
+//   - component interface{}
+//   a component to get the priority.
+// Returns int
+// the resolution priority or 0 if it was not set.
+func (c *ManagedReferences) GetResolutionPriority(component interface{}) int {
+	index := indexOfComponent(c.prioritized, component)
+	if index < 0 {
+		return 0
+	}
+	return c.priorities[index]
+}
+
+func (c *ManagedReferences) sortByPriority(components []interface{}) []interface{} {
+	if len(components) < 2 || len(c.prioritized) == 0 {
+		return components
+	}
+
+	result := append([]interface{}{}, components...)
+	sort.SliceStable(result, func(i, j int) bool {
+		return c.GetResolutionPriority(result[i]) > c.GetResolutionPriority(result[j])
+	})
+	return result
+}
+
+// Gets all component references that match specified locator ordered by their resolution priority.
+// Parameters:
+//   - locator interface{}
+//   the locator to find a reference by.
+//   - required bool
+//   forces to raise an exception if no reference is found.
+// Returns []interface{}, error
+// a list with matching component references and a ReferenceError when required is set to true but no references found
+func (c *ManagedReferences) Find(locator interface{}, required bool) ([]interface{}, error) {
+	if locator == nil {
+		return nil, cerr.NewBadRequestError("", "NO_LOCATOR", "Locator cannot be nil")
+	}
+	components, err := c.ReferencesDecorator.Find(locator, required)
+	return c.sortByPriority(components), err
+}
+
+// Gets an optional component reference with the highest resolution priority that matches specified locator.
+// Parameters:
+//   - locator interface{}
+//   the locator to find references by.
+// Returns interface{}
+// a matching component reference or null if nothing was found.
+func (c *ManagedReferences) GetOneOptional(locator interface{}) interface{} {
+	components := c.GetOptional(locator)
+	if len(components) == 0 {
+		return nil
+	}
+	return components[0]
+}
+
+// Gets a required component reference with the highest resolution priority that matches specified locator.
+// Parameters:
+//   - locator interface{}
+//   the locator to find a reference by.
+// Returns interface{}, error
+// a matching component reference, a ReferenceError when no references found.
+func (c *ManagedReferences) GetOneRequired(locator interface{}) (interface{}, error) {
+	components, err := c.Find(locator, true)
+	if err != nil || len(components) == 0 {
+		return nil, err
+	}
+	return components[0], nil
+}
+
+// Gets all component references that match specified locator ordered by their resolution priority.
+// Parameters:
+//   - locator interface{}
+//   the locator to find references by.
+// Returns []interface{}
+// a list with matching component references or empty list if nothing was found.
+func (c *ManagedReferences) GetOptional(locator interface{}) []interface{} {
+	if locator == nil {
+		return []interface{}{}
+	}
+	return c.sortByPriority(c.ReferencesDecorator.GetOptional(locator))
+}
+
+// Gets all component references that match specified locator ordered by their resolution priority.
+// At least one component reference must be present. If it doesn't the method throws an error.
+// Parameters:
+//   - locator interface{}
+//   the locator to find references by.
+// Returns []interface{}, error
+// a list with matching component references and error a ReferenceError when no references found.
+func (c *ManagedReferences) GetRequired(locator interface{}) ([]interface{}, error) {
+	return c.Find(locator, true)
 }
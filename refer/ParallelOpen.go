@@ -0,0 +1,91 @@
+package refer
+
+import (
+	"context"
+	"sync"
+)
+
+// Sets the maximum number of components opened concurrently. Components are opened
+// in parallel when all components they depend on are opened. When a component fails,
+// components that were not started yet are not opened, unless the failure is tolerated
+// in continue-on-error mode (see SetContinueOnError).
+// Parameters:
+//   - concurrency int
+//   the maximum number of components opened at once, 1 or less to open components one by one.
+//   - dependencies func(component interface{}) []interface{}
+//   a function that returns components the component depends on.
+func (c *RunReferencesDecorator) SetOpenConcurrency(concurrency int,
+	dependencies func(component interface{}) []interface{}) {
+	c.concurrency = concurrency
+	c.dependencies = dependencies
+}
+
+// Opens components concurrently respecting their dependencies.
+// To avoid deadlocks on circular dependencies a component waits only
+// for dependencies that precede it in the references.
+func (c *RunReferencesDecorator) openParallel(ctx context.Context, correlationId string,
+	components []interface{}, locators []interface{}) error {
+	done := make([]chan struct{}, len(components))
+	errs := make([]error, len(components))
+	for index := range components {
+		done[index] = make(chan struct{})
+	}
+
+	var failed bool
+	var failedLock sync.Mutex
+	isFailed := func() bool {
+		failedLock.Lock()
+		defer failedLock.Unlock()
+		return failed
+	}
+
+	slots := make(chan struct{}, c.concurrency)
+	for index, component := range components {
+		waitFor := []int{}
+		for _, dependency := range c.dependencies(component) {
+			dependencyIndex := indexOfComponent(components[:index], dependency)
+			if dependencyIndex >= 0 {
+				waitFor = append(waitFor, dependencyIndex)
+			}
+		}
+
+		go func(index int, component interface{}, waitFor []int) {
+			defer close(done[index])
+
+			for _, dependencyIndex := range waitFor {
+				<-done[dependencyIndex]
+			}
+			if isFailed() {
+				return
+			}
+
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			if isFailed() {
+				return
+			}
+			errs[index] = c.openNext(ctx, correlationId, locators[index], component)
+			if errs[index] != nil && c.tolerateOpenError(ctx, locators[index], component, errs[index]) {
+				errs[index] = nil
+			}
+			if errs[index] != nil {
+				failedLock.Lock()
+				failed = true
+				failedLock.Unlock()
+			}
+		}(index, component, waitFor)
+	}
+
+	for index := range components {
+		<-done[index]
+	}
+
+	for index, err := range errs {
+		if err != nil {
+			c.failedLocator = locators[index]
+			return err
+		}
+	}
+	return nil
+}
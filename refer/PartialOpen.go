@@ -0,0 +1,56 @@
+package refer
+
+import (
+	"context"
+)
+
+/*
+Failure of a non-critical component that was skipped when components were opened
+with continue-on-error mode (see RunReferencesDecorator.SetContinueOnError).
+*/
+type OpenFailure struct {
+	Locator interface{}
+	Error   error
+}
+
+// Sets whether opening continues past failing components. When it is enabled,
+// errors of non-critical components are recorded and the rest of components are opened,
+// while a failure of a critical component still stops opening.
+// Parameters:
+//   - continueOnError bool
+//   true to continue opening when a non-critical component fails.
+//   - critical func(component interface{}) bool
+//   a function that checks if the component is critical, or nil if no component is critical.
+func (c *RunReferencesDecorator) SetContinueOnError(continueOnError bool,
+	critical func(component interface{}) bool) {
+	c.continueOnError = continueOnError
+	c.critical = critical
+}
+
+// Gets failures of non-critical components skipped during the last Open call.
+// Returns []*OpenFailure
+// the failures or empty list if all components were opened.
+func (c *RunReferencesDecorator) GetOpenFailures() []*OpenFailure {
+	c.failuresLock.Lock()
+	defer c.failuresLock.Unlock()
+
+	return append([]*OpenFailure{}, c.failures...)
+}
+
+// Records the open error of a non-critical component in continue-on-error mode.
+// Returns true when the error is tolerated and opening shall continue.
+func (c *RunReferencesDecorator) tolerateOpenError(ctx context.Context,
+	locator interface{}, component interface{}, err error) bool {
+	if !c.continueOnError || ctx.Err() != nil {
+		return false
+	}
+	if c.critical != nil && c.critical(component) {
+		return false
+	}
+
+	c.failuresLock.Lock()
+	defer c.failuresLock.Unlock()
+
+	c.failures = append(c.failures, &OpenFailure{Locator: locator, Error: err})
+	return true
+}
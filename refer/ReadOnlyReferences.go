@@ -0,0 +1,139 @@
+package refer
+
+import (
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+)
+
+/*
+Immutable snapshot of component references.
+
+The snapshot is taken once and never changes afterwards, so it can be safely shared
+between goroutines that handle requests without holding the mutable container references.
+Put, Remove and RemoveAll calls are ignored.
+
+see
+ContainerReferences.CloneReadOnly
+*/
+type ReadOnlyReferences struct {
+	references *crefer.References
+}
+
+// Creates a new read-only snapshot of the given references.
+// Parameters:
+//   - references crefer.IReferences
+//   references to copy components from.
+// Returns *ReadOnlyReferences
+func NewReadOnlyReferences(references crefer.IReferences) *ReadOnlyReferences {
+	tuples := []interface{}{}
+
+	if references != nil {
+		locators := references.GetAllLocators()
+		components := references.GetAll()
+		for index, component := range components {
+			var locator interface{}
+			if index < len(locators) {
+				locator = locators[index]
+			}
+			tuples = append(tuples, locator, component)
+		}
+	}
+
+	return &ReadOnlyReferences{
+		references: crefer.NewReferences(tuples),
+	}
+}
+
+// Ignores the call since the references are read-only.
+// Parameters:
+//   - locator interface{}
+//   a locator to find the reference by.
+//   - component interface{}
+//   a component reference to be added.
+func (c *ReadOnlyReferences) Put(locator interface{}, component interface{}) {
+}
+
+// Ignores the call since the references are read-only.
+// Parameters:
+//   - locator interface{}
+//   a locator to remove reference
+// Returns interface{}
+// always nil.
+func (c *ReadOnlyReferences) Remove(locator interface{}) interface{} {
+	return nil
+}
+
+// Ignores the call since the references are read-only.
+// Parameters:
+//   - locator interface{}
+//   the locator to remove references by.
+// Returns []interface{}
+// always an empty list.
+func (c *ReadOnlyReferences) RemoveAll(locator interface{}) []interface{} {
+	return []interface{}{}
+}
+
+// Gets locators for all registered component references in this reference map.
+// Returns []interface{}
+// a list with component locators.
+func (c *ReadOnlyReferences) GetAllLocators() []interface{} {
+	return c.references.GetAllLocators()
+}
+
+// Gets all component references registered in this reference map.
+// Returns []interface{}
+// a list with component references.
+func (c *ReadOnlyReferences) GetAll() []interface{} {
+	return c.references.GetAll()
+}
+
+// Gets an optional component reference that matches specified locator.
+// Parameters:
+//   - locator interface{}
+//   the locator to find references by.
+// Returns interface{}
+// a matching component reference or nil if nothing was found.
+func (c *ReadOnlyReferences) GetOneOptional(locator interface{}) interface{} {
+	return c.references.GetOneOptional(locator)
+}
+
+// Gets a required component reference that matches specified locator.
+// Parameters:
+//   - locator interface{}
+//   the locator to find a reference by.
+// Returns interface{}, error
+// a matching component reference, a ReferenceError when no references found.
+func (c *ReadOnlyReferences) GetOneRequired(locator interface{}) (interface{}, error) {
+	return c.references.GetOneRequired(locator)
+}
+
+// Gets all component references that match specified locator.
+// Parameters:
+//   - locator interface{}
+//   the locator to find references by.
+// Returns []interface{}
+// a list with matching component references or empty list if nothing was found.
+func (c *ReadOnlyReferences) GetOptional(locator interface{}) []interface{} {
+	return c.references.GetOptional(locator)
+}
+
+// Gets all component references that match specified locator. At least one component reference must be present.
+// Parameters:
+//   - locator interface{}
+//   the locator to find references by.
+// Returns []interface{}, error
+// a list with matching component references and a ReferenceError when no references found.
+func (c *ReadOnlyReferences) GetRequired(locator interface{}) ([]interface{}, error) {
+	return c.references.GetRequired(locator)
+}
+
+// Gets all component references that match specified locator.
+// Parameters:
+//   - locator interface{}
+//   the locator to find a reference by.
+//   - required bool
+//   forces to return an error if no reference is found.
+// Returns []interface{}, error
+// a list with matching component references and a ReferenceError when required is set to true but no references found
+func (c *ReadOnlyReferences) Find(locator interface{}, required bool) ([]interface{}, error) {
+	return c.references.Find(locator, required)
+}
@@ -0,0 +1,176 @@
+package refer
+
+import (
+	"context"
+	goerrors "errors"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cconv "github.com/pip-services3-go/pip-services3-commons-go/convert"
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+/*
+Policy to retry opening and closing of a component that fails with a transient error,
+for instance when its database is still booting. Delays between attempts grow exponentially
+from the initial backoff up to the maximum backoff and are randomized by the jitter.
+
+Errors are transient when they are connection errors (NoResponse category), timeouts
+or refused network connections.
+
+Configuration parameters
+  - retry:
+    - attempts: total number of attempts (default: 1, no retries)
+    - backoff: delay in milliseconds before the second attempt (default: 1000)
+    - max_backoff: maximum delay in milliseconds between attempts (default: 30000)
+    - jitter: fraction of the delay randomly added or subtracted, from 0 to 1 (default: 0)
+Example
+  - descriptor: "mygroup:persistence:postgres:default:1.0"
+    retry:
+      attempts: 5
+      backoff: 500
+      jitter: 0.2
+
+see
+RunReferencesDecorator.SetRetryPolicy
+*/
+type RetryPolicy struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	Jitter     float64
+}
+
+// Creates a new retry policy with default backoff.
+// Parameters:
+//   - attempts int
+//   a total number of attempts.
+// Returns *RetryPolicy
+func NewRetryPolicy(attempts int) *RetryPolicy {
+	return &RetryPolicy{
+		Attempts:   attempts,
+		Backoff:    time.Second,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+// Reads the retry policy from "retry" section of component configuration.
+// Parameters:
+//   - config *cconfig.ConfigParams
+//   component configuration parameters.
+// Returns *RetryPolicy
+// the retry policy or nil when the component shall not be retried.
+func ReadRetryPolicyFromConfig(config *cconfig.ConfigParams) *RetryPolicy {
+	if config == nil {
+		return nil
+	}
+
+	retry := config.GetSection("retry")
+	policy := NewRetryPolicy(retry.GetAsIntegerWithDefault("attempts", 1))
+	if policy.Attempts <= 1 {
+		return nil
+	}
+
+	policy.Backoff = time.Duration(retry.GetAsLongWithDefault("backoff", policy.Backoff.Milliseconds())) * time.Millisecond
+	policy.MaxBackoff = time.Duration(retry.GetAsLongWithDefault("max_backoff", policy.MaxBackoff.Milliseconds())) * time.Millisecond
+	policy.Jitter = retry.GetAsDoubleWithDefault("jitter", policy.Jitter)
+	return policy
+}
+
+// Calculates the delay before the next attempt.
+// Parameters:
+//   - attempt int
+//   a number of the failed attempt starting from 1.
+// Returns time.Duration
+func (c *RetryPolicy) GetDelay(attempt int) time.Duration {
+	delay := c.Backoff
+	for index := 1; index < attempt && (c.MaxBackoff <= 0 || delay < c.MaxBackoff); index++ {
+		delay *= 2
+	}
+	if c.MaxBackoff > 0 && delay > c.MaxBackoff {
+		delay = c.MaxBackoff
+	}
+
+	if c.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * c.Jitter * float64(delay))
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay
+}
+
+// Checks if the operation that failed with the error is worth retrying.
+func isTransientError(err error) bool {
+	if appErr, ok := err.(*errors.ApplicationError); ok {
+		return appErr.Category == errors.NoResponse ||
+			strings.Contains(strings.ToUpper(appErr.Code), "TIMEOUT")
+	}
+	var netErr net.Error
+	if goerrors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return goerrors.Is(err, syscall.ECONNREFUSED)
+}
+
+// Runs the operation until it succeeds, fails with a permanent error,
+// runs out of attempts or the context is done.
+func (c *RunReferencesDecorator) retry(ctx context.Context, correlationId string, action string,
+	locator interface{}, policy *RetryPolicy, operation func() error) error {
+	err := operation()
+	if policy == nil {
+		return err
+	}
+
+	for attempt := 1; err != nil && attempt < policy.Attempts && isTransientError(err); attempt++ {
+		delay := policy.GetDelay(attempt)
+		if c.logger != nil {
+			c.logger.Warn(correlationId, "Component %s failed to %s, retrying in %v (attempt %d of %d): %s",
+				cconv.StringConverter.ToString(locator), action, delay, attempt+1, policy.Attempts, err.Error())
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		err = operation()
+	}
+	return err
+}
+
+// Sets the policy to retry opening and closing of the component that fails with transient errors.
+// Parameters:
+//   - component interface{}
+//   a component to set the policy.
+//   - policy *RetryPolicy
+//   a retry policy or nil to disable retries.
+func (c *RunReferencesDecorator) SetRetryPolicy(component interface{}, policy *RetryPolicy) {
+	index := indexOfComponent(c.retried, component)
+	if index >= 0 {
+		c.retryPolicies[index] = policy
+		return
+	}
+	c.retried = append(c.retried, component)
+	c.retryPolicies = append(c.retryPolicies, policy)
+}
+
+// Gets the policy to retry opening and closing of the component.
+// Parameters:
+//   - component interface{}
+//   a component to get the policy.
+// Returns *RetryPolicy
+// the retry policy or nil when the component is not retried.
+func (c *RunReferencesDecorator) GetRetryPolicy(component interface{}) *RetryPolicy {
+	index := indexOfComponent(c.retried, component)
+	if index < 0 {
+		return nil
+	}
+	return c.retryPolicies[index]
+}
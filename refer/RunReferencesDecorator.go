@@ -1,18 +1,58 @@
 package refer
 
 import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
 	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
-	"github.com/pip-services3-go/pip-services3-commons-go/run"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
 )
 
 /*
 
 References decorator that automatically opens to newly added components that implement IOpenable interface and
 closes removed components that implement ICloseable interface.
+
+Components that implement IContextOpenable or IContextClosable interfaces receive a context
+bounded by their open and close timeouts, so slow operations inside them are cancelled.
+Components that fail with transient errors are retried according to their retry policies (see RetryPolicy).
 */
 type RunReferencesDecorator struct {
 	ReferencesDecorator
-	opened bool
+	opened        bool
+	excluded      []interface{}
+	failedLocator interface{}
+	timed         []interface{}
+	timeouts      []componentTimeouts
+	concurrency   int
+	dependencies  func(component interface{}) []interface{}
+	listeners     []ILifecycleListener
+	retried       []interface{}
+	retryPolicies []*RetryPolicy
+	shared        []interface{}
+	sharedKeys    []string
+	environed     []interface{}
+	environments  []map[string]string
+	logger        log.ILogger
+
+	continueOnError bool
+	critical        func(component interface{}) bool
+	failures        []*OpenFailure
+	failuresLock    sync.Mutex
+
+	openBudget      time.Duration
+	batchSize       int
+	progress        OpenProgress
+	progressStarted time.Time
+	opening         bool
+	progressLock    sync.Mutex
+}
+
+type componentTimeouts struct {
+	open  time.Duration
+	close time.Duration
 }
 
 // Creates a new instance of the decorator.
@@ -42,28 +82,170 @@ func (c *RunReferencesDecorator) IsOpen() bool {
 //   transaction id to trace execution through call chain.
 // Returns error
 func (c *RunReferencesDecorator) Open(correlationId string) error {
+	return c.OpenWithContext(context.Background(), correlationId)
+}
+
+// Opens components one by one until the context is cancelled. Contexts of components
+// that implement IContextOpenable interface are derived from the context.
+// When open concurrency is set, independent components are opened in parallel (see SetOpenConcurrency).
+// When open budget is set, components are opened in batches with their own deadlines (see SetOpenBudget).
+// Parameters:
+//   - ctx context.Context
+//   a context to cancel opening.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+func (c *RunReferencesDecorator) OpenWithContext(ctx context.Context, correlationId string) error {
 	if !c.opened {
-		components := c.GetAll()
-		err := run.Opener.Open(correlationId, components)
-		c.opened = err == nil
-		return err
+		c.failedLocator = nil
+		c.failuresLock.Lock()
+		c.failures = nil
+		c.failuresLock.Unlock()
+
+		components := []interface{}{}
+		locators := []interface{}{}
+		allLocators := c.GetAllLocators()
+		for index, component := range c.GetAll() {
+			if c.IsExcluded(component) {
+				continue
+			}
+			var locator interface{}
+			if index < len(allLocators) {
+				locator = allLocators[index]
+			}
+			components = append(components, component)
+			locators = append(locators, locator)
+		}
+
+		var err error
+		if c.openBudget > 0 || c.batchSize > 0 {
+			err = c.openBatches(ctx, correlationId, components, locators)
+		} else {
+			c.startProgress(len(components), 1)
+			err = c.openSequential(ctx, correlationId, components, locators)
+			c.updateProgress(0, 1)
+		}
+		c.finishProgress()
+		if err != nil {
+			return err
+		}
+		c.opened = true
 	}
 	return nil
 }
 
+// Opens components one by one, or in parallel when open concurrency is set.
+func (c *RunReferencesDecorator) openSequential(ctx context.Context, correlationId string,
+	components []interface{}, locators []interface{}) error {
+	if c.concurrency > 1 && c.dependencies != nil {
+		return c.openParallel(ctx, correlationId, components, locators)
+	}
+
+	for index, component := range components {
+		err := c.openNext(ctx, correlationId, locators[index], component)
+		if err != nil && c.tolerateOpenError(ctx, locators[index], component, err) {
+			continue
+		}
+		if err != nil {
+			c.failedLocator = locators[index]
+			return err
+		}
+	}
+	return nil
+}
+
+// Opens the component unless the context is already cancelled.
+func (c *RunReferencesDecorator) openNext(ctx context.Context, correlationId string,
+	locator interface{}, component interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return errors.NewInvalidStateError(
+			correlationId, "OPEN_CANCELLED", "Opening of components was cancelled",
+		).WithDetails("locator", locator).WithCause(err)
+	}
+	return c.openComponent(ctx, correlationId, locator, component)
+}
+
+// Gets locator of the component that failed to open during the last Open call.
+// Returns interface{}
+// the component locator or nil if all components were opened successfully.
+func (c *RunReferencesDecorator) GetFailedLocator() interface{} {
+	return c.failedLocator
+}
+
 // Closes component and frees used resources.
 // Parameters:
 //   - correlationId string
 //   transaction id to trace execution through call chain.
 // Returns error
 func (c *RunReferencesDecorator) Close(correlationId string) error {
-	//if c.opened {
+	return c.CloseWithContext(context.Background(), correlationId)
+}
+
+// Closes components and frees used resources. Contexts of components
+// that implement IContextClosable interface are derived from the context.
+// Parameters:
+//   - ctx context.Context
+//   a context to cancel closing.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns error
+func (c *RunReferencesDecorator) CloseWithContext(ctx context.Context, correlationId string) error {
+	report := c.CloseWithReportContext(ctx, correlationId)
+	return report.FirstError()
+}
+
+// Closes all components in reverse order and reports close duration and outcome of each component.
+// Components are closed even if some of them fail.
+// Parameters:
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns *CloseReport
+func (c *RunReferencesDecorator) CloseWithReport(correlationId string) *CloseReport {
+	return c.CloseWithReportContext(context.Background(), correlationId)
+}
+
+// Closes all components in reverse order and reports close duration and outcome of each component.
+// Contexts of components that implement IContextClosable interface are derived from the context.
+// When the context is cancelled or its deadline is exceeded, the component that is still closing
+// and components that were not closed yet are abandoned and reported as timed out.
+// Parameters:
+//   - ctx context.Context
+//   a context to cancel closing.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+// Returns *CloseReport
+func (c *RunReferencesDecorator) CloseWithReportContext(ctx context.Context, correlationId string) *CloseReport {
+	report := NewCloseReport()
+	start := time.Now()
+
 	components := c.GetAll()
-	err := run.Closer.Close(correlationId, components)
+	locators := c.GetAllLocators()
+
+	// Close components in reverse order so dependencies are closed last
+	for index := len(components) - 1; index >= 0; index-- {
+		component := components[index]
+		componentReport := &ComponentCloseReport{}
+		if index < len(locators) {
+			componentReport.Locator = locators[index]
+		}
+
+		componentStart := time.Now()
+		if ctx.Err() != nil {
+			// Abandoned shared components are still released, so other containers can close them
+			c.releaseShared(component)
+			componentReport.Error = newAbandonedError(correlationId, componentReport.Locator)
+		} else {
+			componentReport.Error = c.closeComponentAsync(ctx, correlationId, componentReport.Locator, component)
+		}
+		componentReport.Duration = time.Since(componentStart)
+		componentReport.TimedOut = isTimeoutError(componentReport.Error)
+
+		report.Components = append(report.Components, componentReport)
+	}
+
 	c.opened = false
-	return err
-	// }
-	// return nil
+	report.Duration = time.Since(start)
+	return report
 }
 
 // Puts a new reference into this reference map.
@@ -75,11 +257,121 @@ func (c *RunReferencesDecorator) Close(correlationId string) error {
 func (c *RunReferencesDecorator) Put(locator interface{}, component interface{}) {
 	c.ReferencesDecorator.Put(locator, component)
 
-	if c.opened {
-		run.Opener.OpenOne("", component)
+	if c.opened && !c.IsExcluded(component) {
+		c.openComponent(context.Background(), "", locator, component)
+	}
+}
+
+// Sets timeouts to open and close the component. They bound contexts passed to components
+// that implement IContextOpenable and IContextClosable interfaces.
+// Parameters:
+//   - component interface{}
+//   a component to set the timeouts.
+//   - openTimeout time.Duration
+//   a timeout to open the component or 0 for no timeout.
+//   - closeTimeout time.Duration
+//   a timeout to close the component or 0 for no timeout.
+func (c *RunReferencesDecorator) SetTimeouts(component interface{}, openTimeout time.Duration, closeTimeout time.Duration) {
+	timeouts := componentTimeouts{open: openTimeout, close: closeTimeout}
+	index := indexOfComponent(c.timed, component)
+	if index >= 0 {
+		c.timeouts[index] = timeouts
+		return
+	}
+	c.timed = append(c.timed, component)
+	c.timeouts = append(c.timeouts, timeouts)
+}
+
+// Gets timeouts to open and close the component.
+// Parameters:
+//   - component interface{}
+//   a component to get the timeouts.
+// Returns time.Duration, time.Duration
+// the open and close timeouts or 0 when they were not set.
+func (c *RunReferencesDecorator) GetTimeouts(component interface{}) (time.Duration, time.Duration) {
+	index := indexOfComponent(c.timed, component)
+	if index < 0 {
+		return 0, 0
+	}
+	return c.timeouts[index].open, c.timeouts[index].close
+}
+
+// Marks the component as shared with other containers (see SharedComponentRegistry).
+// The shared component is opened once and closed only when it is released by all containers.
+// Parameters:
+//   - component interface{}
+//   a component acquired from the registry of shared components.
+//   - key string
+//   a key of the component in the registry.
+func (c *RunReferencesDecorator) SetShared(component interface{}, key string) {
+	index := indexOfComponent(c.shared, component)
+	if index >= 0 {
+		c.sharedKeys[index] = key
+		return
+	}
+	c.shared = append(c.shared, component)
+	c.sharedKeys = append(c.sharedKeys, key)
+}
+
+// Gets the key of the shared component.
+// Parameters:
+//   - component interface{}
+//   a component to get the key.
+// Returns string
+// the key in the registry of shared components or empty string when the component is not shared.
+func (c *RunReferencesDecorator) GetSharedKey(component interface{}) string {
+	index := indexOfComponent(c.shared, component)
+	if index < 0 {
+		return ""
+	}
+	return c.sharedKeys[index]
+}
+
+// Releases the shared component held by these references.
+// Returns true when the component is not shared or this was the last reference to it, so it shall be closed.
+func (c *RunReferencesDecorator) releaseShared(component interface{}) bool {
+	index := indexOfComponent(c.shared, component)
+	if index < 0 {
+		return true
+	}
+	key := c.sharedKeys[index]
+	c.shared = append(c.shared[:index:index], c.shared[index+1:]...)
+	c.sharedKeys = append(c.sharedKeys[:index:index], c.sharedKeys[index+1:]...)
+	return SharedComponents.Release(key) == 0
+}
+
+// Excludes the component from automatic opening. Excluded components are still closed
+// together with the rest of the components. It is used when components are opened on schedule or on demand.
+// Parameters:
+//   - component interface{}
+//   a component to be excluded.
+func (c *RunReferencesDecorator) Exclude(component interface{}) {
+	if !c.IsExcluded(component) {
+		c.excluded = append(c.excluded, component)
 	}
 }
 
+// Returns the previously excluded component back to automatic opening.
+// Parameters:
+//   - component interface{}
+//   a component to be included.
+func (c *RunReferencesDecorator) Include(component interface{}) {
+	index := indexOfComponent(c.excluded, component)
+	if index >= 0 {
+		c.excluded = append(c.excluded[:index], c.excluded[index+1:]...)
+	}
+}
+
+// Checks if the component is excluded from automatic opening.
+// Parameters:
+//   - component interface{}
+//   a component to be checked.
+// Returns bool
+// true if the component is excluded and false otherwise.
+func (c *RunReferencesDecorator) IsExcluded(component interface{}) bool {
+	return indexOfComponent(c.excluded, component) >= 0
+}
+
 // Removes a previously added reference that matches specified locator. If many references match the locator, it removes only the first one. When all references shall be removed, use removeAll method instead.
 // see
 // removeAll
@@ -92,7 +384,7 @@ func (c *RunReferencesDecorator) Remove(locator interface{}) interface{} {
 	component := c.ReferencesDecorator.Remove(locator)
 
 	if c.opened {
-		run.Closer.CloseOne("", component)
+		c.closeComponent(context.Background(), "", locator, component)
 	}
 
 	return component
@@ -108,8 +400,163 @@ func (c *RunReferencesDecorator) RemoveAll(locator interface{}) []interface{} {
 	components := c.NextReferences.RemoveAll(locator)
 
 	if c.opened {
-		run.Closer.Close("", components)
+		for _, component := range components {
+			c.closeComponent(context.Background(), "", locator, component)
+		}
 	}
 
 	return components
 }
+
+// Adds a listener of lifecycle transitions of components.
+// Parameters:
+//   - listener ILifecycleListener
+//   a listener to be added.
+func (c *RunReferencesDecorator) AddListener(listener ILifecycleListener) {
+	c.listeners = append(c.listeners, listener)
+}
+
+func (c *RunReferencesDecorator) openComponent(ctx context.Context, correlationId string,
+	locator interface{}, component interface{}) error {
+	for _, listener := range c.listeners {
+		callListener(func() { listener.OnOpening(correlationId, locator) })
+	}
+
+	openTimeout, _ := c.GetTimeouts(component)
+	env := c.GetEnvironment(component)
+	open := func() error {
+		return c.retry(ctx, correlationId, "open", locator, c.GetRetryPolicy(component), func() error {
+			return withEnvironment(env, func() error {
+				return openWithContext(ctx, correlationId, locator, component, openTimeout)
+			})
+		})
+	}
+
+	var err error
+	if key := c.GetSharedKey(component); key != "" {
+		err = SharedComponents.Open(key, open)
+	} else {
+		err = open()
+	}
+
+	if err == nil {
+		c.updateProgress(1, 0)
+	}
+
+	for _, listener := range c.listeners {
+		if err != nil {
+			callListener(func() { listener.OnFailed(correlationId, locator, err) })
+		} else {
+			callListener(func() { listener.OnOpened(correlationId, locator) })
+		}
+	}
+	return err
+}
+
+// Opens a single component that is already in the references, for instance to restart it
+// or to open it on demand after it was excluded from automatic opening.
+// The component is opened the same way as together with the rest of the components:
+// lifecycle listeners are notified and its timeouts, retry policy, environment and shared state are respected.
+// Parameters:
+//   - ctx context.Context
+//   a context to cancel opening.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - component interface{}
+//   a component to be opened.
+// Returns error
+func (c *RunReferencesDecorator) OpenComponent(ctx context.Context, correlationId string, component interface{}) error {
+	return c.openComponent(ctx, correlationId, c.getLocator(component), component)
+}
+
+// Closes a single component without removing it from the references, so it can be opened again.
+// The component is closed the same way as together with the rest of the components:
+// lifecycle listeners are notified, its timeouts and retry policy are respected and its panic is converted into error.
+// Shared components are not closed while other containers reference them.
+// Parameters:
+//   - ctx context.Context
+//   a context to cancel closing.
+//   - correlationId string
+//   transaction id to trace execution through call chain.
+//   - component interface{}
+//   a component to be closed.
+// Returns error
+func (c *RunReferencesDecorator) CloseComponent(ctx context.Context, correlationId string, component interface{}) error {
+	locator := c.getLocator(component)
+	if key := c.GetSharedKey(component); key != "" {
+		return SharedComponents.Close(key, func() error {
+			return c.stopComponent(ctx, correlationId, locator, component)
+		})
+	}
+	return c.stopComponent(ctx, correlationId, locator, component)
+}
+
+// Finds the locator the component was added with.
+func (c *RunReferencesDecorator) getLocator(component interface{}) interface{} {
+	locators := c.GetAllLocators()
+	for index, item := range c.GetAll() {
+		if sameComponent(item, component) && index < len(locators) {
+			return locators[index]
+		}
+	}
+	return nil
+}
+
+// Closes the component in a separate goroutine when the context can be cancelled,
+// so the component that hangs is abandoned when the context is done.
+func (c *RunReferencesDecorator) closeComponentAsync(ctx context.Context, correlationId string,
+	locator interface{}, component interface{}) error {
+	if ctx.Done() == nil {
+		return c.closeComponent(ctx, correlationId, locator, component)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- c.closeComponent(ctx, correlationId, locator, component)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return newAbandonedError(correlationId, locator)
+	}
+}
+
+// Releases and closes a single component. Shared components are closed by the last container that releases them.
+func (c *RunReferencesDecorator) closeComponent(ctx context.Context, correlationId string,
+	locator interface{}, component interface{}) error {
+	// Shared components stay opened while other containers reference them
+	if !c.releaseShared(component) {
+		return nil
+	}
+	return c.stopComponent(ctx, correlationId, locator, component)
+}
+
+// Closes a single component and converts its panic into error,
+// so one failing component cannot prevent the rest from closing.
+func (c *RunReferencesDecorator) stopComponent(ctx context.Context, correlationId string,
+	locator interface{}, component interface{}) (err error) {
+	for _, listener := range c.listeners {
+		callListener(func() { listener.OnClosing(correlationId, locator) })
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicToError(correlationId, r)
+		}
+
+		for _, listener := range c.listeners {
+			if err != nil {
+				callListener(func() { listener.OnFailed(correlationId, locator, err) })
+			} else {
+				callListener(func() { listener.OnClosed(correlationId, locator) })
+			}
+		}
+	}()
+
+	_, closeTimeout := c.GetTimeouts(component)
+	return c.retry(ctx, correlationId, "close", locator, c.GetRetryPolicy(component), func() error {
+		return closeWithContext(ctx, correlationId, locator, component, closeTimeout)
+	})
+}
@@ -0,0 +1,250 @@
+package refer
+
+import (
+	"sort"
+	"sync"
+
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+)
+
+/*
+Process-wide registry of components shared by multiple containers hosted in the same process.
+A shared component is created and configured by the first container that acquires it,
+opened once and closed only when the last container that references it releases it.
+
+Components are opted in with "shared: true" parameter of their configuration.
+Shared components are linked with references of the first container that opens them and handed over
+to the next container when it closes, other containers only reference them. When containers configure the same component differently,
+the configuration of the first container wins.
+
+Example
+  components:
+  - descriptor: "myservice:rules-engine:default:default:1.0"
+    shared: true
+    rules_path: "./rules"
+
+see
+ContainerReferences.PutFromConfig
+*/
+type SharedComponentRegistry struct {
+	entries map[string]*sharedEntry
+	lock    sync.Mutex
+}
+
+type sharedEntry struct {
+	component interface{}
+	refs      int
+	holders   []crefer.IReferences
+	opened    bool
+	lock      sync.Mutex
+}
+
+// Registry of shared components of the process.
+var SharedComponents = NewSharedComponentRegistry()
+
+// Creates a new registry of shared components.
+// Returns *SharedComponentRegistry
+func NewSharedComponentRegistry() *SharedComponentRegistry {
+	return &SharedComponentRegistry{
+		entries: map[string]*sharedEntry{},
+	}
+}
+
+// Acquires the shared component and increments its reference count.
+// When the component is not registered yet it is created with the function.
+// Parameters:
+//   - key string
+//   a key of the shared component, usually its descriptor.
+//   - create func() (interface{}, error)
+//   a function to create the component.
+// Returns interface{}, bool, error
+// the shared component, true if it was created by this call and the error of creation.
+func (c *SharedComponentRegistry) Acquire(key string, create func() (interface{}, error)) (interface{}, bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if ok {
+		entry.refs++
+		return entry.component, false, nil
+	}
+
+	component, err := create()
+	if err != nil || component == nil {
+		return component, false, err
+	}
+
+	c.entries[key] = &sharedEntry{component: component, refs: 1}
+	return component, true, nil
+}
+
+// Opens the shared component once. Following calls do nothing until the component is released by all containers.
+// Parameters:
+//   - key string
+//   a key of the shared component.
+//   - open func() error
+//   a function to open the component.
+// Returns error
+// the error of the open function.
+func (c *SharedComponentRegistry) Open(key string, open func() error) error {
+	c.lock.Lock()
+	entry, ok := c.entries[key]
+	c.lock.Unlock()
+	if !ok {
+		return open()
+	}
+
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+
+	if entry.opened {
+		return nil
+	}
+	err := open()
+	if err == nil {
+		entry.opened = true
+	}
+	return err
+}
+
+// Closes the shared component so it can be opened again, for instance to restart it.
+// The component is not closed while other containers reference it.
+// Parameters:
+//   - key string
+//   a key of the shared component.
+//   - close func() error
+//   a function to close the component.
+// Returns error
+// the error of the close function.
+func (c *SharedComponentRegistry) Close(key string, close func() error) error {
+	c.lock.Lock()
+	entry, ok := c.entries[key]
+	c.lock.Unlock()
+	if !ok {
+		return close()
+	}
+
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+
+	c.lock.Lock()
+	refs := entry.refs
+	c.lock.Unlock()
+	if refs > 1 {
+		return nil
+	}
+
+	entry.opened = false
+	return close()
+}
+
+// Attaches references of the container that links the shared component.
+// Parameters:
+//   - key string
+//   a key of the shared component.
+//   - references crefer.IReferences
+//   references of the container.
+// Returns bool
+// true when these are the first attached references, so the component shall be linked with them.
+func (c *SharedComponentRegistry) Attach(key string, references crefer.IReferences) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return true
+	}
+	for _, holder := range entry.holders {
+		if holder == references {
+			return false
+		}
+	}
+	entry.holders = append(entry.holders, references)
+	return len(entry.holders) == 1
+}
+
+// Detaches references of the container that stops using the shared component.
+// Parameters:
+//   - key string
+//   a key of the shared component.
+//   - references crefer.IReferences
+//   references of the container.
+// Returns crefer.IReferences, int
+// references to link the component with when the detached references were linked with it and other
+// containers still hold it, otherwise nil, and the number of attached references left.
+// When no references are left the caller shall unset references of the component.
+func (c *SharedComponentRegistry) Detach(key string, references crefer.IReferences) (crefer.IReferences, int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, 0
+	}
+	for index, holder := range entry.holders {
+		if holder != references {
+			continue
+		}
+		entry.holders = append(entry.holders[:index:index], entry.holders[index+1:]...)
+		if index == 0 && len(entry.holders) > 0 {
+			return entry.holders[0], len(entry.holders)
+		}
+		break
+	}
+	return nil, len(entry.holders)
+}
+
+// Releases the shared component and decrements its reference count.
+// The component is removed from the registry when it is released by all containers.
+// Parameters:
+//   - key string
+//   a key of the shared component.
+// Returns int
+// the number of references left. When it is 0 the caller shall close the component.
+func (c *SharedComponentRegistry) Release(key string) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return 0
+	}
+
+	entry.refs--
+	if entry.refs <= 0 {
+		delete(c.entries, key)
+		return 0
+	}
+	return entry.refs
+}
+
+// Gets the number of containers that reference the shared component.
+// Parameters:
+//   - key string
+//   a key of the shared component.
+// Returns int
+// the reference count or 0 when the component is not registered.
+func (c *SharedComponentRegistry) GetReferenceCount(key string) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if entry, ok := c.entries[key]; ok {
+		return entry.refs
+	}
+	return 0
+}
+
+// Gets keys of all registered shared components.
+// Returns []string
+// a sorted list of keys.
+func (c *SharedComponentRegistry) GetKeys() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	keys := []string{}
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package refer
+
+import (
+	"sort"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/convert"
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+)
+
+/*
+Resolution of a single dependency declared in a component configuration.
+*/
+type DependencyWiring struct {
+	Component  string   `json:"component"`
+	Dependency string   `json:"dependency"`
+	Locator    string   `json:"locator"`
+	Resolved   []string `json:"resolved"`
+}
+
+/*
+Machine-readable report that shows which components satisfied dependencies declared
+in "dependencies" sections and "depends_on" lists of component configurations.
+It is used in configuration reviews and to detect drift between environments.
+
+see
+ContainerReferences.GetWiringReport
+*/
+type WiringReport struct {
+	Dependencies []*DependencyWiring `json:"dependencies"`
+	Unresolved   int                 `json:"unresolved"`
+}
+
+// Creates a report of how declared dependencies of components are resolved.
+// Returns *WiringReport
+func (c *ContainerReferences) GetWiringReport() *WiringReport {
+	report := &WiringReport{
+		Dependencies: []*DependencyWiring{},
+	}
+
+	for index, component := range c.components {
+		componentConfig := c.configs[index]
+		name := convert.StringConverter.ToString(c.GetComponentLocator(component))
+
+		for _, dependency := range componentConfig.DependsOn {
+			report.add(c.resolveWiring(name, "depends_on", dependency))
+		}
+
+		if componentConfig.Config == nil {
+			continue
+		}
+		dependencies := componentConfig.Config.GetSection("dependencies")
+		keys := dependencies.Keys()
+		sort.Strings(keys)
+		for _, key := range keys {
+			descriptor, err := refer.ParseDescriptorFromString(dependencies.GetAsString(key))
+			if err != nil || descriptor == nil {
+				continue
+			}
+			report.add(c.resolveWiring(name, key, descriptor))
+		}
+	}
+
+	return report
+}
+
+func (c *ContainerReferences) resolveWiring(component string, dependency string, locator *refer.Descriptor) *DependencyWiring {
+	wiring := &DependencyWiring{
+		Component:  component,
+		Dependency: dependency,
+		Locator:    locator.String(),
+		Resolved:   []string{},
+	}
+
+	for _, resolved := range c.GetOptional(locator) {
+		wiring.Resolved = append(wiring.Resolved, convert.StringConverter.ToString(c.GetComponentLocator(resolved)))
+	}
+	return wiring
+}
+
+func (c *WiringReport) add(wiring *DependencyWiring) {
+	c.Dependencies = append(c.Dependencies, wiring)
+	if len(wiring.Resolved) == 0 {
+		c.Unresolved++
+	}
+}
@@ -0,0 +1,39 @@
+package test_barrier
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-container-go/barrier"
+)
+
+func TestFileCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "barrier")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ready")
+	check := &barrier.BarrierCheck{Type: "file", Path: path}
+
+	assert.NotNil(t, check.Check(time.Second))
+
+	err = ioutil.WriteFile(path, []byte{}, 0644)
+	assert.Nil(t, err)
+	assert.Nil(t, check.Check(time.Second))
+
+	b := barrier.NewBarrier()
+	b.AddCheck(check)
+
+	err = b.Open("123")
+	assert.Nil(t, err)
+	assert.True(t, b.IsOpen())
+
+	err = b.Close("123")
+	assert.Nil(t, err)
+	assert.False(t, b.IsOpen())
+}
@@ -0,0 +1,21 @@
+//go:build !minimal
+// +build !minimal
+
+package test_build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-container-go/build"
+)
+
+func TestDefaultContainerFactory(t *testing.T) {
+	factory := build.NewDefaultContainerFactory()
+
+	assert.NotNil(t, factory.CanCreate(crefer.NewDescriptor("pip-services", "logger", "console", "*", "1.0")))
+	assert.NotNil(t, factory.CanCreate(crefer.NewDescriptor("pip-services", "cache", "memory", "*", "1.0")))
+	assert.NotNil(t, factory.CanCreate(crefer.NewDescriptor("pip-services", "counters", "log", "*", "1.0")))
+}
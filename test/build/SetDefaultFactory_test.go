@@ -0,0 +1,54 @@
+//go:build !minimal
+// +build !minimal
+
+package test_build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	cbuild "github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-components-go/cache"
+	"github.com/pip-services3-go/pip-services3-components-go/count"
+	"github.com/pip-services3-go/pip-services3-container-go/build"
+)
+
+func TestSetDefaultFactory(t *testing.T) {
+	countersDescriptor := crefer.NewDescriptor("mygroup", "counters", "custom", "*", "1.0")
+	counters := cbuild.NewFactory()
+	counters.Register(countersDescriptor, func(locator interface{}) interface{} {
+		return count.NewNullCounters()
+	})
+
+	extraDescriptor := crefer.NewDescriptor("mygroup", "extra", "default", "*", "1.0")
+	extra := cbuild.NewFactory()
+	extra.Register(extraDescriptor, func(locator interface{}) interface{} {
+		return "extra"
+	})
+
+	build.SetDefaultFactory("count", counters)
+	build.SetDefaultFactory("cache", nil)
+	build.SetDefaultFactory("extra", extra)
+	defer func() {
+		build.SetDefaultFactory("count", count.NewDefaultCountersFactory())
+		build.SetDefaultFactory("cache", cache.NewDefaultCacheFactory())
+		build.SetDefaultFactory("extra", nil)
+	}()
+
+	factory := build.NewDefaultContainerFactory()
+
+	// The group factory is replaced
+	assert.NotNil(t, factory.CanCreate(countersDescriptor))
+	assert.Nil(t, factory.CanCreate(crefer.NewDescriptor("pip-services", "counters", "log", "*", "1.0")))
+
+	// The group is excluded
+	assert.Nil(t, factory.CanCreate(crefer.NewDescriptor("pip-services", "cache", "memory", "*", "1.0")))
+
+	// Factories of unknown groups are added
+	assert.NotNil(t, factory.CanCreate(extraDescriptor))
+
+	// Other groups keep their default factories
+	assert.NotNil(t, factory.CanCreate(crefer.NewDescriptor("pip-services", "logger", "console", "*", "1.0")))
+}
@@ -0,0 +1,40 @@
+package test_config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+func TestFilterByConditions(t *testing.T) {
+	containerConfig := cconf.ContainerConfig{
+		cconf.NewComponentConfigFromDescriptor(
+			refer.NewDescriptor("mygroup", "cache", "redis", "default", "1.0"),
+			conf.NewConfigParamsFromTuples("when", "{{FEATURE_CACHE}} == 'redis'"),
+		),
+		cconf.NewComponentConfigFromDescriptor(
+			refer.NewDescriptor("mygroup", "cache", "memory", "default", "1.0"),
+			conf.NewConfigParamsFromTuples("when", "{{FEATURE_CACHE}} != 'redis'"),
+		),
+		cconf.NewComponentConfigFromDescriptor(
+			refer.NewDescriptor("mygroup", "controller", "default", "default", "1.0"), nil,
+		),
+	}
+
+	selected, err := containerConfig.FilterByConditions(conf.NewConfigParamsFromTuples("FEATURE_CACHE", "redis"))
+	assert.Nil(t, err)
+	assert.Len(t, selected, 2)
+	assert.Equal(t, "redis", selected[0].Descriptor.Kind())
+
+	_, err = cconf.ContainerConfig{
+		cconf.NewComponentConfigFromDescriptor(
+			refer.NewDescriptor("mygroup", "cache", "redis", "default", "1.0"),
+			conf.NewConfigParamsFromTuples("when", "(redis == 'redis'"),
+		),
+	}.FilterByConditions(nil)
+	assert.NotNil(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	conf "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/convert"
 	"github.com/pip-services3-go/pip-services3-commons-go/refer"
 	"github.com/pip-services3-go/pip-services3-commons-go/reflect"
 	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
@@ -78,3 +79,28 @@ func TestComponentConfigFromCorrectConfig(t *testing.T) {
 	assert.Equal(t, "name", componentConfig.Descriptor.Name())
 	assert.Equal(t, "version", componentConfig.Descriptor.Version())
 }
+
+func TestValidateConfigSchema(t *testing.T) {
+	schema := cconf.NewConfigSchema().
+		WithRequiredProperty("connection.port", convert.Integer).
+		WithOptionalProperty("level", convert.String, "debug", "info", "error").
+		WithOptionalProperty("options", convert.Map)
+
+	componentConfig, err := cconf.ReadComponentConfigFromConfig(conf.NewConfigParamsFromTuples(
+		"descriptor", "pip-services:logger:console:default:1.0",
+		"connection.port", "abc",
+		"level", "trace",
+		"options.timeout", "1000",
+		"depends_on", "pip-services:context-info:default:default:1.0",
+		"lvl", "debug",
+	))
+	assert.Nil(t, err)
+
+	violations := schema.Validate(componentConfig)
+	assert.Len(t, violations, 3)
+	keys := []string{}
+	for _, violation := range violations {
+		keys = append(keys, violation.Key)
+	}
+	assert.Equal(t, []string{"connection.port", "level", "lvl"}, keys)
+}
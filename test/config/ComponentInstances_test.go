@@ -0,0 +1,29 @@
+package test_config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+func TestComponentInstances(t *testing.T) {
+	containerConfig, err := cconf.ContainerConfigReader.ReadFromBytes("123", []byte(`
+- descriptor: "mygroup:consumer:kafka:orders:1.0"
+  count: 3
+  partition: "${index}"
+- descriptor: "mygroup:controller:default:default:1.0"
+`), ".yml", nil)
+	assert.Nil(t, err)
+	assert.Len(t, containerConfig, 4)
+	assert.Equal(t, "orders-0", containerConfig[0].Descriptor.Name())
+	assert.Equal(t, "orders-2", containerConfig[2].Descriptor.Name())
+	assert.Equal(t, 2, containerConfig[2].Config.GetAsInteger("partition"))
+
+	_, err = cconf.ContainerConfigReader.ReadFromBytes("123", []byte(`
+- descriptor: "mygroup:consumer:kafka:orders:1.0"
+  count: many
+`), ".yml", nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,43 @@
+package test_config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+func TestComponentTemplates(t *testing.T) {
+	containerConfig, err := cconf.ContainerConfigReader.ReadFromBytes("123", []byte(`
+templates:
+  queue_listener:
+    descriptor: "mygroup:listener:rabbitmq:default:1.0"
+    connection:
+      host: localhost
+      port: 5672
+components:
+  - template: queue_listener
+    descriptor: "mygroup:listener:rabbitmq:orders:1.0"
+    queue: orders
+  - template: queue_listener
+    queue: invoices
+    connection:
+      port: 5673
+`), ".yml", nil)
+	assert.Nil(t, err)
+	assert.Len(t, containerConfig, 2)
+	assert.Equal(t, "orders", containerConfig[0].Descriptor.Name())
+	assert.Equal(t, "localhost", containerConfig[0].Config.GetAsString("connection.host"))
+	assert.Equal(t, "default", containerConfig[1].Descriptor.Name())
+	assert.Equal(t, 5673, containerConfig[1].Config.GetAsInteger("connection.port"))
+
+	_, err = cconf.ContainerConfigReader.ReadFromBytes("123", []byte(`
+templates:
+  queue_listener:
+    descriptor: "mygroup:listener:rabbitmq:default:1.0"
+components:
+  - template: missing
+`), ".yml", nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,36 @@
+package test_config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/config"
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+func TestScanConfigParameters(t *testing.T) {
+	template := `
+- descriptor: "pip-services:logger:console:default:1.0"
+  level: {{LOG_LEVEL}}
+
+{{#if MONGO_ENABLED}}
+- descriptor: "mygroup:persistence:mongodb:default:1.0"
+  connection:
+    uri: {{MONGO_URI}}
+{{/if}}
+
+- descriptor: "pip-services:endpoint:http:default:1.0"
+  connection:
+    port: {{HTTP_PORT}}
+`
+	parameters := config.NewConfigParamsFromTuples("LOG_LEVEL", "debug", "MONGO_URI", "mongodb://localhost")
+
+	params := cconf.ScanConfigParameters(template, parameters)
+
+	assert.Len(t, params, 4)
+	assert.Equal(t, &cconf.ConfigParameter{Name: "HTTP_PORT", Required: true, Set: false}, params[0])
+	assert.Equal(t, &cconf.ConfigParameter{Name: "LOG_LEVEL", Required: true, Set: true}, params[1])
+	assert.Equal(t, &cconf.ConfigParameter{Name: "MONGO_ENABLED", Required: false, Set: false}, params[2])
+	assert.Equal(t, &cconf.ConfigParameter{Name: "MONGO_URI", Required: false, Set: true}, params[3])
+}
@@ -0,0 +1,80 @@
+package test_config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	conf "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValueProviders(t *testing.T) {
+	providers := cconf.NewConfigValueProviders()
+	providers.Register("vault:", cconf.ConfigValueProviderFunc(
+		func(correlationId string, value string) (string, error) {
+			return "secret-of-" + value, nil
+		}))
+
+	config := cconf.NewContainerConfig(
+		cconf.NewComponentConfigFromDescriptor(
+			refer.NewDescriptor("pip-services", "connection", "default", "default", "1.0"),
+			conf.NewConfigParamsFromTuples(
+				"descriptor", "vault:pip-services:connection:default:default:1.0",
+				"password", "vault:db#password",
+				"host", "localhost",
+				"url", "http://localhost:8080",
+			),
+		),
+	)
+
+	resolved, err := providers.Resolve("123", config)
+	assert.Nil(t, err)
+	assert.Len(t, resolved, 1)
+
+	params := resolved[0].Config
+	assert.Equal(t, "secret-of-db#password", params.GetAsString("password"))
+	assert.Equal(t, "localhost", params.GetAsString("host"))
+	assert.Equal(t, "http://localhost:8080", params.GetAsString("url"))
+	assert.Equal(t, "vault:pip-services:connection:default:default:1.0", params.GetAsString("descriptor"))
+
+	// The original configuration is not changed
+	assert.Equal(t, "vault:db#password", config[0].Config.GetAsString("password"))
+}
+
+func TestFileConfigValueProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "db_password")
+	err = ioutil.WriteFile(path, []byte("pa$$word\n"), 0600)
+	assert.Nil(t, err)
+
+	provider := cconf.NewFileConfigValueProvider()
+
+	value, err := provider.Resolve("123", path)
+	assert.Nil(t, err)
+	assert.Equal(t, "pa$$word", value)
+
+	_, err = provider.Resolve("123", filepath.Join(dir, "missing"))
+	assert.NotNil(t, err)
+
+	providers := cconf.NewConfigValueProviders()
+	providers.Register("file", provider)
+
+	config := cconf.NewContainerConfig(
+		cconf.NewComponentConfigFromDescriptor(
+			refer.NewDescriptor("pip-services", "connection", "default", "default", "1.0"),
+			conf.NewConfigParamsFromTuples(
+				"password", "file:"+filepath.Join(dir, "missing"),
+			),
+		),
+	)
+	_, err = providers.Resolve("123", config)
+	assert.NotNil(t, err)
+}
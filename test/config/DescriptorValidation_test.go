@@ -0,0 +1,61 @@
+package test_config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+func TestCheckDescriptorString(t *testing.T) {
+	assert.Equal(t, "", cconf.CheckDescriptorString("mygroup:persistence:*:default:1.0"))
+	assert.NotEqual(t, "", cconf.CheckDescriptorString("mygroup:persistence:default:1.0"))
+	assert.NotEqual(t, "", cconf.CheckDescriptorString("mygroup:persist ence:*:default:1.0"))
+	assert.NotEqual(t, "", cconf.CheckDescriptorString(":persistence:*:default:1.0"))
+}
+
+func TestDescriptorViolationString(t *testing.T) {
+	violation := &cconf.DescriptorViolation{
+		Path:    "config.yml",
+		Line:    3,
+		Key:     "descriptor",
+		Value:   "mygroup:controller:default:1.0",
+		Message: "Descriptor must have 5 fields separated by colons, but has 4",
+	}
+	assert.Equal(t, "config.yml:3: descriptor \"mygroup:controller:default:1.0\": Descriptor must have 5 fields separated by colons, but has 4", violation.String())
+}
+
+func TestDescriptorViolationsInFormatFiles(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "config.yml")
+	err := ioutil.WriteFile(yamlPath, []byte(`
+- descriptor: "mygroup:persistence:memory:default:1.0"
+- descriptor: "mygroup:controller:default:1.0"
+`), 0644)
+	assert.Nil(t, err)
+	jsonPath := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(jsonPath, []byte(`[
+  { "descriptor": "mygroup:persistence:memory:default:1.0" },
+  { "descriptor": "mygroup:controller:default:1.0" }
+]`), 0644)
+	assert.Nil(t, err)
+
+	// Format specific readers report malformed descriptors with their lines like ReadFromFile
+	_, err = cconf.ContainerConfigReader.ReadFromYamlFile("123", yamlPath, nil)
+	assert.NotNil(t, err)
+	violations := err.(*errors.ApplicationError).Details["violations"].([]*cconf.DescriptorViolation)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, yamlPath, violations[0].Path)
+	assert.Equal(t, 3, violations[0].Line)
+
+	_, err = cconf.ContainerConfigReader.ReadFromJsonFile("123", jsonPath, nil)
+	assert.NotNil(t, err)
+	violations = err.(*errors.ApplicationError).Details["violations"].([]*cconf.DescriptorViolation)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, jsonPath, violations[0].Path)
+	assert.Equal(t, 3, violations[0].Line)
+}
@@ -0,0 +1,38 @@
+package test_config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+func TestReadDotenvFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dotenv")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, ".env")
+	err = ioutil.WriteFile(path, []byte(`
+# Local development settings
+MONGO_URI=mongodb://localhost:27017/test # local database
+export HTTP_PORT=8080
+GREETING="Hello,\nWorld"
+`), 0644)
+	assert.Nil(t, err)
+
+	parameters, err := cconf.ContainerConfigReader.ReadDotenvFromFile("123", path)
+	assert.Nil(t, err)
+	assert.Equal(t, "mongodb://localhost:27017/test", parameters.GetAsString("MONGO_URI"))
+	assert.Equal(t, "8080", parameters.GetAsString("HTTP_PORT"))
+	assert.Equal(t, "Hello,\nWorld", parameters.GetAsString("GREETING"))
+
+	err = ioutil.WriteFile(path, []byte("GREETING=\"Hello\n"), 0644)
+	assert.Nil(t, err)
+	_, err = cconf.ContainerConfigReader.ReadDotenvFromFile("123", path)
+	assert.NotNil(t, err)
+}
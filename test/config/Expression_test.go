@@ -0,0 +1,40 @@
+package test_config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	result, err := cconf.EvaluateExpression("prod == 'prod' && eu != 'cn'", nil)
+	assert.Nil(t, err)
+	assert.True(t, result)
+
+	result, err = cconf.EvaluateExpression("prod == 'prod' && cn != 'cn'", nil)
+	assert.Nil(t, err)
+	assert.False(t, result)
+
+	// Missing parameter substituted by empty value
+	result, err = cconf.EvaluateExpression(" == 'prod' || !(false)", nil)
+	assert.Nil(t, err)
+	assert.True(t, result)
+
+	result, err = cconf.EvaluateExpression("{{ENV}} == ''", nil)
+	assert.Nil(t, err)
+	assert.True(t, result)
+
+	result, err = cconf.EvaluateExpression("\"a b\" == 'a b' && (x != y || 0)", nil)
+	assert.Nil(t, err)
+	assert.True(t, result)
+}
+
+func TestEvaluateBadExpression(t *testing.T) {
+	_, err := cconf.EvaluateExpression("(a == b", nil)
+	assert.NotNil(t, err)
+
+	_, err = cconf.EvaluateExpression("a == b)", nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,28 @@
+package test_config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+func TestParameterDefaults(t *testing.T) {
+	containerConfig, err := cconf.ContainerConfigReader.ReadFromBytes("123", []byte(`
+- descriptor: "pip-services:logger:console:default:1.0"
+  level: "{{LOG_LEVEL|info}}"
+  source: "${LOG_SOURCE:-console}"
+`), ".yml", conf.NewConfigParamsFromTuples("LOG_SOURCE", "stdout"))
+	assert.Nil(t, err)
+	assert.Len(t, containerConfig, 1)
+	assert.Equal(t, "info", containerConfig[0].Config.GetAsString("level"))
+	assert.Equal(t, "stdout", containerConfig[0].Config.GetAsString("source"))
+
+	parameters := cconf.ScanConfigParameters(`level: "{{LOG_LEVEL|info}}"
+source: "${LOG_SOURCE:-console}"`, nil)
+	assert.Len(t, parameters, 2)
+	assert.False(t, parameters[0].Required)
+	assert.False(t, parameters[1].Required)
+}
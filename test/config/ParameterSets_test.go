@@ -0,0 +1,62 @@
+package test_config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadParameterSetFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parameters.yml")
+	err := ioutil.WriteFile(path, []byte(`
+default:
+  HTTP_PORT: 8080
+  LOG_LEVEL: info
+eu-west:
+  REGION: eu-west-1
+us-east:
+  REGION: us-east-1
+  HTTP_PORT: 8081
+`), 0644)
+	assert.Nil(t, err)
+
+	// Values of the selected set override default values
+	params, err := cconf.ContainerConfigReader.ReadParameterSetFromFile("123", path, "us-east")
+	assert.Nil(t, err)
+	assert.Equal(t, "us-east-1", params.GetAsString("REGION"))
+	assert.Equal(t, "8081", params.GetAsString("HTTP_PORT"))
+	assert.Equal(t, "info", params.GetAsString("LOG_LEVEL"))
+
+	params, err = cconf.ContainerConfigReader.ReadParameterSetFromFile("123", path, "eu-west")
+	assert.Nil(t, err)
+	assert.Equal(t, "eu-west-1", params.GetAsString("REGION"))
+	assert.Equal(t, "8080", params.GetAsString("HTTP_PORT"))
+
+	params, err = cconf.ContainerConfigReader.ReadParameterSetFromFile("123", path, "default")
+	assert.Nil(t, err)
+	assert.False(t, params.Contains("REGION"))
+	assert.Equal(t, "8080", params.GetAsString("HTTP_PORT"))
+}
+
+func TestReadMissingParameterSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parameters.json")
+	err := ioutil.WriteFile(path, []byte(`{"default": {"HTTP_PORT": 8080}, "eu-west": {"REGION": "eu-west-1"}}`), 0644)
+	assert.Nil(t, err)
+
+	params, err := cconf.ContainerConfigReader.ReadParameterSetFromFile("123", path, "eu-west")
+	assert.Nil(t, err)
+	assert.Equal(t, "eu-west-1", params.GetAsString("REGION"))
+
+	_, err = cconf.ContainerConfigReader.ReadParameterSetFromFile("123", path, "ap-south")
+	assert.NotNil(t, err)
+	assert.Equal(t, "PARAM_SET_NOT_FOUND", err.(*errors.ApplicationError).Code)
+
+	_, err = cconf.ContainerConfigReader.ReadParameterSetFromFile("123", "", "eu-west")
+	assert.NotNil(t, err)
+	assert.Equal(t, "NO_PATH", err.(*errors.ApplicationError).Code)
+}
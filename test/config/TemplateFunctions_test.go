@@ -0,0 +1,73 @@
+package test_config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	conf "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+func TestTemplateFunctions(t *testing.T) {
+	os.Setenv("TEMPLATE_FUNCTION_TEST", "value1")
+	defer os.Unsetenv("TEMPLATE_FUNCTION_TEST")
+	hostname, _ := os.Hostname()
+
+	config, err := cconf.ContainerConfigReader.ReadConfigParams("123", []byte(`
+- descriptor: "mygroup:controller:default:default:1.0"
+  host: "{{hostname()}}"
+  param: "{{env('TEMPLATE_FUNCTION_TEST')}}"
+  id: "{{uuid()}}"
+`), ".yml", conf.NewEmptyConfigParams())
+	assert.Nil(t, err)
+	assert.Equal(t, hostname, config.GetAsString("0.host"))
+	assert.Equal(t, "value1", config.GetAsString("0.param"))
+	assert.Len(t, config.GetAsString("0.id"), 36)
+
+	_, err = cconf.ContainerConfigReader.ReadConfigParams("123", []byte(`
+- descriptor: "mygroup:controller:default:default:1.0"
+  password: "{{file('./missing_secret')}}"
+`), ".yml", conf.NewEmptyConfigParams())
+	assert.NotNil(t, err)
+
+	parameters := cconf.ScanConfigParameters(`host: "{{hostname()}}"`, nil)
+	assert.Empty(t, parameters)
+}
+
+func TestTemplateFunctionValuesAreNotEvaluated(t *testing.T) {
+	secret := "-----BEGIN KEY-----\n{{PASSWORD}} \"quoted\" \\path\n-----END KEY-----"
+	path := filepath.ToSlash(filepath.Join(t.TempDir(), "secret"))
+	err := ioutil.WriteFile(path, []byte(secret+"\n"), 0644)
+	assert.Nil(t, err)
+	parameters := conf.NewConfigParamsFromTuples("PASSWORD", "pass123")
+
+	// Results are escaped inside quotes and quoted when written as plain values
+	config, err := cconf.ContainerConfigReader.ReadConfigParams("123", []byte(`
+- descriptor: "mygroup:controller:default:default:1.0"
+  quoted: "{{file('`+path+`')}}"
+  plain: {{file('`+path+`')}}
+  password: "{{PASSWORD}}"
+`), ".yml", parameters)
+	assert.Nil(t, err)
+	assert.Equal(t, secret, config.GetAsString("0.quoted"))
+	assert.Equal(t, secret, config.GetAsString("0.plain"))
+	assert.Equal(t, "pass123", config.GetAsString("0.password"))
+
+	config, err = cconf.ContainerConfigReader.ReadConfigParams("123", []byte(`[
+  { "descriptor": "mygroup:controller:default:default:1.0", "key": "{{file('`+path+`')}}" }
+]`), ".json", parameters)
+	assert.Nil(t, err)
+	assert.Equal(t, secret, config.GetAsString("0.key"))
+
+	config, err = cconf.ContainerConfigReader.ReadConfigParams("123", []byte(`
+[[components]]
+descriptor = "mygroup:controller:default:default:1.0"
+key = "{{file('`+path+`')}}"
+`), ".toml", parameters)
+	assert.Nil(t, err)
+	assert.Equal(t, secret, config.GetAsString("0.key"))
+}
@@ -0,0 +1,112 @@
+package test_config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	cconf "github.com/pip-services3-go/pip-services3-container-go/config"
+)
+
+func readTomlComponent(t *testing.T, content string) map[string]string {
+	config, err := cconf.ReadContainerConfigFromToml(`
+[[components]]
+descriptor = "mygroup:component:default:default:1.0"
+` + content)
+	if !assert.Nil(t, err) || !assert.Len(t, config, 1) {
+		return nil
+	}
+	return config[0].Config.Value()
+}
+
+func TestTomlValues(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  string
+		key      string
+		expected string
+	}{
+		{"basic string", `s = "plain"`, "s", "plain"},
+		{"escapes", `s = "tab\there \"quoted\" back\\slash \u00e9"`, "s", "tab\there \"quoted\" back\\slash é"},
+		{"literal string", `s = 'C:\path\n'`, "s", `C:\path\n`},
+		{"multi-line string", "s = \"\"\"\none \\\n    two\"\"\"", "s", "one two"},
+		{"multi-line literal", "s = '''\nraw\\n\nline'''", "s", "raw\\n\nline"},
+		{"quotes before delimiter", `s = """say ""hi"""""`, "s", `say ""hi""`},
+		{"dotted key", `server.host = "localhost"`, "server.host", "localhost"},
+		{"quoted key", `"my key" = 1`, "my key", "1"},
+		{"decimal", `n = 1_000`, "n", "1000"},
+		{"signed", `n = -17`, "n", "-17"},
+		{"signed zero", `n = +0`, "n", "0"},
+		{"hex", `n = 0xDEAD_beef`, "n", "3735928559"},
+		{"octal", `n = 0o755`, "n", "493"},
+		{"binary", `n = 0b1010`, "n", "10"},
+		{"float", `f = 1_000.5`, "f", "1000.5"},
+		{"exponent", `f = 5e+2`, "f", "500"},
+		{"boolean", `b = true`, "b", "true"},
+		{"date-time", `d = 1979-05-27T07:32:00Z`, "d", "1979-05-27T07:32:00Z"},
+		{"offset date-time", `d = 1979-05-27 00:32:00.999-07:00`, "d", "1979-05-27 00:32:00.999-07:00"},
+		{"local date", `d = 1979-05-27`, "d", "1979-05-27"},
+		{"local time", `d = 07:32:00`, "d", "07:32:00"},
+		{"array", "a = [\n  1,\n  2, # comment\n]", "a.1", "2"},
+		{"inline table", `t = { x = 1, y.z = "a" }`, "t.y.z", "a"},
+		{"table", "[components.options]\ntimeout = 100", "options.timeout", "100"},
+		{"array of tables", "[[components.items]]\nname = \"a\"\n[[components.items]]\nname = \"b\"", "items.1.name", "b"},
+		{"table of array element", "[[components.items]]\n[components.items.meta]\nid = 1", "items.0.meta.id", "1"},
+		{"implicit table defined later", "[components.x.y]\nz = 1\n[components.x]\nw = 2", "x.w", "2"},
+		{"sub-table of dotted table", "[components.fruit]\napple.color = \"red\"\n[components.fruit.apple.texture]\nsmooth = true",
+			"fruit.apple.texture.smooth", "true"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			values := readTomlComponent(t, c.content)
+			assert.Equal(t, c.expected, values[c.key])
+		})
+	}
+}
+
+func TestInvalidToml(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+	}{
+		{"leading zero", `n = 0755`},
+		{"trailing garbage", `n = 1abc`},
+		{"bare word", `n = abc`},
+		{"empty hex", `n = 0x`},
+		{"signed hex", `n = -0x1F`},
+		{"uppercase prefix", `n = 0X1F`},
+		{"double underscore", `n = 1__000`},
+		{"leading underscore", `n = _1`},
+		{"trailing underscore", `n = 1_`},
+		{"integer overflow", `n = 9223372036854775808`},
+		{"trailing dot", `f = 1.`},
+		{"leading dot", `f = .5`},
+		{"bad date", `d = 1979-05-27X`},
+		{"missing value", `n = `},
+		{"bad escape", `s = "bad \q"`},
+		{"unterminated string", `s = "open`},
+		{"duplicate key", "a = 1\na = 2"},
+		{"duplicate table", "[a]\nx = 1\n[a]\ny = 2"},
+		{"duplicate nested table", "[a.b]\n[a.b]"},
+		{"table over value", "[a]\nb = 1\n[a.b]"},
+		{"table over dotted keys", "a.b = 1\n[a]"},
+		{"dotted keys over table", "[a.b]\nc = 1\n[a]\nb.d = 2"},
+		{"table over inline table", "a = { x = 1 }\n[a]"},
+		{"extended inline table", "a = { x = 1 }\na.y = 2"},
+		{"array of tables over array", "a = [1]\n[[a]]"},
+		{"table over array of tables", "[[a]]\n[a]"},
+		{"array of tables over table", "[a]\n[[a]]"},
+		{"text after value", `a = 1 2`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := cconf.ReadContainerConfigFromToml(c.content)
+			if assert.NotNil(t, err) {
+				assert.Equal(t, "BAD_TOML", err.(*cerr.ApplicationError).Code)
+			}
+		})
+	}
+}
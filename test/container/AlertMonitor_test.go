@@ -0,0 +1,50 @@
+package test_container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestAlertMonitor(t *testing.T) {
+	monitor := container.NewAlertMonitor(log.NewNullLogger())
+	monitor.AddRule(&container.AlertRule{
+		Name:      "restart_storm",
+		Metric:    container.AlertMetricRestarts,
+		Operator:  ">",
+		Threshold: 3,
+		Window:    10 * time.Minute,
+	})
+
+	restarts := map[string]float64{
+		"mygroup:persistence:memory:default:1.0": 4,
+		"mygroup:controller:default:default:1.0": 1,
+	}
+	metric := func(metric string, window time.Duration) map[string]float64 {
+		assert.Equal(t, container.AlertMetricRestarts, metric)
+		assert.Equal(t, 10*time.Minute, window)
+		return restarts
+	}
+
+	raised, resolved := monitor.Check(metric)
+	assert.Len(t, raised, 1)
+	assert.Len(t, resolved, 0)
+	assert.Equal(t, "mygroup:persistence:memory:default:1.0", raised[0].Component)
+	assert.Equal(t, 4.0, raised[0].Value)
+
+	// Alerts are raised once while the rule is violated
+	raised, resolved = monitor.Check(metric)
+	assert.Len(t, raised, 0)
+	assert.Len(t, resolved, 0)
+	assert.Len(t, monitor.GetActive(), 1)
+
+	restarts["mygroup:persistence:memory:default:1.0"] = 2
+	raised, resolved = monitor.Check(metric)
+	assert.Len(t, raised, 0)
+	assert.Len(t, resolved, 1)
+	assert.Len(t, monitor.GetActive(), 0)
+}
@@ -0,0 +1,29 @@
+package test_container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestLastCloseReport(t *testing.T) {
+	c := container.NewContainer("test", "Test container")
+	c.Configure(cconfig.NewConfigParamsFromTuples(
+		"logger.descriptor", "pip-services:logger:null:default:1.0",
+	))
+	assert.Nil(t, c.LastCloseReport())
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+
+	err = c.Close("123")
+	assert.Nil(t, err)
+
+	report := c.LastCloseReport()
+	assert.NotNil(t, report)
+	assert.Nil(t, report.FirstError())
+	assert.NotEmpty(t, report.Components)
+}
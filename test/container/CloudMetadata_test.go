@@ -0,0 +1,140 @@
+package test_container
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func newMetadataServer(header string, value string, responses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok || (r.Method == http.MethodGet && r.Header.Get(header) != value) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(response))
+	}))
+}
+
+func newCloudMetadata(aws string, gcp string, azure string) *container.CloudMetadata {
+	metadata := container.NewCloudMetadata()
+	metadata.Configure(cconfig.NewConfigParamsFromTuples(
+		"cloud_metadata.enabled", true,
+		"cloud_metadata.timeout", 200,
+		"cloud_metadata.endpoints.aws", aws,
+		"cloud_metadata.endpoints.gcp", gcp,
+		"cloud_metadata.endpoints.azure", azure,
+	))
+	return metadata
+}
+
+func TestCloudMetadataProviders(t *testing.T) {
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+
+	aws := newMetadataServer("X-aws-ec2-metadata-token", "token1", map[string]string{
+		"PUT /api/token":                             "token1",
+		"GET /meta-data/instance-id":                 "i-1234567890",
+		"GET /meta-data/placement/availability-zone": "us-east-1a",
+		"GET /meta-data/instance-type":               "t3.micro",
+	})
+	defer aws.Close()
+
+	gcp := newMetadataServer("Metadata-Flavor", "Google", map[string]string{
+		"GET /id":           "4520031799277581759",
+		"GET /zone":         "projects/123/zones/us-central1-a",
+		"GET /machine-type": "projects/123/machineTypes/e2-medium",
+	})
+	defer gcp.Close()
+
+	azure := newMetadataServer("Metadata", "true", map[string]string{
+		"GET /metadata/instance": `{"compute":{"vmId":"02aab8a4","location":"westus","zone":"2","vmSize":"Standard_D2s_v3"}}`,
+	})
+	defer azure.Close()
+
+	metadata := newCloudMetadata(aws.URL, missing.URL, missing.URL)
+	assert.True(t, metadata.IsEnabled())
+	assert.Equal(t, map[string]string{
+		"cloud.provider":      "aws",
+		"cloud.instance_id":   "i-1234567890",
+		"cloud.zone":          "us-east-1a",
+		"cloud.instance_type": "t3.micro",
+	}, metadata.Read())
+
+	// Zone and machine type are trimmed from resource paths
+	metadata = newCloudMetadata(missing.URL, gcp.URL, missing.URL)
+	assert.Equal(t, map[string]string{
+		"cloud.provider":      "gcp",
+		"cloud.instance_id":   "4520031799277581759",
+		"cloud.zone":          "us-central1-a",
+		"cloud.instance_type": "e2-medium",
+	}, metadata.Read())
+
+	metadata = newCloudMetadata(missing.URL, missing.URL, azure.URL+"/metadata/instance?api-version=2021-02-01")
+	assert.Equal(t, map[string]string{
+		"cloud.provider":      "azure",
+		"cloud.instance_id":   "02aab8a4",
+		"cloud.zone":          "westus-2",
+		"cloud.instance_type": "Standard_D2s_v3",
+	}, metadata.Read())
+}
+
+func TestCloudMetadataNotAvailable(t *testing.T) {
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+	}))
+	defer hung.Close()
+
+	// Services that don't respond in time are treated as missing
+	metadata := newCloudMetadata(hung.URL, missing.URL, missing.URL)
+	start := time.Now()
+	assert.Equal(t, map[string]string{}, metadata.Read())
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestContainerCloudMetadata(t *testing.T) {
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	gcp := newMetadataServer("Metadata-Flavor", "Google", map[string]string{
+		"GET /id":           "4520031799277581759",
+		"GET /zone":         "projects/123/zones/us-central1-a",
+		"GET /machine-type": "projects/123/machineTypes/e2-medium",
+	})
+	defer gcp.Close()
+
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	err := ioutil.WriteFile(configPath, []byte(`
+- descriptor: "pip-services:container:default:default:1.0"
+  cloud_metadata:
+    enabled: true
+    timeout: 200
+    endpoints:
+      aws: "`+missing.URL+`"
+      gcp: "`+gcp.URL+`"
+      azure: "`+missing.URL+`"
+`), 0644)
+	assert.Nil(t, err)
+
+	c := container.NewContainer("test", "Test container")
+	err = c.ReadConfigFromFile("123", configPath, nil)
+	assert.Nil(t, err)
+
+	err = c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	properties := c.Info().Properties
+	assert.Equal(t, "gcp", properties["cloud.provider"])
+	assert.Equal(t, "us-central1-a", properties["cloud.zone"])
+}
@@ -0,0 +1,91 @@
+package test_container
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-commons-go/run"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+type hungExecutable struct{}
+
+func (c *hungExecutable) ExecuteWithContext(ctx context.Context, correlationId string,
+	args *run.Parameters) (interface{}, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestExecutionTimeout(t *testing.T) {
+	descriptor := crefer.NewDescriptor("test", "job", "default", "default", "1.0")
+	references := crefer.NewReferencesFromTuples(descriptor, &hungExecutable{})
+
+	dispatcher := container.NewCommandDispatcher()
+	dispatcher.Register("job", descriptor)
+	dispatcher.SetLimits(10*time.Millisecond, 1, 0)
+
+	_, err := dispatcher.Execute("123", references, "job", nil)
+	assert.NotNil(t, err)
+	appErr, ok := err.(*cerr.ApplicationError)
+	assert.True(t, ok)
+	assert.Equal(t, "EXECUTION_TIMEOUT", appErr.Code)
+
+	// The slot is released after timeout
+	_, err = dispatcher.Execute("123", references, "job", nil)
+	assert.NotNil(t, err)
+}
+
+type blockedExecutable struct {
+	calls   int
+	unblock chan struct{}
+	lock    sync.Mutex
+}
+
+func (c *blockedExecutable) Execute(correlationId string, args *run.Parameters) (interface{}, error) {
+	c.lock.Lock()
+	c.calls++
+	c.lock.Unlock()
+	<-c.unblock
+	return "done", nil
+}
+
+func (c *blockedExecutable) Calls() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.calls
+}
+
+func TestAbandonedExecutionKeepsSlot(t *testing.T) {
+	descriptor := crefer.NewDescriptor("test", "job", "default", "default", "1.0")
+	executable := &blockedExecutable{unblock: make(chan struct{})}
+	references := crefer.NewReferencesFromTuples(descriptor, executable)
+
+	dispatcher := container.NewCommandDispatcher()
+	dispatcher.Register("job", descriptor)
+	dispatcher.SetLimits(10*time.Millisecond, 1, 0)
+
+	_, err := dispatcher.Execute("123", references, "job", nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, "EXECUTION_TIMEOUT", err.(*cerr.ApplicationError).Code)
+
+	// The abandoned execution still runs, so the next one waits for the slot
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = dispatcher.ExecuteWithContext(ctx, "123", references, "job", nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, "EXECUTION_TIMEOUT", err.(*cerr.ApplicationError).Code)
+	assert.Equal(t, 1, executable.Calls())
+
+	// The slot is released when the abandoned execution returns
+	close(executable.unblock)
+	result, err := dispatcher.ExecuteWithContext(context.Background(), "123", references, "job", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "done", result)
+	assert.Equal(t, 2, executable.Calls())
+}
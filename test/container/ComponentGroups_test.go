@@ -0,0 +1,77 @@
+package test_container
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+const groupedConfig = `
+- descriptor: "pip-services:container:default:default:1.0"
+  groups:
+    reporting:
+      idle_timeout: %d
+- descriptor: "mygroup:report-builder:default:default:1.0"
+  group: reporting
+`
+
+func TestEnsureGroupOpen(t *testing.T) {
+	builder := &restartableComponent{}
+	c := newFixtureContainer(t, fmt.Sprintf(groupedConfig, 0), map[string]interface{}{
+		"mygroup:report-builder:default:default:1.0": builder,
+	})
+	listener := &recordingListener{}
+	c.AddLifecycleListener(listener)
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+	assert.False(t, builder.IsOpen())
+
+	err = c.EnsureGroupOpen("123", "reporting")
+	assert.Nil(t, err)
+	err = c.EnsureGroupOpen("123", "reporting")
+	assert.Nil(t, err)
+	opens, _ := builder.Counts()
+	assert.Equal(t, 1, opens)
+	assert.Contains(t, listener.Events(), "opened mygroup:report-builder:default:default:1.0")
+
+	err = c.EnsureGroupOpen("123", "billing")
+	assert.NotNil(t, err)
+	assert.Equal(t, "GROUP_NOT_FOUND", err.(*cerr.ApplicationError).Code)
+}
+
+func TestGroupIdleTimeout(t *testing.T) {
+	builder := &restartableComponent{}
+	c := newFixtureContainer(t, fmt.Sprintf(groupedConfig, 100), map[string]interface{}{
+		"mygroup:report-builder:default:default:1.0": builder,
+	})
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	// Every call restarts the idle timeout
+	for i := 0; i < 3; i++ {
+		err = c.EnsureGroupOpen("123", "reporting")
+		assert.Nil(t, err)
+		time.Sleep(50 * time.Millisecond)
+	}
+	assert.True(t, builder.IsOpen())
+
+	time.Sleep(200 * time.Millisecond)
+	assert.False(t, builder.IsOpen())
+	_, closes := builder.Counts()
+	assert.Equal(t, 1, closes)
+
+	// The idle group is opened again on demand
+	err = c.EnsureGroupOpen("123", "reporting")
+	assert.Nil(t, err)
+	assert.True(t, builder.IsOpen())
+	opens, _ := builder.Counts()
+	assert.Equal(t, 2, opens)
+}
@@ -0,0 +1,214 @@
+package test_container
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+type restartableComponent struct {
+	opens  int
+	closes int
+	isOpen bool
+	lock   sync.Mutex
+}
+
+func (c *restartableComponent) IsOpen() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.isOpen
+}
+
+func (c *restartableComponent) Open(correlationId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.opens++
+	c.isOpen = true
+	return nil
+}
+
+func (c *restartableComponent) Close(correlationId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.closes++
+	c.isOpen = false
+	return nil
+}
+
+func (c *restartableComponent) Counts() (int, int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.opens, c.closes
+}
+
+func TestRestartThroughRunner(t *testing.T) {
+	runner := refer.NewRunReferencesDecorator(crefer.NewEmptyReferences(), nil)
+	listener := &recordingListener{}
+	runner.AddListener(listener)
+	component := &restartableComponent{}
+	runner.Put("component", component)
+
+	supervisor := container.NewComponentSupervisor(log.NewNullLogger())
+	supervisor.SetRunner(runner)
+
+	err := supervisor.Restart("123", "component", component)
+	assert.Nil(t, err)
+
+	opens, closes := component.Counts()
+	assert.Equal(t, 1, opens)
+	assert.Equal(t, 1, closes)
+	assert.Equal(t, []string{"closing component", "closed component", "opening component", "opened component"},
+		listener.Events())
+}
+
+func TestRestartSharedComponent(t *testing.T) {
+	key := "test:restart:shared:default:1.0"
+	component := &restartableComponent{}
+	create := func() (interface{}, error) { return component, nil }
+	refer.SharedComponents.Acquire(key, create)
+	refer.SharedComponents.Acquire(key, create)
+	defer refer.SharedComponents.Release(key)
+
+	runner := refer.NewRunReferencesDecorator(crefer.NewEmptyReferences(), nil)
+	runner.Put("component", component)
+	runner.SetShared(component, key)
+
+	supervisor := container.NewComponentSupervisor(log.NewNullLogger())
+	supervisor.SetRunner(runner)
+
+	// The other container still uses the component, so it is not closed
+	err := supervisor.Restart("123", "component", component)
+	assert.Nil(t, err)
+	opens, closes := component.Counts()
+	assert.Equal(t, 0, closes)
+
+	// After the other container releases it the restart closes and opens it again
+	refer.SharedComponents.Release(key)
+	err = supervisor.Restart("123", "component", component)
+	assert.Nil(t, err)
+	reopens, closes := component.Counts()
+	assert.Equal(t, 1, closes)
+	assert.Equal(t, opens+1, reopens)
+}
+
+func TestQuarantine(t *testing.T) {
+	runner := refer.NewRunReferencesDecorator(crefer.NewEmptyReferences(), nil)
+	component := &restartableComponent{}
+	runner.Put("component", component)
+
+	supervisor := container.NewComponentSupervisor(log.NewNullLogger())
+	supervisor.SetRunner(runner)
+	supervisor.SetRestartLimits(2, time.Minute)
+	supervisor.SetRestartBudget(0, 0)
+
+	assert.Nil(t, supervisor.Restart("123", "component", component))
+	assert.Nil(t, supervisor.Restart("123", "component", component))
+	assert.False(t, supervisor.IsDegraded())
+
+	err := supervisor.Restart("123", "component", component)
+	assert.NotNil(t, err)
+	assert.Equal(t, "COMPONENT_QUARANTINED", err.(*cerr.ApplicationError).Code)
+	assert.True(t, supervisor.IsQuarantined("component"))
+	assert.True(t, supervisor.IsDegraded())
+	assert.Equal(t, []string{"component"}, supervisor.GetQuarantined())
+
+	// Quarantined components are not restarted
+	err = supervisor.Restart("123", "component", component)
+	assert.NotNil(t, err)
+	opens, _ := component.Counts()
+	assert.Equal(t, 2, opens)
+
+	assert.True(t, supervisor.Unquarantine("123", "component"))
+	assert.False(t, supervisor.Unquarantine("123", "component"))
+	assert.False(t, supervisor.IsQuarantined("component"))
+	assert.False(t, supervisor.IsDegraded())
+
+	// Restart counter starts over after the component is released
+	assert.Nil(t, supervisor.Restart("123", "component", component))
+	opens, _ = component.Counts()
+	assert.Equal(t, 3, opens)
+}
+
+type slowComponent struct {
+	restartableComponent
+	delay time.Duration
+}
+
+func (c *slowComponent) Open(correlationId string) error {
+	time.Sleep(c.delay)
+	return c.restartableComponent.Open(correlationId)
+}
+
+func TestCoalescedRestarts(t *testing.T) {
+	runner := refer.NewRunReferencesDecorator(crefer.NewEmptyReferences(), nil)
+	component := &slowComponent{delay: 100 * time.Millisecond}
+	runner.Put("component", component)
+
+	supervisor := container.NewComponentSupervisor(log.NewNullLogger())
+	supervisor.SetRunner(runner)
+
+	// A burst of restarts of the same component results in a single restart
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, supervisor.Restart("123", "component", component))
+		}()
+	}
+	wg.Wait()
+
+	opens, closes := component.Counts()
+	assert.Equal(t, 1, opens)
+	assert.Equal(t, 1, closes)
+}
+
+func TestRestartBudget(t *testing.T) {
+	runner := refer.NewRunReferencesDecorator(crefer.NewEmptyReferences(), nil)
+	components := []*restartableComponent{}
+	for i := 0; i < 4; i++ {
+		component := &restartableComponent{}
+		components = append(components, component)
+		runner.Put(fmt.Sprintf("component%d", i), component)
+	}
+
+	supervisor := container.NewComponentSupervisor(log.NewNullLogger())
+	supervisor.SetRunner(runner)
+	supervisor.SetRestartBudget(2, 200*time.Millisecond)
+
+	// Restarts above the budget wait until the budget window frees up
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i, component := range components {
+		wg.Add(1)
+		go func(name string, component *restartableComponent) {
+			defer wg.Done()
+			assert.Nil(t, supervisor.Restart("123", name, component))
+		}(fmt.Sprintf("component%d", i), component)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	restarted := 0
+	for _, component := range components {
+		if opens, _ := component.Counts(); opens > 0 {
+			restarted++
+		}
+	}
+	assert.Equal(t, 2, restarted)
+
+	wg.Wait()
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+	for _, component := range components {
+		opens, _ := component.Counts()
+		assert.Equal(t, 1, opens)
+	}
+}
@@ -0,0 +1,30 @@
+package test_container
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestDryRunConfigDir(t *testing.T) {
+	c := container.NewEmptyContainer()
+
+	_, err := c.DryRunConfigDir("123", "./missing", nil)
+	assert.NotNil(t, err)
+
+	dir, err := ioutil.TempDir("", "configs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "README.txt"), []byte("Tenant configurations"), 0644)
+	assert.Nil(t, err)
+
+	failures, err := c.DryRunConfigDir("123", dir, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, failures)
+}
@@ -0,0 +1,38 @@
+package test_container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+type requireNamespaceRule struct{}
+
+func (c *requireNamespaceRule) Name() string {
+	return "require-namespace"
+}
+
+func (c *requireNamespaceRule) Check(context *container.LintContext) []*container.LintFinding {
+	return []*container.LintFinding{
+		{Severity: container.LintError, Message: "Namespace is not set"},
+	}
+}
+
+func TestConfigLinter(t *testing.T) {
+	linter := container.NewConfigLinter()
+	linter.AddRule(&requireNamespaceRule{})
+
+	findings := linter.Lint(&container.LintContext{
+		Config: config.ContainerConfig{},
+	})
+
+	assert.Len(t, findings, 2)
+	assert.Equal(t, "require-namespace", findings[0].Rule)
+	assert.Equal(t, container.LintError, findings[0].Severity)
+	assert.Equal(t, "missing-logger", findings[1].Rule)
+	assert.Equal(t, "error [require-namespace]: Namespace is not set", findings[0].String())
+	assert.True(t, container.HasLintErrors(findings))
+}
@@ -0,0 +1,92 @@
+package test_container
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestConfigTransformers(t *testing.T) {
+	factory := build.NewFactory()
+	for _, typ := range []string{"service", "debug", "audit"} {
+		factory.Register(crefer.NewDescriptor("mygroup", typ, "*", "*", "1.0"),
+			func(locator interface{}) interface{} {
+				return &restartableComponent{}
+			})
+	}
+
+	c := container.NewContainer("test", "Test container")
+	c.AddFactory(factory)
+	err := c.ReadConfigFromBytes("123", []byte(`
+- descriptor: "pip-services:container:default:default:1.0"
+  shutdown_timeout: 1000
+- descriptor: "mygroup:service:default:default:1.0"
+- descriptor: "mygroup:debug:default:default:1.0"
+`), ".yml", nil)
+	assert.Nil(t, err)
+
+	received := []string{}
+	c.AddConfigTransformer(func(correlationId string, containerConfig config.ContainerConfig) (config.ContainerConfig, error) {
+		for _, componentConfig := range containerConfig {
+			received = append(received, componentConfig.Descriptor.String())
+		}
+		// Prune debug components
+		_, result := containerConfig.SplitByTypes("debug")
+		return result, nil
+	})
+	c.AddConfigTransformer(func(correlationId string, containerConfig config.ContainerConfig) (config.ContainerConfig, error) {
+		// Inject an audit component
+		audit := config.NewComponentConfigFromDescriptor(
+			crefer.NewDescriptor("mygroup", "audit", "default", "default", "1.0"), nil)
+		return append(append(config.ContainerConfig{}, containerConfig...), audit), nil
+	})
+
+	err = c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	// Transformers receive components without container options
+	assert.Equal(t, []string{
+		"mygroup:service:default:default:1.0",
+		"mygroup:debug:default:default:1.0",
+	}, received)
+
+	components := map[string]bool{}
+	for _, component := range c.Introspect().Components {
+		components[component.Locator] = true
+	}
+	assert.True(t, components["mygroup:service:default:default:1.0"])
+	assert.True(t, components["mygroup:audit:default:default:1.0"])
+	assert.False(t, components["mygroup:debug:default:default:1.0"])
+}
+
+func TestFailedConfigTransformer(t *testing.T) {
+	c := container.NewContainer("test", "Test container")
+	err := c.ReadConfigFromBytes("123", []byte(`[]`), ".json", nil)
+	assert.Nil(t, err)
+
+	calls := []string{}
+	c.AddConfigTransformer(func(correlationId string, containerConfig config.ContainerConfig) (config.ContainerConfig, error) {
+		calls = append(calls, "first")
+		return containerConfig, nil
+	})
+	c.AddConfigTransformer(func(correlationId string, containerConfig config.ContainerConfig) (config.ContainerConfig, error) {
+		calls = append(calls, "second")
+		return nil, errors.New("Debug components are not allowed")
+	})
+	c.AddConfigTransformer(func(correlationId string, containerConfig config.ContainerConfig) (config.ContainerConfig, error) {
+		calls = append(calls, "third")
+		return containerConfig, nil
+	})
+
+	err = c.Open("123")
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"first", "second"}, calls)
+	assert.Equal(t, container.StateFailed, c.GetState())
+}
@@ -0,0 +1,205 @@
+package test_container
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-commons-go/run"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func drainEvents(events chan container.ContainerEvent) []string {
+	result := []string{}
+	for {
+		select {
+		case e := <-events:
+			result = append(result, e.Event)
+		default:
+			return result
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	c := newFixtureContainer(t, singleComponentConfig, map[string]interface{}{
+		"mygroup:component:default:default:1.0": &restartableComponent{},
+	})
+	events := make(chan container.ContainerEvent, 100)
+	c.Subscribe(events)
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	err = c.Close("123")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		container.EventOpening, container.EventOpened, container.EventClosing, container.EventClosed,
+	}, drainEvents(events))
+
+	// Unsubscribed channels don't receive events anymore
+	c.Unsubscribe(events)
+	err = c.Open("123")
+	assert.Nil(t, err)
+	err = c.Close("123")
+	assert.Nil(t, err)
+	assert.Empty(t, drainEvents(events))
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	c := newFixtureContainer(t, singleComponentConfig, map[string]interface{}{
+		"mygroup:component:default:default:1.0": &restartableComponent{},
+	})
+	blocked := make(chan container.ContainerEvent)
+	bounded := make(chan container.ContainerEvent, 1)
+	c.Subscribe(blocked)
+	c.Subscribe(bounded)
+
+	// Subscribers that don't read events can't block the container
+	done := make(chan error)
+	go func() {
+		err := c.Open("123")
+		if err == nil {
+			err = c.Close("123")
+		}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Container is blocked by subscribers")
+	}
+
+	// Events above the channel buffer are dropped
+	assert.Equal(t, []string{container.EventOpening}, drainEvents(bounded))
+	assert.Empty(t, drainEvents(blocked))
+}
+
+type notifiableComponent struct {
+	events []string
+	lock   sync.Mutex
+}
+
+func (c *notifiableComponent) Notify(correlationId string, args *run.Parameters) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	event := args.GetAsString("event")
+	if args.Contains("components") {
+		event = event + " " + args.GetAsString("components")
+	}
+	c.events = append(c.events, event)
+}
+
+func (c *notifiableComponent) Events() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]string{}, c.events...)
+}
+
+type panickingNotifiable struct{}
+
+func (c *panickingNotifiable) Notify(correlationId string, args *run.Parameters) {
+	panic("notify")
+}
+
+func TestNotifyComponents(t *testing.T) {
+	first := &notifiableComponent{}
+	second := &notifiableComponent{}
+	c := newFixtureContainer(t, `
+- descriptor: "mygroup:first:default:default:1.0"
+- descriptor: "mygroup:panicking:default:default:1.0"
+- descriptor: "mygroup:second:default:default:1.0"
+`, map[string]interface{}{
+		"mygroup:first:default:default:1.0":     first,
+		"mygroup:panicking:default:default:1.0": &panickingNotifiable{},
+		"mygroup:second:default:default:1.0":    second,
+	})
+	c.Supervisor().SetRestartLimits(1, time.Minute)
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+
+	// The second restart quarantines the component and degrades the container
+	locator := crefer.NewDescriptor("mygroup", "second", "default", "default", "1.0")
+	err = c.RestartComponent("123", locator)
+	assert.Nil(t, err)
+	err = c.RestartComponent("123", locator)
+	assert.NotNil(t, err)
+
+	err = c.Close("123")
+	assert.Nil(t, err)
+
+	// A panicking component doesn't prevent notification of the others
+	expected := []string{container.EventOpened, container.EventDegraded, container.EventClosing}
+	assert.Equal(t, expected, first.Events())
+	assert.Equal(t, expected, second.Events())
+}
+
+type notifiableParent struct {
+	notifiableComponent
+}
+
+func (c *notifiableParent) SetReferences(references crefer.IReferences) {}
+
+func TestNotifyInheritingObject(t *testing.T) {
+	parent := &notifiableParent{}
+	c := container.InheritContainer("test", "Test container", parent)
+	c.Configure(cconfig.NewConfigParamsFromTuples(
+		"panicking.descriptor", "mygroup:panicking:default:default:1.0",
+	))
+	factory := build.NewFactory()
+	factory.Register(crefer.NewDescriptor("mygroup", "panicking", "default", "default", "1.0"),
+		func(locator interface{}) interface{} {
+			return &panickingNotifiable{}
+		})
+	c.AddFactory(factory)
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	err = c.Close("123")
+	assert.Nil(t, err)
+
+	// The inheriting object is notified like container components
+	assert.Equal(t, []string{container.EventOpened, container.EventClosing}, parent.Events())
+}
+
+func TestNotifyReloadedComponents(t *testing.T) {
+	first := &notifiableComponent{}
+	second := &notifiableComponent{}
+	c := newFixtureContainer(t, `
+- descriptor: "mygroup:first:default:default:1.0"
+- descriptor: "mygroup:panicking:default:default:1.0"
+`, map[string]interface{}{
+		"mygroup:first:default:default:1.0":     first,
+		"mygroup:panicking:default:default:1.0": &panickingNotifiable{},
+		"mygroup:second:default:default:1.0":    second,
+	})
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+
+	// Added components are notified about the reload that added them
+	updated, err := config.ReadContainerConfigFromConfig(cconfig.NewConfigParamsFromTuples(
+		"first.descriptor", "mygroup:first:default:default:1.0",
+		"panicking.descriptor", "mygroup:panicking:default:default:1.0",
+		"second.descriptor", "mygroup:second:default:default:1.0",
+	))
+	assert.Nil(t, err)
+	err = c.ReloadConfig("123", updated)
+	assert.Nil(t, err)
+
+	err = c.Close("123")
+	assert.Nil(t, err)
+
+	reloaded := container.EventReloaded + " mygroup:second:default:default:1.0"
+	assert.Equal(t, []string{container.EventOpened, reloaded, container.EventClosing}, first.Events())
+	assert.Equal(t, []string{reloaded, container.EventClosing}, second.Events())
+}
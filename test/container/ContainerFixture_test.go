@@ -0,0 +1,37 @@
+package test_container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+// Configuration of a single component, created from "mygroup:component:default:default:1.0" descriptor.
+const singleComponentConfig = `
+- descriptor: "mygroup:component:default:default:1.0"
+`
+
+// Creates a test container configured by YAML configuration.
+// Components are created from the map of their descriptors to instances,
+// so tests can inspect the same instances the container opens and closes.
+func newFixtureContainer(t *testing.T, content string, components map[string]interface{}) *container.Container {
+	factory := build.NewFactory()
+	for descriptor, component := range components {
+		component := component
+		locator, err := crefer.ParseDescriptorFromString(descriptor)
+		assert.Nil(t, err)
+		factory.Register(locator, func(locator interface{}) interface{} {
+			return component
+		})
+	}
+
+	c := container.NewContainer("test", "Test container")
+	c.AddFactory(factory)
+	err := c.ReadConfigFromBytes("123", []byte(content), ".yml", nil)
+	assert.Nil(t, err)
+	return c
+}
@@ -0,0 +1,53 @@
+package test_container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+type sessionStore struct {
+	configured int
+}
+
+func (c *sessionStore) Configure(config *cconfig.ConfigParams) {
+	c.configured++
+}
+
+func TestReloadNeverReconfiguresFrozenComponents(t *testing.T) {
+	store := &sessionStore{}
+	factory := build.NewFactory()
+	factory.Register(crefer.NewDescriptor("mygroup", "sessions", "memory", "default", "1.0"),
+		func(locator interface{}) interface{} {
+			return store
+		})
+
+	c := container.NewContainer("test", "Test container")
+	err := c.TryAddFactory(factory)
+	assert.Nil(t, err)
+	err = c.ReadConfigFromBytes("123", []byte(`
+- descriptor: "mygroup:sessions:memory:default:1.0"
+  reload: never
+  max_size: 100
+`), ".yml", nil)
+	assert.Nil(t, err)
+	err = c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	updated, err := config.ContainerConfigReader.ReadFromBytes("123", []byte(`
+- descriptor: "mygroup:sessions:memory:default:1.0"
+  reload: never
+  max_size: 200
+`), ".yml", nil)
+	assert.Nil(t, err)
+	err = c.ReloadConfig("123", updated)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, store.configured)
+}
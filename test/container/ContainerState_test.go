@@ -0,0 +1,73 @@
+package test_container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestContainerState(t *testing.T) {
+	c := container.NewContainer("test", "Test container")
+
+	assert.Equal(t, container.StateCreated, c.GetState())
+	assert.Equal(t, "Created", c.GetState().String())
+
+	err := c.WaitForState(container.StateCreated, 0)
+	assert.Nil(t, err)
+
+	err = c.WaitForState(container.StateOpen, 10*time.Millisecond)
+	assert.NotNil(t, err)
+}
+
+type blockingStore struct {
+	configured int
+	reloading  chan struct{}
+	release    chan struct{}
+}
+
+func (c *blockingStore) Configure(config *cconfig.ConfigParams) {
+	c.configured++
+	if c.configured > 1 {
+		close(c.reloading)
+		<-c.release
+	}
+}
+
+func TestOpenWhileReloading(t *testing.T) {
+	store := &blockingStore{reloading: make(chan struct{}), release: make(chan struct{})}
+	c := newFixtureContainer(t, `
+- descriptor: "mygroup:sessions:memory:default:1.0"
+  max_size: 100
+`, map[string]interface{}{
+		"mygroup:sessions:memory:default:1.0": store,
+	})
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	updated, err := config.ContainerConfigReader.ReadFromBytes("123", []byte(`
+- descriptor: "mygroup:sessions:memory:default:1.0"
+  max_size: 200
+`), ".yml", nil)
+	assert.Nil(t, err)
+
+	reloaded := make(chan error)
+	go func() {
+		reloaded <- c.ReloadConfig("123", updated)
+	}()
+
+	// The container keeps serving while components are reconfigured
+	<-store.reloading
+	assert.Equal(t, container.StateReloading, c.GetState())
+	assert.True(t, c.IsOpen())
+
+	close(store.release)
+	assert.Nil(t, <-reloaded)
+	assert.Equal(t, container.StateOpen, c.GetState())
+	assert.True(t, c.IsOpen())
+}
@@ -0,0 +1,97 @@
+package test_container
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+// Buffered component like CachedLogger or CachedCounters
+type dumpableComponent struct {
+	dumps int
+	delay time.Duration
+	err   error
+	lock  sync.Mutex
+}
+
+func (c *dumpableComponent) Dump() error {
+	time.Sleep(c.delay)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.dumps++
+	return c.err
+}
+
+func (c *dumpableComponent) Dumps() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.dumps
+}
+
+const telemetryConfig = `
+- descriptor: "pip-services:container:default:default:1.0"
+  telemetry:
+    flush_timeout: 100
+- descriptor: "mygroup:counters:cached:default:1.0"
+- descriptor: "mygroup:logger:cached:default:1.0"
+`
+
+func TestFlushTelemetry(t *testing.T) {
+	logger := &dumpableComponent{}
+	counters := &dumpableComponent{}
+	c := newFixtureContainer(t, telemetryConfig, map[string]interface{}{
+		"mygroup:logger:cached:default:1.0":   logger,
+		"mygroup:counters:cached:default:1.0": counters,
+	})
+	assert.Nil(t, c.FlushTelemetry("123"))
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+
+	err = c.FlushTelemetry("123")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, logger.Dumps())
+	assert.Equal(t, 1, counters.Dumps())
+
+	// Failures are reported after all components are flushed
+	logger.err = errors.New("Connection refused")
+	err = c.FlushTelemetry("123")
+	assert.Equal(t, logger.err, err)
+	assert.Equal(t, 2, counters.Dumps())
+	logger.err = nil
+
+	// Buffered components are flushed once more when the container is closed
+	err = c.Close("123")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, logger.Dumps())
+	assert.Equal(t, 3, counters.Dumps())
+}
+
+func TestFlushTelemetryTimeout(t *testing.T) {
+	logger := &dumpableComponent{delay: time.Second}
+	counters := &dumpableComponent{}
+	c := newFixtureContainer(t, telemetryConfig, map[string]interface{}{
+		"mygroup:logger:cached:default:1.0":   logger,
+		"mygroup:counters:cached:default:1.0": counters,
+	})
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+
+	// A stuck backend doesn't delay the caller longer than the flush timeout
+	start := time.Now()
+	err = c.FlushTelemetry("123")
+	assert.NotNil(t, err)
+	assert.Equal(t, "FLUSH_TIMEOUT", err.(*cerr.ApplicationError).Code)
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+
+	start = time.Now()
+	err = c.Close("123")
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+}
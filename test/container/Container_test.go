@@ -0,0 +1,87 @@
+package test_container
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestAddNilFactory(t *testing.T) {
+	c := container.NewContainer("test", "Test container")
+
+	err := c.TryAddFactory(nil)
+	assert.NotNil(t, err)
+	err = c.TryAddFactory(build.NewFactory())
+	assert.Nil(t, err)
+
+	// Nil factories are ignored
+	c.AddFactory(nil)
+	err = c.ReadConfigFromBytes("123", []byte(`[]`), ".json", nil)
+	assert.Nil(t, err)
+	err = c.Open("123")
+	assert.Nil(t, err)
+	c.Close("123")
+
+	err = c.RegisterCommand("", nil)
+	assert.NotNil(t, err)
+}
+
+func TestConcurrentOpenClose(t *testing.T) {
+	c := newFixtureContainer(t, singleComponentConfig, map[string]interface{}{
+		"mygroup:component:default:default:1.0": &restartableComponent{},
+	})
+
+	var wg sync.WaitGroup
+	opened := int32(0)
+	stop := make(chan struct{})
+
+	// Readers run while the references are replaced by Open and Close
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				c.GetGoroutineCounts()
+				c.GetComponentDependents(crefer.NewDescriptor("*", "*", "*", "*", "*"), true)
+				c.GetListeners()
+				c.GetWiringReport()
+				c.Introspect()
+				c.RestartComponent("123", crefer.NewDescriptor("mygroup", "component", "*", "*", "*"))
+				c.InjectInto(&struct{}{})
+			}
+		}()
+	}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if c.Open("123") == nil {
+					atomic.AddInt32(&opened, 1)
+				}
+				c.Close("123")
+			}
+		}()
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+
+	c.Close("123")
+	assert.False(t, c.IsOpen())
+	assert.True(t, atomic.LoadInt32(&opened) > 0)
+}
@@ -0,0 +1,102 @@
+package test_container
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+var traceparentPattern = regexp.MustCompile("^00-[0-9a-f]{32}-[0-9a-f]{16}-01$")
+
+type correlatedComponent struct {
+	restartableComponent
+	correlationIds []string
+	lock           sync.Mutex
+}
+
+func (c *correlatedComponent) Open(correlationId string) error {
+	c.lock.Lock()
+	c.correlationIds = append(c.correlationIds, correlationId)
+	c.lock.Unlock()
+	return c.restartableComponent.Open(correlationId)
+}
+
+func (c *correlatedComponent) Close(correlationId string) error {
+	c.lock.Lock()
+	c.correlationIds = append(c.correlationIds, correlationId)
+	c.lock.Unlock()
+	return c.restartableComponent.Close(correlationId)
+}
+
+func (c *correlatedComponent) CorrelationIds() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]string{}, c.correlationIds...)
+}
+
+func TestCorrelationIdStrategies(t *testing.T) {
+	strategy, err := container.NewCorrelationIdStrategy("static")
+	assert.Nil(t, err)
+	assert.Equal(t, "123", strategy.Next("123", "open"))
+
+	strategy, err = container.NewCorrelationIdStrategy("uuid")
+	assert.Nil(t, err)
+	first := strategy.Next("123", "open")
+	second := strategy.Next("123", "open")
+	assert.NotEqual(t, "123", first)
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+
+	strategy, err = container.NewCorrelationIdStrategy("TraceParent")
+	assert.Nil(t, err)
+
+	// A new trace is started when the caller doesn't pass a traceparent
+	first = strategy.Next("123", "open")
+	assert.Regexp(t, traceparentPattern, first)
+	second = strategy.Next("123", "open")
+	assert.NotEqual(t, strings.Split(first, "-")[1], strings.Split(second, "-")[1])
+
+	// The caller trace is continued with a new parent id
+	caller := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	next := strategy.Next(caller, "close")
+	assert.Regexp(t, traceparentPattern, next)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", strings.Split(next, "-")[1])
+	assert.NotEqual(t, "00f067aa0ba902b7", strings.Split(next, "-")[2])
+
+	_, err = container.NewCorrelationIdStrategy("sequential")
+	assert.NotNil(t, err)
+	assert.Equal(t, "BAD_CORRELATION_STRATEGY", err.(*cerr.ApplicationError).Code)
+}
+
+func TestContainerCorrelationIds(t *testing.T) {
+	component := &correlatedComponent{}
+	c := newFixtureContainer(t, `
+- descriptor: "pip-services:container:default:default:1.0"
+  correlation_ids:
+    strategy: traceparent
+- descriptor: "mygroup:component:default:default:1.0"
+`, map[string]interface{}{
+		"mygroup:component:default:default:1.0": component,
+	})
+
+	caller := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	err := c.Open(caller)
+	assert.Nil(t, err)
+	err = c.Close(caller)
+	assert.Nil(t, err)
+
+	// Components receive traceparents that continue the caller trace
+	correlationIds := component.CorrelationIds()
+	assert.Len(t, correlationIds, 2)
+	for _, correlationId := range correlationIds {
+		assert.Regexp(t, traceparentPattern, correlationId)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", strings.Split(correlationId, "-")[1])
+	}
+	assert.NotEqual(t, correlationIds[0], correlationIds[1])
+}
@@ -0,0 +1,38 @@
+package test_container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronExpressionMatch(t *testing.T) {
+	expr, err := container.ParseCronExpression("*/15 22 * * 1-5")
+	assert.Nil(t, err)
+
+	// Monday
+	assert.True(t, expr.Match(time.Date(2021, 5, 3, 22, 30, 0, 0, time.UTC)))
+	assert.False(t, expr.Match(time.Date(2021, 5, 3, 22, 31, 0, 0, time.UTC)))
+	// Sunday
+	assert.False(t, expr.Match(time.Date(2021, 5, 2, 22, 30, 0, 0, time.UTC)))
+}
+
+func TestCronExpressionPrevious(t *testing.T) {
+	expr, err := container.ParseCronExpression("0 6 * * *")
+	assert.Nil(t, err)
+
+	now := time.Date(2021, 5, 3, 5, 0, 0, 0, time.UTC)
+	previous, ok := expr.Previous(now, 48*time.Hour)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2021, 5, 2, 6, 0, 0, 0, time.UTC), previous)
+}
+
+func TestInvalidCronExpression(t *testing.T) {
+	_, err := container.ParseCronExpression("0 25 * * *")
+	assert.NotNil(t, err)
+
+	_, err = container.ParseCronExpression("0 6 * *")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,127 @@
+package test_container
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type healthComponent struct {
+	restartableComponent
+	healthy bool
+	lock    sync.Mutex
+}
+
+func (c *healthComponent) SetHealthy(healthy bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.healthy = healthy
+}
+
+func (c *healthComponent) IsHealthy(correlationId string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.healthy
+}
+
+const degradableConfig = `
+- descriptor: "pip-services:container:default:default:1.0"
+  shedding:
+    threshold: 0.5
+    retry_after: 10
+- descriptor: "mygroup:cache:default:default:1.0"
+- descriptor: "mygroup:database:default:default:1.0"
+  criticality: 10
+- descriptor: "mygroup:metrics:default:default:1.0"
+  criticality: 0
+`
+
+func TestDegradationScore(t *testing.T) {
+	database := &healthComponent{healthy: true}
+	cache := &healthComponent{healthy: true}
+	metrics := &healthComponent{healthy: true}
+	c := newFixtureContainer(t, degradableConfig, map[string]interface{}{
+		"mygroup:database:default:default:1.0": database,
+		"mygroup:cache:default:default:1.0":    cache,
+		"mygroup:metrics:default:default:1.0":  metrics,
+	})
+	assert.Nil(t, c.GetDegradation("123"))
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	degradation := c.GetDegradation("123")
+	assert.Equal(t, 0.0, degradation.Score)
+	assert.Equal(t, degradation.Total, degradation.Healthy)
+	assert.Empty(t, degradation.Unhealthy)
+
+	// Unhealthy components reduce the score by their criticality
+	database.SetHealthy(false)
+	degradation = c.GetDegradation("123")
+	assert.Equal(t, []string{"mygroup:database:default:default:1.0"}, degradation.Unhealthy)
+	assert.Equal(t, degradation.Total-10, degradation.Healthy)
+	assert.InDelta(t, 10/degradation.Total, degradation.Score, 1e-9)
+	database.SetHealthy(true)
+
+	cache.SetHealthy(false)
+	degradation = c.GetDegradation("123")
+	assert.Equal(t, []string{"mygroup:cache:default:default:1.0"}, degradation.Unhealthy)
+	assert.InDelta(t, 1/degradation.Total, degradation.Score, 1e-9)
+	cache.SetHealthy(true)
+
+	// Components with zero criticality don't affect the score
+	metrics.SetHealthy(false)
+	degradation = c.GetDegradation("123")
+	assert.Equal(t, 0.0, degradation.Score)
+	assert.Empty(t, degradation.Unhealthy)
+
+	// Closed components are unhealthy
+	cache.Close("123")
+	degradation = c.GetDegradation("123")
+	assert.Equal(t, []string{"mygroup:cache:default:default:1.0"}, degradation.Unhealthy)
+}
+
+func TestLoadShedding(t *testing.T) {
+	database := &healthComponent{healthy: true}
+	cache := &healthComponent{healthy: true}
+	metrics := &healthComponent{healthy: true}
+	c := newFixtureContainer(t, degradableConfig, map[string]interface{}{
+		"mygroup:database:default:default:1.0": database,
+		"mygroup:cache:default:default:1.0":    cache,
+		"mygroup:metrics:default:default:1.0":  metrics,
+	})
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	handler := c.SheddingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		return recorder
+	}
+
+	// A failure of a minor component keeps the score below the threshold
+	cache.SetHealthy(false)
+	assert.False(t, c.ShouldShedLoad())
+	assert.Equal(t, http.StatusOK, serve().Code)
+
+	// A failure of a critical component reaches the threshold
+	database.SetHealthy(false)
+	assert.True(t, c.ShouldShedLoad())
+	response := serve()
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Equal(t, "10", response.Header().Get("Retry-After"))
+
+	database.SetHealthy(true)
+	cache.SetHealthy(true)
+	assert.False(t, c.ShouldShedLoad())
+	assert.Equal(t, http.StatusOK, serve().Code)
+}
@@ -0,0 +1,77 @@
+package test_container
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestExportDependencyGraph(t *testing.T) {
+	factory := build.NewFactory()
+	for _, typ := range []string{"controller", "persistence"} {
+		factory.Register(crefer.NewDescriptor("mygroup", typ, "*", "*", "1.0"),
+			func(locator interface{}) interface{} {
+				return &restartableComponent{}
+			})
+	}
+
+	c := container.NewContainer("test", "Test container")
+	c.AddFactory(factory)
+	_, err := c.ExportDependencyGraph(container.DependencyGraphDot)
+	assert.NotNil(t, err)
+
+	err = c.ReadConfigFromBytes("123", []byte(`
+- descriptor: "mygroup:persistence:memory:default:1.0"
+- descriptor: "mygroup:controller:default:default:1.0"
+  dependencies:
+    persistence: "mygroup:persistence:*:*:1.0"
+    cache: "mygroup:cache:*:*:1.0"
+`), ".yml", nil)
+	assert.Nil(t, err)
+	err = c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	data, err := c.ExportDependencyGraph(container.DependencyGraphDot)
+	assert.Nil(t, err)
+	dot := string(data)
+	assert.True(t, strings.HasPrefix(dot, "digraph \"test\" {"))
+	assert.Contains(t, dot, `  "mygroup:controller:default:default:1.0" -> "mygroup:persistence:memory:default:1.0" [label="persistence"];`)
+	assert.Contains(t, dot, `  "mygroup:controller:default:default:1.0" -> "mygroup:cache:*:*:1.0" [label="cache"];`)
+	// Dependencies that no component satisfies are drawn dashed
+	assert.Contains(t, dot, `  "mygroup:cache:*:*:1.0" [style=dashed, color=red];`)
+	assert.Contains(t, dot, `  "mygroup:persistence:memory:default:1.0";`)
+
+	data, err = c.ExportDependencyGraph(container.DependencyGraphJson)
+	assert.Nil(t, err)
+	graph := &container.DependencyGraph{}
+	err = json.Unmarshal(data, graph)
+	assert.Nil(t, err)
+
+	edges := map[string]string{}
+	for _, edge := range graph.Edges {
+		assert.Equal(t, "mygroup:controller:default:default:1.0", edge.From)
+		edges[edge.Dependency] = edge.To
+	}
+	assert.Equal(t, map[string]string{
+		"cache":       "mygroup:cache:*:*:1.0",
+		"persistence": "mygroup:persistence:memory:default:1.0",
+	}, edges)
+
+	missing := []string{}
+	for _, node := range graph.Nodes {
+		if node.Missing {
+			missing = append(missing, node.Id)
+		}
+	}
+	assert.Equal(t, []string{"mygroup:cache:*:*:1.0"}, missing)
+
+	_, err = c.ExportDependencyGraph("svg")
+	assert.NotNil(t, err)
+}
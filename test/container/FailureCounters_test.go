@@ -0,0 +1,25 @@
+package test_container
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestGetFailureCategory(t *testing.T) {
+	assert.Equal(t, container.FailureTimeout, container.GetFailureCategory(nil, true))
+	assert.Equal(t, container.FailureInternal, container.GetFailureCategory(errors.New("test"), false))
+
+	err := &cerr.ApplicationError{Category: "Misconfiguration", Code: "BAD_CONFIG"}
+	assert.Equal(t, container.FailureConfig, container.GetFailureCategory(err, false))
+
+	err = &cerr.ApplicationError{Category: "NoResponse", Code: "CONNECT_FAILED"}
+	assert.Equal(t, container.FailureConnection, container.GetFailureCategory(err, false))
+
+	err = &cerr.ApplicationError{Category: "InvalidState", Code: "BARRIER_TIMEOUT"}
+	assert.Equal(t, container.FailureTimeout, container.GetFailureCategory(err, false))
+}
@@ -0,0 +1,92 @@
+package test_container
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+type trackedWorker struct {
+	*container.GoroutineTracker
+	stop chan struct{}
+}
+
+func (c *trackedWorker) Start(workers int) {
+	for i := 0; i < workers; i++ {
+		c.Go(func() {
+			<-c.stop
+		})
+	}
+}
+
+func TestGoroutineTracker(t *testing.T) {
+	tracker := container.NewGoroutineTracker()
+	assert.Equal(t, int64(0), tracker.GoroutineCount())
+
+	stop := make(chan struct{})
+	started := make(chan struct{}, 3)
+	for i := 0; i < 3; i++ {
+		tracker.Go(func() {
+			started <- struct{}{}
+			<-stop
+		})
+	}
+	for i := 0; i < 3; i++ {
+		<-started
+	}
+	assert.Equal(t, int64(3), tracker.GoroutineCount())
+
+	// Goroutines are accounted until they return
+	close(stop)
+	tracker.Wait()
+	assert.Equal(t, int64(0), tracker.GoroutineCount())
+}
+
+func TestGetGoroutineCounts(t *testing.T) {
+	worker := &trackedWorker{
+		GoroutineTracker: container.NewGoroutineTracker(),
+		stop:             make(chan struct{}),
+	}
+	c := newFixtureContainer(t, `
+- descriptor: "mygroup:worker:default:default:1.0"
+`, map[string]interface{}{
+		"mygroup:worker:default:default:1.0": worker,
+	})
+
+	// Only the process total is known before the container is opened
+	counts := c.GetGoroutineCounts()
+	assert.Len(t, counts, 1)
+	assert.True(t, counts["process"] > 0)
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	worker.Start(2)
+	counts = c.GetGoroutineCounts()
+	assert.Equal(t, int64(2), counts["mygroup:worker:default:default:1.0"])
+	assert.True(t, counts["process"] >= 2)
+
+	// Counts are exposed to observers by introspection
+	introspection := c.Introspect()
+	assert.Equal(t, int64(2), introspection.Goroutines["mygroup:worker:default:default:1.0"])
+
+	recorder := httptest.NewRecorder()
+	c.IntrospectionHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/goroutines", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	served := map[string]int64{}
+	err = json.Unmarshal(recorder.Body.Bytes(), &served)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), served["mygroup:worker:default:default:1.0"])
+	assert.True(t, served["process"] >= 2)
+
+	close(worker.stop)
+	worker.Wait()
+	counts = c.GetGoroutineCounts()
+	assert.Equal(t, int64(0), counts["mygroup:worker:default:default:1.0"])
+}
@@ -0,0 +1,60 @@
+package test_container
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestResolveHostInfo(t *testing.T) {
+	hostname, _ := os.Hostname()
+
+	host := container.ResolveHostInfo()
+	assert.Equal(t, hostname, host.Name)
+	assert.NotEmpty(t, host.Fqdn)
+
+	parameters := host.GetParameters()
+	assert.Equal(t, host.Name, parameters.GetAsString(container.HostNameParameter))
+	assert.Equal(t, host.Fqdn, parameters.GetAsString(container.HostFqdnParameter))
+	assert.Equal(t, host.Ip, parameters.GetAsString(container.HostIpParameter))
+	assert.Equal(t, strings.Join(host.Ips, ","), parameters.GetAsString(container.HostIpsParameter))
+
+	properties := host.GetProperties()
+	assert.Equal(t, host.Name, properties["host.name"])
+	assert.Equal(t, host.Ip, properties["host.ip"])
+}
+
+func TestHostParameters(t *testing.T) {
+	hostname, _ := os.Hostname()
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	err := ioutil.WriteFile(configPath, []byte(`
+- descriptor: "pip-services:discovery:memory:default:1.0"
+  host_name: "{{HOST_NAME}}"
+  host_ip: "{{HOST_IP}}"
+`), 0644)
+	assert.Nil(t, err)
+
+	// Explicit parameters take precedence over resolved host addresses
+	c := container.NewContainer("test", "Test container")
+	err = c.ReadConfigFromFile("123", configPath, cconfig.NewConfigParamsFromTuples(
+		container.HostIpParameter, "10.0.0.1",
+	))
+	assert.Nil(t, err)
+
+	bundle := c.ExportSupportBundle()
+	assert.Equal(t, hostname, bundle.Parameters[container.HostNameParameter])
+	assert.Equal(t, "10.0.0.1", bundle.Parameters[container.HostIpParameter])
+
+	err = c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	assert.Equal(t, hostname, c.Info().Properties["host.name"])
+}
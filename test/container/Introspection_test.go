@@ -0,0 +1,71 @@
+package test_container
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestIntrospectionHandlerIsReadOnly(t *testing.T) {
+	c := container.NewEmptyContainer()
+	handler := c.IntrospectionHandler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/components", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/components", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	introspection := c.Introspect()
+	assert.Equal(t, container.StateCreated.String(), introspection.State)
+	assert.Empty(t, introspection.Components)
+}
+
+func getConfigHash(t *testing.T, content string) string {
+	c := newFixtureContainer(t, content, map[string]interface{}{
+		"mygroup:component:default:default:1.0": &restartableComponent{},
+	})
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	for _, component := range c.Introspect().Components {
+		if strings.HasPrefix(component.Locator, "mygroup:component") {
+			return component.ConfigHash
+		}
+	}
+	return ""
+}
+
+func TestConfigHashSkipsSecrets(t *testing.T) {
+	hash := getConfigHash(t, `
+- descriptor: "mygroup:component:default:default:1.0"
+  interval: 1000
+  credential:
+    password: "pass1"
+`)
+	assert.NotEmpty(t, hash)
+
+	// Secrets don't change the hash, so it can't be used to guess them
+	assert.Equal(t, hash, getConfigHash(t, `
+- descriptor: "mygroup:component:default:default:1.0"
+  interval: 1000
+  credential:
+    password: "pass2"
+`))
+
+	assert.NotEqual(t, hash, getConfigHash(t, `
+- descriptor: "mygroup:component:default:default:1.0"
+  interval: 2000
+  credential:
+    password: "pass1"
+`))
+}
@@ -0,0 +1,236 @@
+package test_container
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+type memoryLock struct {
+	locks        map[string]time.Time
+	available    bool
+	releaseDelay time.Duration
+	lock         sync.Mutex
+}
+
+func newMemoryLock() *memoryLock {
+	return &memoryLock{
+		locks:     map[string]time.Time{},
+		available: true,
+	}
+}
+
+func (c *memoryLock) SetAvailable(available bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.available = available
+}
+
+func (c *memoryLock) TryAcquireLock(correlationId string, key string, ttl int64) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.available {
+		return false, nil
+	}
+	if expiration, ok := c.locks[key]; ok && time.Now().Before(expiration) {
+		return false, nil
+	}
+	c.locks[key] = time.Now().Add(time.Duration(ttl) * time.Millisecond)
+	return true, nil
+}
+
+func (c *memoryLock) AcquireLock(correlationId string, key string, ttl int64, timeout int64) error {
+	deadline := time.Now().Add(time.Duration(timeout) * time.Millisecond)
+	for {
+		acquired, err := c.TryAcquireLock(correlationId, key, ttl)
+		if acquired || err != nil {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return errors.New("Acquiring lock " + key + " timed out")
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+func (c *memoryLock) ReleaseLock(correlationId string, key string) error {
+	c.lock.Lock()
+	delete(c.locks, key)
+	delay := c.releaseDelay
+	c.lock.Unlock()
+
+	// Give contenders a chance to take the released lock
+	time.Sleep(delay)
+	return nil
+}
+
+const leaderConfig = `
+- descriptor: "pip-services:container:default:default:1.0"
+  leader_election:
+    key: "worker"
+    ttl: 200
+    interval: 20
+- descriptor: "mygroup:worker:default:default:1.0"
+  leader_only: true
+`
+
+func TestLeaderKeepsLockFromContender(t *testing.T) {
+	lock := newMemoryLock()
+	lock.releaseDelay = 10 * time.Millisecond
+	worker1 := &restartableComponent{}
+	worker2 := &restartableComponent{}
+	c1 := newFixtureContainer(t, leaderConfig, map[string]interface{}{
+		"mygroup:worker:default:default:1.0": worker1,
+	})
+	c1.SetLeaderLock(lock)
+	c2 := newFixtureContainer(t, leaderConfig, map[string]interface{}{
+		"mygroup:worker:default:default:1.0": worker2,
+	})
+	c2.SetLeaderLock(lock)
+
+	err := c1.Open("123")
+	assert.Nil(t, err)
+	err = c2.Open("123")
+	assert.Nil(t, err)
+	defer c2.Close("123")
+	assert.True(t, c1.IsLeader())
+	assert.False(t, c2.IsLeader())
+
+	// The standby polls the lock while the leader acquires it again many times
+	time.Sleep(500 * time.Millisecond)
+	assert.True(t, c1.IsLeader())
+	assert.False(t, c2.IsLeader())
+	opens, _ := worker1.Counts()
+	assert.Equal(t, 1, opens)
+	opens, _ = worker2.Counts()
+	assert.Equal(t, 0, opens)
+
+	// The standby takes over when the leader stops
+	err = c1.Close("123")
+	assert.Nil(t, err)
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, c2.IsLeader())
+	assert.True(t, worker2.IsOpen())
+}
+
+func TestLeadershipLoss(t *testing.T) {
+	lock := newMemoryLock()
+	worker := &restartableComponent{}
+	c := newFixtureContainer(t, leaderConfig, map[string]interface{}{
+		"mygroup:worker:default:default:1.0": worker,
+	})
+	c.SetLeaderLock(lock)
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+	assert.True(t, c.IsLeader())
+	assert.True(t, worker.IsOpen())
+
+	// The leader keeps the leadership until its lock expires
+	lock.SetAvailable(false)
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, c.IsLeader())
+	time.Sleep(300 * time.Millisecond)
+	assert.False(t, c.IsLeader())
+	assert.False(t, worker.IsOpen())
+
+	lock.SetAvailable(true)
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, c.IsLeader())
+	opens, closes := worker.Counts()
+	assert.Equal(t, 2, opens)
+	assert.Equal(t, 1, closes)
+}
+
+const gatedConfig = `
+- descriptor: "pip-services:container:default:default:1.0"
+  standby: %t
+  leader_election:
+    ttl: 200
+    interval: 20
+- descriptor: "mygroup:service:default:default:1.0"
+- descriptor: "mygroup:worker:default:default:1.0"
+  leader_only: true
+`
+
+func waitEvent(events chan container.ContainerEvent, event string, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case e := <-events:
+			if e.Event == event {
+				return true
+			}
+		case <-deadline:
+			return false
+		}
+	}
+}
+
+func TestLeaderOnlyGating(t *testing.T) {
+	lock := newMemoryLock()
+	lock.SetAvailable(false)
+	worker := &restartableComponent{}
+	service := &restartableComponent{}
+	c := newFixtureContainer(t, fmt.Sprintf(gatedConfig, false), map[string]interface{}{
+		"mygroup:worker:default:default:1.0":  worker,
+		"mygroup:service:default:default:1.0": service,
+	})
+	c.SetLeaderLock(lock)
+	events := make(chan container.ContainerEvent, 100)
+	c.Subscribe(events)
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	// Gated components stay closed until the leadership is acquired
+	assert.False(t, c.IsLeader())
+	assert.True(t, service.IsOpen())
+	assert.False(t, worker.IsOpen())
+
+	lock.SetAvailable(true)
+	assert.True(t, waitEvent(events, container.EventPromoted, time.Second))
+	assert.True(t, c.IsLeader())
+	assert.True(t, worker.IsOpen())
+
+	// Gated components are closed when the leadership is lost, the rest keeps running
+	lock.SetAvailable(false)
+	assert.True(t, waitEvent(events, container.EventDemoted, time.Second))
+	assert.False(t, c.IsLeader())
+	assert.False(t, worker.IsOpen())
+	assert.True(t, service.IsOpen())
+}
+
+func TestStandbyGatesAllComponents(t *testing.T) {
+	lock := newMemoryLock()
+	lock.SetAvailable(false)
+	worker := &restartableComponent{}
+	service := &restartableComponent{}
+	c := newFixtureContainer(t, fmt.Sprintf(gatedConfig, true), map[string]interface{}{
+		"mygroup:worker:default:default:1.0":  worker,
+		"mygroup:service:default:default:1.0": service,
+	})
+	c.SetLeaderLock(lock)
+	events := make(chan container.ContainerEvent, 100)
+	c.Subscribe(events)
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+	assert.False(t, service.IsOpen())
+	assert.False(t, worker.IsOpen())
+
+	lock.SetAvailable(true)
+	assert.True(t, waitEvent(events, container.EventPromoted, time.Second))
+	assert.True(t, service.IsOpen())
+	assert.True(t, worker.IsOpen())
+}
@@ -0,0 +1,37 @@
+package test_container
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestLifecycleHistory(t *testing.T) {
+	history := container.NewLifecycleHistory(3)
+	start := time.Now().UTC()
+
+	for index, event := range []string{"opening", "opened", "reloaded", "closing"} {
+		history.Add(container.HistoryEntry{Event: event, Time: start.Add(time.Duration(index) * time.Minute)})
+	}
+
+	entries := history.GetEntries(time.Time{})
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "opened", entries[0].Event)
+	assert.Equal(t, "closing", entries[2].Event)
+
+	entries = history.GetEntries(start.Add(90 * time.Second))
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "reloaded", entries[0].Event)
+
+	history.OnFailed("123", "mygroup:persistence:memory:default:1.0", errors.New("Connection refused"))
+	history.SetSize(2)
+	entries = history.GetEntries(time.Time{})
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "closing", entries[0].Event)
+	assert.Equal(t, container.HistoryComponentFailed, entries[1].Event)
+	assert.Equal(t, "Connection refused", entries[1].Error)
+}
@@ -0,0 +1,181 @@
+package test_container
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+type recordingListener struct {
+	events []string
+	lock   sync.Mutex
+}
+
+func (c *recordingListener) record(event string, locator interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.events = append(c.events, fmt.Sprintf("%s %v", event, locator))
+}
+
+func (c *recordingListener) OnOpening(correlationId string, locator interface{}) {
+	c.record("opening", locator)
+}
+
+func (c *recordingListener) OnOpened(correlationId string, locator interface{}) {
+	c.record("opened", locator)
+}
+
+func (c *recordingListener) OnClosing(correlationId string, locator interface{}) {
+	c.record("closing", locator)
+}
+
+func (c *recordingListener) OnClosed(correlationId string, locator interface{}) {
+	c.record("closed", locator)
+}
+
+func (c *recordingListener) OnFailed(correlationId string, locator interface{}, err error) {
+	c.record("failed", locator)
+}
+
+func (c *recordingListener) Events() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]string{}, c.events...)
+}
+
+type panickingListener struct{}
+
+func (c *panickingListener) OnOpening(correlationId string, locator interface{}) { panic("opening") }
+func (c *panickingListener) OnOpened(correlationId string, locator interface{})  { panic("opened") }
+func (c *panickingListener) OnClosing(correlationId string, locator interface{}) { panic("closing") }
+func (c *panickingListener) OnClosed(correlationId string, locator interface{})  { panic("closed") }
+func (c *panickingListener) OnFailed(correlationId string, locator interface{}, err error) {
+	panic("failed")
+}
+
+// Skips transitions of components added by the container itself.
+func ownEvents(listener *recordingListener) []string {
+	result := []string{}
+	for _, event := range listener.Events() {
+		if !strings.Contains(event, "pip-services:") {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+func TestLifecycleListener(t *testing.T) {
+	component := &restartableComponent{}
+	c := newFixtureContainer(t, singleComponentConfig, map[string]interface{}{
+		"mygroup:component:default:default:1.0": component,
+	})
+	listener := &recordingListener{}
+	c.AddLifecycleListener(listener)
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	err = c.Close("123")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"opening <nil>",
+		"opening mygroup:component:default:default:1.0",
+		"opened mygroup:component:default:default:1.0",
+		"opened <nil>",
+		"closing <nil>",
+		"closing mygroup:component:default:default:1.0",
+		"closed mygroup:component:default:default:1.0",
+		"closed <nil>",
+	}, ownEvents(listener))
+}
+
+func TestLifecycleListenerOnFailure(t *testing.T) {
+	c := newFixtureContainer(t, singleComponentConfig, map[string]interface{}{
+		"mygroup:component:default:default:1.0": &failingComponent{},
+	})
+	listener := &recordingListener{}
+	c.AddLifecycleListener(listener)
+
+	err := c.Open("123")
+	assert.NotNil(t, err)
+
+	events := listener.Events()
+	assert.Contains(t, events, "failed mygroup:component:default:default:1.0")
+	assert.Contains(t, events, "failed <nil>")
+	assert.NotContains(t, events, "opened <nil>")
+}
+
+func TestLifecycleListenerOnRestart(t *testing.T) {
+	component := &restartableComponent{}
+	c := newFixtureContainer(t, singleComponentConfig, map[string]interface{}{
+		"mygroup:component:default:default:1.0": component,
+	})
+	listener := &recordingListener{}
+	c.AddLifecycleListener(listener)
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	// Restarts by the supervisor are reported like any other transition
+	opened := len(listener.Events())
+	err = c.RestartComponent("123", crefer.NewDescriptor("mygroup", "component", "*", "*", "*"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"closing mygroup:component:default:default:1.0",
+		"closed mygroup:component:default:default:1.0",
+		"opening mygroup:component:default:default:1.0",
+		"opened mygroup:component:default:default:1.0",
+	}, listener.Events()[opened:])
+}
+
+func TestLifecycleListenerOnPromotion(t *testing.T) {
+	lock := newMemoryLock()
+	lock.SetAvailable(false)
+	worker := &restartableComponent{}
+	c := newFixtureContainer(t, leaderConfig, map[string]interface{}{
+		"mygroup:worker:default:default:1.0": worker,
+	})
+	c.SetLeaderLock(lock)
+	listener := &recordingListener{}
+	c.AddLifecycleListener(listener)
+	events := make(chan container.ContainerEvent, 100)
+	c.Subscribe(events)
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+	assert.NotContains(t, listener.Events(), "opened mygroup:worker:default:default:1.0")
+
+	// Components gated by the leadership are opened through the runner as well
+	lock.SetAvailable(true)
+	assert.True(t, waitEvent(events, container.EventPromoted, time.Second))
+	assert.Contains(t, listener.Events(), "opening mygroup:worker:default:default:1.0")
+	assert.Contains(t, listener.Events(), "opened mygroup:worker:default:default:1.0")
+}
+
+func TestPanickingLifecycleListener(t *testing.T) {
+	component := &restartableComponent{}
+	c := newFixtureContainer(t, singleComponentConfig, map[string]interface{}{
+		"mygroup:component:default:default:1.0": component,
+	})
+	c.AddLifecycleListener(&panickingListener{})
+	listener := &recordingListener{}
+	c.AddLifecycleListener(listener)
+
+	// Panics in listeners do not break transitions nor other listeners
+	err := c.Open("123")
+	assert.Nil(t, err)
+	assert.True(t, component.IsOpen())
+	err = c.Close("123")
+	assert.Nil(t, err)
+	assert.False(t, component.IsOpen())
+	assert.Len(t, ownEvents(listener), 8)
+}
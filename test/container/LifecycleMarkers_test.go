@@ -0,0 +1,100 @@
+package test_container
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+type failingComponent struct{}
+
+func (c *failingComponent) IsOpen() bool {
+	return false
+}
+
+func (c *failingComponent) Open(correlationId string) error {
+	return errors.New("Connection refused")
+}
+
+func (c *failingComponent) Close(correlationId string) error {
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func modTime(t *testing.T, path string) time.Time {
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func TestLifecycleMarkers(t *testing.T) {
+	dir := t.TempDir()
+	readyPath := filepath.Join(dir, "ready")
+	livePath := filepath.Join(dir, "live")
+	configPath := filepath.Join(dir, "config.yml")
+
+	err := ioutil.WriteFile(configPath, []byte(`
+- descriptor: "pip-services:container:default:default:1.0"
+  markers:
+    ready: "`+filepath.ToSlash(readyPath)+`"
+    live: "`+filepath.ToSlash(livePath)+`"
+    heartbeat_interval: 50
+`), 0644)
+	assert.Nil(t, err)
+
+	c := container.NewContainer("test", "Test container")
+	err = c.ReadConfigFromFile("123", configPath, nil)
+	assert.Nil(t, err)
+	assert.False(t, fileExists(readyPath))
+	assert.False(t, fileExists(livePath))
+
+	err = c.Open("123")
+	assert.Nil(t, err)
+	assert.True(t, fileExists(readyPath))
+	assert.True(t, fileExists(livePath))
+
+	// The heartbeat keeps touching the live file while the container runs
+	touched := modTime(t, livePath)
+	time.Sleep(200 * time.Millisecond)
+	assert.True(t, modTime(t, livePath).After(touched))
+
+	err = c.Close("123")
+	assert.Nil(t, err)
+	assert.False(t, fileExists(readyPath))
+	assert.False(t, fileExists(livePath))
+}
+
+func TestLifecycleMarkersOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	readyPath := filepath.Join(dir, "ready")
+	livePath := filepath.Join(dir, "live")
+
+	c := newFixtureContainer(t, singleComponentConfig, map[string]interface{}{
+		"mygroup:component:default:default:1.0": &failingComponent{},
+	})
+	c.SetMarkerFiles(readyPath, livePath, 50*time.Millisecond)
+
+	// A container that failed to start is neither ready nor live
+	err := c.Open("123")
+	assert.NotNil(t, err)
+	assert.False(t, fileExists(readyPath))
+	assert.False(t, fileExists(livePath))
+
+	// The heartbeat is stopped, so the live file doesn't come back
+	time.Sleep(150 * time.Millisecond)
+	assert.False(t, fileExists(livePath))
+}
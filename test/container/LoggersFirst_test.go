@@ -0,0 +1,48 @@
+package test_container
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+)
+
+type recordingLogger struct {
+	*log.NullLogger
+	lock     sync.Mutex
+	messages []string
+}
+
+func (c *recordingLogger) Log(level int, correlationId string, err error, message string, args ...interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.messages = append(c.messages, fmt.Sprintf(message, args...))
+}
+
+func (c *recordingLogger) Messages() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]string{}, c.messages...)
+}
+
+func TestLoggersCreatedFirst(t *testing.T) {
+	logger := &recordingLogger{NullLogger: log.NewNullLogger()}
+
+	// The logger is declared after the component that is expected to be logged
+	c := newFixtureContainer(t, `
+- descriptor: "mygroup:controller:default:default:1.0"
+- descriptor: "mygroup:logger:recording:default:1.0"
+`, map[string]interface{}{
+		"mygroup:controller:default:default:1.0": &struct{ name string }{name: "controller"},
+		"mygroup:logger:recording:default:1.0":   logger,
+	})
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	assert.Contains(t, logger.Messages(), "Created component mygroup:controller:default:default:1.0")
+}
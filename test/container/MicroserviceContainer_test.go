@@ -0,0 +1,21 @@
+package test_container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestMicroserviceContainer(t *testing.T) {
+	c := container.NewMicroserviceContainer("beacons", build.NewFactory(), nil)
+	assert.Equal(t, "beacons", c.Info().Name)
+
+	bundle := c.ExportSupportBundle()
+	assert.Len(t, bundle.Factories, 2)
+
+	err := c.ReadConfigFromBytes("123", []byte(container.MicroserviceConfig), ".yml", nil)
+	assert.Nil(t, err)
+}
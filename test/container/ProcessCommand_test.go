@@ -0,0 +1,131 @@
+package test_container
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-commons-go/run"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+// Announces opening in stderr, so the parent process can see which components were opened
+type announcingComponent struct {
+	name   string
+	opened bool
+}
+
+func (c *announcingComponent) IsOpen() bool {
+	return c.opened
+}
+
+func (c *announcingComponent) Open(correlationId string) error {
+	c.opened = true
+	fmt.Fprintln(os.Stderr, "opened "+c.name)
+	return nil
+}
+
+func (c *announcingComponent) Close(correlationId string) error {
+	c.opened = false
+	return nil
+}
+
+type announcingExecutable struct {
+	announcingComponent
+}
+
+func (c *announcingExecutable) Execute(correlationId string, args *run.Parameters) (interface{}, error) {
+	return map[string]interface{}{
+		"command": "report",
+		"period":  args.GetAsString("period"),
+	}, nil
+}
+
+const processCommandConfig = `
+- descriptor: "pip-services:container:default:default:1.0"
+  commands:
+    report: "mygroup:reporter:default:default:1.0"
+- descriptor: "mygroup:reporter:default:default:1.0"
+  depends_on:
+    - "mygroup:storage:default:default:1.0"
+- descriptor: "mygroup:storage:default:default:1.0"
+- descriptor: "mygroup:service:default:default:1.0"
+`
+
+// Runs the process container in a child process, because it exits when the command is done
+func TestProcessCommandHelper(t *testing.T) {
+	args := os.Getenv("PROCESS_COMMAND_ARGS")
+	if args == "" {
+		t.Skip("Runs only as a child process of TestProcessCommand")
+	}
+
+	factory := build.NewFactory()
+	for _, name := range []string{"storage", "service"} {
+		component := &announcingComponent{name: name}
+		factory.Register(crefer.NewDescriptor("mygroup", name, "default", "default", "1.0"),
+			func(locator interface{}) interface{} {
+				return component
+			})
+	}
+	factory.Register(crefer.NewDescriptor("mygroup", "reporter", "default", "default", "1.0"),
+		func(locator interface{}) interface{} {
+			return &announcingExecutable{announcingComponent{name: "reporter"}}
+		})
+
+	c := container.NewProcessContainer("test", "Test container")
+	c.AddFactory(factory)
+	c.Run(strings.Split(args, " "))
+}
+
+func runProcessCommand(t *testing.T, args string) (string, string, error) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	err := ioutil.WriteFile(path, []byte(processCommandConfig), 0644)
+	assert.Nil(t, err)
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestProcessCommandHelper$")
+	cmd.Env = append(os.Environ(), "PROCESS_COMMAND_ARGS=-c "+path+" "+args)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestProcessCommand(t *testing.T) {
+	stdout, stderr, err := runProcessCommand(t, "exec report -p period=daily")
+	assert.Nil(t, err, stderr)
+
+	// Only the command component and its dependencies are opened
+	assert.Contains(t, stderr, "opened reporter")
+	assert.Contains(t, stderr, "opened storage")
+	assert.NotContains(t, stderr, "opened service")
+
+	// The result is printed as JSON in the last line of the output
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	result := map[string]interface{}{}
+	err = json.Unmarshal([]byte(lines[len(lines)-1]), &result)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"command": "report", "period": "daily"}, result)
+}
+
+func TestUnknownProcessCommand(t *testing.T) {
+	_, stderr, err := runProcessCommand(t, "exec cleanup")
+	assert.NotNil(t, err)
+	exitErr, ok := err.(*exec.ExitError)
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, 1, exitErr.ExitCode())
+	}
+	assert.NotContains(t, stderr, "opened")
+	assert.Contains(t, stderr, "COMMAND_NOT_FOUND")
+}
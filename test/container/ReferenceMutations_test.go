@@ -0,0 +1,100 @@
+package test_container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+func nextMutation(events chan container.ContainerEvent) *container.ContainerEvent {
+	for {
+		select {
+		case event := <-events:
+			if event.Event == container.EventReferencesChanged {
+				return &event
+			}
+		case <-time.After(100 * time.Millisecond):
+			return nil
+		}
+	}
+}
+
+func TestMutateReferences(t *testing.T) {
+	plugin := &restartableComponent{}
+	factory := build.NewFactory()
+	factory.Register(crefer.NewDescriptor("mygroup", "service", "default", "default", "1.0"),
+		func(locator interface{}) interface{} {
+			return &restartableComponent{}
+		})
+	factory.Register(crefer.NewDescriptor("mygroup", "plugin", "default", "default", "1.0"),
+		func(locator interface{}) interface{} {
+			return plugin
+		})
+
+	c := container.NewContainer("test", "Test container")
+	c.AddFactory(factory)
+	noop := func(references *refer.ContainerReferences) error {
+		return nil
+	}
+
+	err := c.MutateReferences("123", container.ReferenceMutationPlugin, noop)
+	assert.NotNil(t, err)
+
+	err = c.ReadConfigFromBytes("123", []byte(`
+- descriptor: "mygroup:service:default:default:1.0"
+`), ".yml", nil)
+	assert.Nil(t, err)
+	err = c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	events := make(chan container.ContainerEvent, 10)
+	c.Subscribe(events)
+
+	// Mutations that don't add or remove components are not reported
+	err = c.MutateReferences("123", container.ReferenceMutationPlugin, noop)
+	assert.Nil(t, err)
+	err = c.RestartComponent("123", crefer.NewDescriptor("mygroup", "service", "*", "*", "*"))
+	assert.Nil(t, err)
+	assert.Nil(t, nextMutation(events))
+
+	pluginConfig, err := config.ContainerConfigReader.ReadFromBytes("123", []byte(`
+- descriptor: "mygroup:plugin:default:default:1.0"
+`), ".yml", nil)
+	assert.Nil(t, err)
+	err = c.MutateReferences("123", container.ReferenceMutationPlugin,
+		func(references *refer.ContainerReferences) error {
+			return references.AddFromConfig("123", pluginConfig)
+		})
+	assert.Nil(t, err)
+
+	event := nextMutation(events)
+	if assert.NotNil(t, event) {
+		assert.Equal(t, container.ReferenceMutationPlugin, event.Parameters.GetAsString("source"))
+		assert.Equal(t, "mygroup:plugin:default:default:1.0", event.Parameters.GetAsString("added"))
+		assert.Equal(t, "", event.Parameters.GetAsString("removed"))
+		assert.NotContains(t, event.Parameters.GetAsString("before"), "mygroup:plugin")
+		assert.Contains(t, event.Parameters.GetAsString("after"), "mygroup:plugin:default:default:1.0")
+	}
+
+	err = c.MutateReferences("123", container.ReferenceMutationPlugin,
+		func(references *refer.ContainerReferences) error {
+			references.RemoveComponent(plugin)
+			return nil
+		})
+	assert.Nil(t, err)
+
+	event = nextMutation(events)
+	if assert.NotNil(t, event) {
+		assert.Equal(t, "", event.Parameters.GetAsString("added"))
+		assert.Equal(t, "mygroup:plugin:default:default:1.0", event.Parameters.GetAsString("removed"))
+		assert.NotContains(t, event.Parameters.GetAsString("after"), "mygroup:plugin")
+	}
+}
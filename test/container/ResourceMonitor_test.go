@@ -0,0 +1,19 @@
+package test_container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestResourceMonitor(t *testing.T) {
+	monitor := container.NewResourceMonitor()
+	report := monitor.Sample()
+
+	assert.True(t, report.CpuLimit > 0)
+	assert.True(t, report.MemoryBytes > 0)
+	assert.Len(t, report.Groups, 0)
+	assert.Equal(t, report, monitor.GetReport())
+}
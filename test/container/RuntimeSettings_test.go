@@ -0,0 +1,31 @@
+package test_container
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestRuntimeSettings(t *testing.T) {
+	procs := runtime.GOMAXPROCS(0)
+	defer runtime.GOMAXPROCS(procs)
+
+	settings := container.NewRuntimeSettings(log.NewNullLogger())
+	settings.Configure(cconfig.NewConfigParamsFromTuples(
+		"runtime.gomaxprocs", 1,
+	))
+	err := settings.Apply("123")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, runtime.GOMAXPROCS(0))
+
+	settings.Configure(cconfig.NewConfigParamsFromTuples(
+		"runtime.gogc", "often",
+	))
+	err = settings.Apply("123")
+	assert.NotNil(t, err)
+}
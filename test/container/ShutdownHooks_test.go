@@ -0,0 +1,75 @@
+package test_container
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+)
+
+type hookCalls struct {
+	calls []string
+	lock  sync.Mutex
+}
+
+func (c *hookCalls) add(call string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.calls = append(c.calls, call)
+}
+
+func (c *hookCalls) get() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]string{}, c.calls...)
+}
+
+func TestShutdownHooks(t *testing.T) {
+	component := &restartableComponent{}
+	c := newFixtureContainer(t, singleComponentConfig, map[string]interface{}{
+		"mygroup:component:default:default:1.0": component,
+	})
+	c.SetShutdownHookTimeout(50 * time.Millisecond)
+
+	// Hooks run in separate goroutines, so calls are guarded
+	calls := &hookCalls{}
+	c.AddShutdownHook(func(ctx context.Context) error {
+		calls.add("first")
+		return errors.New("Failed to remove PID file")
+	})
+	c.AddShutdownHook(func(ctx context.Context) error {
+		calls.add("second")
+		panic("Discovery is not available")
+	})
+	c.AddShutdownHook(func(ctx context.Context) error {
+		// Components are closed before hooks run
+		if component.IsOpen() {
+			calls.add("third: component is open")
+		} else {
+			calls.add("third")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := c.Open("123")
+	assert.Nil(t, err)
+	assert.Empty(t, calls.get())
+
+	// Failed, panicking and hung hooks don't stop the rest, the first error is returned
+	err = c.Close("123")
+	assert.NotNil(t, err)
+	assert.Equal(t, "SHUTDOWN_HOOK_TIMEOUT", err.(*cerr.ApplicationError).Code)
+	assert.Equal(t, []string{"third", "second", "first"}, calls.get())
+
+	// Hooks run each time the container closes
+	err = c.Open("123")
+	assert.Nil(t, err)
+	c.Close("123")
+	assert.Len(t, calls.get(), 6)
+}
@@ -0,0 +1,119 @@
+package test_container
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type statefulComponent struct {
+	state      string
+	restoreErr error
+	lock       sync.Mutex
+}
+
+func (c *statefulComponent) SetState(state string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.state = state
+}
+
+func (c *statefulComponent) State() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.state
+}
+
+func (c *statefulComponent) SaveState(correlationId string) ([]byte, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return []byte(c.state), nil
+}
+
+func (c *statefulComponent) RestoreState(correlationId string, state []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.restoreErr != nil {
+		return c.restoreErr
+	}
+	c.state = string(state)
+	return nil
+}
+
+const statefulConfig = `
+- descriptor: "pip-services:container:default:default:1.0"
+  state:
+    backend: file
+    path: %q
+- descriptor: "mygroup:first:default:default:1.0"
+- descriptor: "mygroup:second:default:default:1.0"
+`
+
+func readState(t *testing.T, path string, name string) string {
+	data, err := ioutil.ReadFile(filepath.Join(path, "mygroup_"+name+"_default_default_1.0.state"))
+	assert.Nil(t, err)
+	return string(data)
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	path := t.TempDir()
+
+	first := &statefulComponent{}
+	second := &statefulComponent{}
+	c := newFixtureContainer(t, fmt.Sprintf(statefulConfig, path), map[string]interface{}{
+		"mygroup:first:default:default:1.0":  first,
+		"mygroup:second:default:default:1.0": second,
+	})
+	err := c.Open("123")
+	assert.Nil(t, err)
+	first.SetState("first state")
+	second.SetState("second state")
+	err = c.Close("123")
+	assert.Nil(t, err)
+
+	// State files are named after component locators
+	assert.Equal(t, "first state", readState(t, path, "first"))
+	assert.Equal(t, "second state", readState(t, path, "second"))
+
+	// The next run restores the saved state before components are opened
+	first = &statefulComponent{}
+	second = &statefulComponent{}
+	c = newFixtureContainer(t, fmt.Sprintf(statefulConfig, path), map[string]interface{}{
+		"mygroup:first:default:default:1.0":  first,
+		"mygroup:second:default:default:1.0": second,
+	})
+	err = c.Open("123")
+	assert.Nil(t, err)
+	assert.Equal(t, "first state", first.State())
+	assert.Equal(t, "second state", second.State())
+	err = c.Close("123")
+	assert.Nil(t, err)
+}
+
+func TestFailedStartupKeepsState(t *testing.T) {
+	path := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(path, "mygroup_first_default_default_1.0.state"), []byte("first state"), 0644)
+	assert.Nil(t, err)
+	err = ioutil.WriteFile(filepath.Join(path, "mygroup_second_default_default_1.0.state"), []byte("second state"), 0644)
+	assert.Nil(t, err)
+
+	// One component fails to restore its state, so the container doesn't start
+	first := &statefulComponent{}
+	second := &statefulComponent{restoreErr: errors.New("Corrupted state")}
+	c := newFixtureContainer(t, fmt.Sprintf(statefulConfig, path), map[string]interface{}{
+		"mygroup:first:default:default:1.0":  first,
+		"mygroup:second:default:default:1.0": second,
+	})
+	first.SetState("overwritten")
+	err = c.Open("123")
+	assert.NotNil(t, err)
+
+	// Saved state is left untouched when the container stops after the failure
+	assert.Equal(t, "first state", readState(t, path, "first"))
+	assert.Equal(t, "second state", readState(t, path, "second"))
+}
@@ -0,0 +1,21 @@
+package test_container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestUnusedConfigKeys(t *testing.T) {
+	c := container.NewEmptyContainer()
+	assert.Empty(t, c.GetUnusedConfigKeys())
+
+	key := &container.UnusedConfigKey{
+		Component:  "mygroup:persistence:mongodb:default:1.0",
+		Key:        "conection.host",
+		Suggestion: "connection.host",
+	}
+	assert.Equal(t, "mygroup:persistence:mongodb:default:1.0: conection.host (did you mean connection.host?)", key.String())
+}
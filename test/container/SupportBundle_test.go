@@ -0,0 +1,64 @@
+package test_container
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+func TestExportSupportBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yml")
+	err = ioutil.WriteFile(path, []byte(`
+- descriptor: "pip-services:logger:null:default:1.0"
+`), 0644)
+	assert.Nil(t, err)
+
+	c := container.NewContainer("test", "Test container")
+	c.AddFactory(build.NewFactory())
+	err = c.ReadConfigFromFile("123", path, cconfig.NewConfigParamsFromTuples(
+		"db.host", "localhost",
+		"db.password", "pa$$word",
+	))
+	assert.Nil(t, err)
+
+	bundle := c.ExportSupportBundle()
+	assert.Equal(t, "test", bundle.Name)
+	assert.False(t, bundle.Opened)
+	assert.Empty(t, bundle.Components)
+	assert.Equal(t, "localhost", bundle.Parameters["db.host"])
+	assert.Equal(t, "***", bundle.Parameters["db.password"])
+	assert.Contains(t, bundle.Factories, "pip-services:factory:container:default:1.0")
+	assert.Contains(t, bundle.Factories, "*build.Factory")
+
+	err = c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	bundle = c.ExportSupportBundle()
+	assert.True(t, bundle.Opened)
+	assert.Contains(t, bundle.Components, "pip-services:logger:null:default:1.0")
+}
+
+func TestSupportBundleRedactsEnvironment(t *testing.T) {
+	os.Setenv("TEST_BUNDLE_API_TOKEN", "abc")
+	os.Setenv("TEST_BUNDLE_REGION", "us-east-1")
+	defer os.Unsetenv("TEST_BUNDLE_API_TOKEN")
+	defer os.Unsetenv("TEST_BUNDLE_REGION")
+
+	c := container.NewContainer("test", "Test container")
+	bundle := c.ExportSupportBundle()
+
+	assert.Equal(t, "***", bundle.Environment["TEST_BUNDLE_API_TOKEN"])
+	assert.Equal(t, "us-east-1", bundle.Environment["TEST_BUNDLE_REGION"])
+}
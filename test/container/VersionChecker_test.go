@@ -0,0 +1,107 @@
+package test_container
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	cerr "github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+)
+
+type warningLogger struct {
+	*log.NullLogger
+	warnings []string
+}
+
+func (c *warningLogger) Warn(correlationId string, message string, args ...interface{}) {
+	c.warnings = append(c.warnings, fmt.Sprintf(message, args...))
+}
+
+type versionedFactory struct {
+	*build.Factory
+	name    string
+	version string
+}
+
+func (c *versionedFactory) GetLibraryName() string {
+	return c.name
+}
+
+func (c *versionedFactory) GetLibraryVersion() string {
+	return c.version
+}
+
+func newVersionedFactory(name string, version string) *versionedFactory {
+	return &versionedFactory{Factory: build.NewFactory(), name: name, version: version}
+}
+
+func TestCollectVersions(t *testing.T) {
+	checker := container.NewVersionChecker(log.NewNullLogger())
+	versions := checker.Collect([]interface{}{
+		newVersionedFactory("github.com/example/storage-go", "1.2.0"),
+		newVersionedFactory("github.com/example/storage-go", "v1.5.3"),
+		newVersionedFactory("github.com/example/storage-go/v2", "2.0.1"),
+		"not versioned",
+	})
+
+	// Major version is taken from the module path suffix or from the version itself
+	assert.Equal(t, map[string][]string{
+		"1": {"github.com/example/storage-go@1.2.0", "github.com/example/storage-go@v1.5.3"},
+		"2": {"github.com/example/storage-go/v2@2.0.1"},
+	}, versions["github.com/example/storage-go"])
+}
+
+func TestMixedMajorVersions(t *testing.T) {
+	logger := &warningLogger{NullLogger: log.NewNullLogger()}
+	checker := container.NewVersionChecker(logger)
+
+	// Minor versions of the same library are compatible
+	err := checker.Check("123", []interface{}{
+		newVersionedFactory("github.com/example/storage-go", "1.2.0"),
+		newVersionedFactory("github.com/example/storage-go", "1.5.3"),
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, logger.warnings)
+
+	mixed := []interface{}{
+		newVersionedFactory("github.com/example/storage-go", "1.2.0"),
+		newVersionedFactory("github.com/example/storage-go/v2", "2.0.1"),
+	}
+
+	// Mixed major versions are reported as a warning by default
+	err = checker.Check("123", mixed)
+	assert.Nil(t, err)
+	assert.Len(t, logger.warnings, 1)
+	assert.Contains(t, logger.warnings[0],
+		"github.com/example/storage-go (github.com/example/storage-go/v2@2.0.1, github.com/example/storage-go@1.2.0)")
+
+	// Strict mode fails on mixed major versions
+	checker.Configure(cconfig.NewConfigParamsFromTuples("versions.strict", true))
+	err = checker.Check("123", mixed)
+	assert.NotNil(t, err)
+	appErr := err.(*cerr.ApplicationError)
+	assert.Equal(t, "INCOMPATIBLE_VERSIONS", appErr.Code)
+	assert.Equal(t, []string{
+		"github.com/example/storage-go (github.com/example/storage-go/v2@2.0.1, github.com/example/storage-go@1.2.0)",
+	}, appErr.Details["conflicts"])
+}
+
+func TestContainerVersionCheck(t *testing.T) {
+	c := container.NewContainer("test", "Test container")
+	c.AddFactory(newVersionedFactory("github.com/example/storage-go", "1.2.0"))
+	c.AddFactory(newVersionedFactory("github.com/example/storage-go/v2", "2.0.1"))
+	c.Configure(cconfig.NewConfigParamsFromTuples(
+		"container.descriptor", "pip-services:container:default:default:1.0",
+		"container.versions.strict", true,
+	))
+
+	err := c.Open("123")
+	assert.NotNil(t, err)
+	assert.Equal(t, "INCOMPATIBLE_VERSIONS", err.(*cerr.ApplicationError).Code)
+	assert.False(t, c.IsOpen())
+}
@@ -0,0 +1,87 @@
+package test_container
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-container-go/container"
+	"github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+func TestWiringReport(t *testing.T) {
+	factory := build.NewFactory()
+	factory.Register(crefer.NewDescriptor("mygroup", "*", "*", "*", "1.0"),
+		func(locator interface{}) interface{} {
+			return &restartableComponent{}
+		})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wiring.json")
+	configPath := filepath.Join(dir, "config.yml")
+	err := ioutil.WriteFile(configPath, []byte(`
+- descriptor: "pip-services:container:default:default:1.0"
+  wiring_report:
+    path: "`+filepath.ToSlash(path)+`"
+- descriptor: "mygroup:storage:memory:primary:1.0"
+- descriptor: "mygroup:storage:memory:replica:1.0"
+- descriptor: "mygroup:controller:default:default:1.0"
+  depends_on:
+    - "mygroup:storage:memory:primary:1.0"
+  dependencies:
+    persistence: "mygroup:storage:*:*:1.0"
+    cache: "mygroup:cache:*:*:1.0"
+`), 0644)
+	assert.Nil(t, err)
+
+	c := container.NewContainer("test", "Test container")
+	c.AddFactory(factory)
+	err = c.ReadConfigFromFile("123", configPath, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, c.GetWiringReport())
+
+	err = c.Open("123")
+	assert.Nil(t, err)
+	defer c.Close("123")
+
+	// Declared dependencies are listed with all components that satisfy them,
+	// in the order references return them: the last added first
+	controller := "mygroup:controller:default:default:1.0"
+	expected := &refer.WiringReport{
+		Dependencies: []*refer.DependencyWiring{
+			{
+				Component:  controller,
+				Dependency: "depends_on",
+				Locator:    "mygroup:storage:memory:primary:1.0",
+				Resolved:   []string{"mygroup:storage:memory:primary:1.0"},
+			},
+			{
+				Component:  controller,
+				Dependency: "cache",
+				Locator:    "mygroup:cache:*:*:1.0",
+				Resolved:   []string{},
+			},
+			{
+				Component:  controller,
+				Dependency: "persistence",
+				Locator:    "mygroup:storage:*:*:1.0",
+				Resolved:   []string{"mygroup:storage:memory:replica:1.0", "mygroup:storage:memory:primary:1.0"},
+			},
+		},
+		Unresolved: 1,
+	}
+	assert.Equal(t, expected, c.GetWiringReport())
+
+	// The same report is saved to the configured file
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	saved := &refer.WiringReport{}
+	err = json.Unmarshal(data, saved)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, saved)
+}
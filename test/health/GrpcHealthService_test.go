@@ -0,0 +1,82 @@
+package test_health
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	cconfig "github.com/pip-services3-go/pip-services3-commons-go/config"
+	crefer "github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/pip-services3-go/pip-services3-container-go/health"
+)
+
+type healthSource struct {
+	serving bool
+	lock    sync.Mutex
+}
+
+func (c *healthSource) IsServing(correlationId string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.serving
+}
+
+func (c *healthSource) SetServing(serving bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.serving = serving
+}
+
+func (c *healthSource) GetComponentHealth(correlationId string) map[string]bool {
+	return map[string]bool{
+		"mygroup:persistence:memory:billing:1.0": false,
+		"mygroup:controller:default:default:1.0": true,
+	}
+}
+
+func TestGrpcHealthService(t *testing.T) {
+	service := health.NewGrpcHealthService()
+	service.Configure(cconfig.NewConfigParamsFromTuples(
+		"connection.host", "localhost",
+		"connection.port", 18090,
+		"interval", 50,
+		"services.0.name", "mygroup.billing.v1.Billing",
+		"services.0.components", "mygroup:*:*:billing:1.0",
+		"services.1.name", "mygroup.controller.v1.Controller",
+		"services.1.components", "mygroup:controller:*:*:1.0",
+	))
+	source := &healthSource{serving: true}
+	service.SetReferences(crefer.NewReferencesFromTuples(
+		crefer.NewDescriptor("pip-services", "health-source", "container", "default", "1.0"), source,
+	))
+
+	err := service.Open("123")
+	assert.Nil(t, err)
+	defer service.Close("123")
+
+	conn, err := grpc.Dial("localhost:18090", grpc.WithInsecure())
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	check := func(name string) healthpb.HealthCheckResponse_ServingStatus {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		response, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: name})
+		assert.Nil(t, err)
+		return response.GetStatus()
+	}
+
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check(""))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check("mygroup.billing.v1.Billing"))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check("mygroup.controller.v1.Controller"))
+
+	source.SetServing(false)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(""))
+}
@@ -0,0 +1,40 @@
+package test_refer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+func TestAsyncComponent(t *testing.T) {
+	handle := crefer.NewAsyncComponent(func() (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "component", nil
+	})
+
+	component, err := handle.Await(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "component", component)
+
+	handle = crefer.NewAsyncComponent(func() (interface{}, error) {
+		return nil, errors.New("failed")
+	})
+
+	_, err = handle.Await(context.Background())
+	assert.NotNil(t, err)
+
+	handle = crefer.NewAsyncComponent(func() (interface{}, error) {
+		time.Sleep(time.Second)
+		return "component", nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = handle.Await(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
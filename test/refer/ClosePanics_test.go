@@ -0,0 +1,60 @@
+package test_refer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+	crefer "github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+type panickingComponent struct {
+	closingComponent
+}
+
+func (c *panickingComponent) Close(correlationId string) error {
+	panic("close failed")
+}
+
+func (c *panickingComponent) SetReferences(references refer.IReferences) {}
+
+func (c *panickingComponent) UnsetReferences() {
+	panic("unset failed")
+}
+
+type unlinkedComponent struct {
+	closingComponent
+	unset bool
+}
+
+func (c *unlinkedComponent) SetReferences(references refer.IReferences) {}
+
+func (c *unlinkedComponent) UnsetReferences() {
+	c.unset = true
+}
+
+func TestClosePanicIsolation(t *testing.T) {
+	panicking := &panickingComponent{}
+	healthy := &unlinkedComponent{}
+
+	refs := crefer.NewEmptyManagedReferences()
+	refs.Put(refer.NewDescriptor("mygroup", "component", "panicking", "default", "1.0"), panicking)
+	refs.Put(refer.NewDescriptor("mygroup", "component", "healthy", "default", "1.0"), healthy)
+	// Panicking components on both sides of the healthy one
+	refs.Put(refer.NewDescriptor("mygroup", "component", "panicking", "default", "2.0"), &panickingComponent{})
+
+	err := refs.Open("123")
+	assert.Nil(t, err)
+
+	report := refs.Runner.CloseWithReport("123")
+	err = report.FirstError()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "close failed")
+	assert.Len(t, report.Components, 3)
+	assert.False(t, healthy.opened)
+
+	err = refs.Linker.Close("123")
+	assert.Nil(t, err)
+	assert.True(t, healthy.unset)
+}
@@ -0,0 +1,78 @@
+package test_refer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+	crefer "github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+type closingComponent struct {
+	opened bool
+	err    error
+}
+
+func (c *closingComponent) IsOpen() bool {
+	return c.opened
+}
+
+func (c *closingComponent) Open(correlationId string) error {
+	c.opened = true
+	return nil
+}
+
+func (c *closingComponent) Close(correlationId string) error {
+	c.opened = false
+	return c.err
+}
+
+func TestCloseWithReport(t *testing.T) {
+	closeErr := errors.New("close failed")
+	failing := &closingComponent{err: closeErr}
+	healthy := &closingComponent{}
+
+	failingLocator := refer.NewDescriptor("mygroup", "component", "failing", "default", "1.0")
+	healthyLocator := refer.NewDescriptor("mygroup", "component", "healthy", "default", "1.0")
+
+	refs := crefer.NewEmptyManagedReferences()
+	refs.Put(failingLocator, failing)
+	refs.Put(healthyLocator, healthy)
+
+	err := refs.Open("123")
+	assert.Nil(t, err)
+	assert.True(t, refs.IsOpen())
+
+	report, err := refs.CloseWithReport("123")
+	assert.Equal(t, closeErr, err)
+	assert.Equal(t, closeErr, report.FirstError())
+	assert.False(t, refs.Runner.IsOpen())
+
+	// Closing continues after a failed component
+	assert.False(t, failing.opened)
+	assert.False(t, healthy.opened)
+
+	assert.Len(t, report.Components, 2)
+	for _, component := range report.Components {
+		if component.Locator == failingLocator {
+			assert.Equal(t, closeErr, component.Error)
+		} else {
+			assert.Equal(t, healthyLocator, component.Locator)
+			assert.Nil(t, component.Error)
+		}
+		assert.False(t, component.TimedOut)
+		assert.True(t, component.Duration <= report.Duration)
+	}
+}
+
+func TestCloseWithReportWithoutErrors(t *testing.T) {
+	refs := crefer.NewEmptyManagedReferences()
+	refs.Put(refer.NewDescriptor("mygroup", "component", "default", "default", "1.0"), &closingComponent{})
+
+	report, err := refs.CloseWithReport("123")
+	assert.Nil(t, err)
+	assert.Nil(t, report.FirstError())
+	assert.Len(t, report.Components, 1)
+}
@@ -0,0 +1,43 @@
+package test_refer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	crefer "github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+func TestFuncComponent(t *testing.T) {
+	opened := 0
+	closed := 0
+	component := crefer.NewFuncComponent(
+		func(correlationId string) error {
+			opened++
+			return nil
+		},
+		func(correlationId string) error {
+			closed++
+			return nil
+		},
+	)
+
+	component.Configure(nil)
+	assert.Nil(t, component.Open("123"))
+	assert.Nil(t, component.Open("123"))
+	assert.True(t, component.IsOpen())
+	assert.Equal(t, 1, opened)
+
+	assert.Nil(t, component.Close("123"))
+	assert.False(t, component.IsOpen())
+	assert.Equal(t, 1, closed)
+
+	component = &crefer.FuncComponent{
+		OnOpen: func(correlationId string) error {
+			return errors.New("failed")
+		},
+	}
+	assert.NotNil(t, component.Open("123"))
+	assert.False(t, component.IsOpen())
+}
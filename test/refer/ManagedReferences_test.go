@@ -1,12 +1,15 @@
 package test_refer
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
 	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
 	crefer "github.com/pip-services3-go/pip-services3-container-go/refer"
 )
 
@@ -45,3 +48,165 @@ func TestNilLocator(t *testing.T) {
 
 	assert.Nil(t, logger)
 }
+
+func TestResolutionPriority(t *testing.T) {
+	refs := crefer.NewEmptyManagedReferences()
+
+	refs.Put(refer.NewDescriptor("group", "connection", "kind", "name1", "1.0"), "component1")
+	refs.Put(refer.NewDescriptor("group", "connection", "kind", "name2", "1.0"), "component2")
+	refs.Put(refer.NewDescriptor("group", "connection", "kind", "name3", "1.0"), "component3")
+
+	refs.SetResolutionPriority("component3", 10)
+	refs.SetResolutionPriority("component1", -1)
+
+	locator := refer.NewDescriptor("group", "connection", "*", "*", "*")
+
+	component, err := refs.GetOneRequired(locator)
+	assert.Nil(t, err)
+	assert.Equal(t, "component3", component)
+	assert.Equal(t, "component3", refs.GetOneOptional(locator))
+
+	components := refs.GetOptional(locator)
+	assert.Equal(t, []interface{}{"component3", "component2", "component1"}, components)
+}
+
+func TestSkipUnresolvableComponents(t *testing.T) {
+	refs := crefer.NewContainerReferences()
+	refs.SetLogger(log.NewNullLogger())
+	refs.SetLenient(true)
+
+	componentConfig := config.NewComponentConfigFromDescriptor(
+		refer.NewDescriptor("mygroup", "cache", "redis", "default", "1.0"), nil,
+	)
+	err := refs.PutFromConfig(config.ContainerConfig{componentConfig})
+
+	assert.Nil(t, err)
+	assert.Len(t, refs.GetSkippedComponents(), 1)
+	assert.Empty(t, refs.GetAll())
+}
+
+func TestComponentFingerprint(t *testing.T) {
+	componentConfig := config.NewComponentConfigFromDescriptor(
+		refer.NewDescriptor("mygroup", "controller", "default", "default", "1.0"), nil,
+	)
+	dependency1 := &struct{ name string }{name: "persistence1"}
+	dependency2 := &struct{ name string }{name: "persistence2"}
+
+	fingerprint := &crefer.ComponentFingerprint{Config: componentConfig, Dependencies: []interface{}{dependency1}}
+	assert.True(t, fingerprint.Equals(&crefer.ComponentFingerprint{Config: componentConfig, Dependencies: []interface{}{dependency1}}))
+	assert.False(t, fingerprint.Equals(&crefer.ComponentFingerprint{Config: componentConfig, Dependencies: []interface{}{dependency2}}))
+	assert.False(t, fingerprint.Equals(nil))
+}
+
+type resolvableController struct {
+	resolver *refer.DependencyResolver
+}
+
+func (c *resolvableController) SetDependencyResolver(resolver *refer.DependencyResolver) {
+	c.resolver = resolver
+}
+
+func TestInjectDependencyResolver(t *testing.T) {
+	descriptor := refer.NewDescriptor("mygroup", "controller", "default", "default", "1.0")
+	controller := &resolvableController{}
+	factory := build.NewFactory()
+	factory.Register(descriptor, func(locator interface{}) interface{} {
+		return controller
+	})
+
+	refs := crefer.NewContainerReferences()
+	refs.SetLogger(log.NewNullLogger())
+	refs.Put(nil, factory)
+
+	containerConfig, err := config.ContainerConfigReader.ReadFromBytes("123", []byte(`
+- descriptor: "mygroup:controller:default:default:1.0"
+  dependencies:
+    persistence: "mygroup:persistence:*:*:1.0"
+`), ".yml", nil)
+	assert.Nil(t, err)
+
+	err = refs.PutFromConfig(containerConfig)
+	assert.Nil(t, err)
+	assert.NotNil(t, controller.resolver)
+}
+
+type serviceComponent struct {
+	opens  int
+	isOpen bool
+}
+
+func (c *serviceComponent) IsOpen() bool {
+	return c.isOpen
+}
+
+func (c *serviceComponent) Open(correlationId string) error {
+	c.opens++
+	c.isOpen = true
+	return nil
+}
+
+func (c *serviceComponent) Close(correlationId string) error {
+	c.isOpen = false
+	return nil
+}
+
+type migrationComponent struct {
+	serviceComponent
+	err        error
+	migrations int
+}
+
+func (c *migrationComponent) Migrate(correlationId string) error {
+	c.migrations++
+	return c.err
+}
+
+type openedListener struct {
+	opened []interface{}
+}
+
+func (c *openedListener) OnOpening(correlationId string, locator interface{}) {}
+func (c *openedListener) OnOpened(correlationId string, locator interface{}) {
+	c.opened = append(c.opened, locator)
+}
+func (c *openedListener) OnClosing(correlationId string, locator interface{})           {}
+func (c *openedListener) OnClosed(correlationId string, locator interface{})            {}
+func (c *openedListener) OnFailed(correlationId string, locator interface{}, err error) {}
+
+func TestMigrate(t *testing.T) {
+	refs := crefer.NewEmptyManagedReferences()
+	listener := &openedListener{}
+	refs.Runner.AddListener(listener)
+
+	migration := &migrationComponent{}
+	service := &serviceComponent{}
+	refs.Put("migration", migration)
+	refs.Put("service", service)
+
+	err := refs.Open("123")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, migration.migrations)
+	assert.Equal(t, 1, migration.opens)
+	assert.True(t, refs.Runner.IsExcluded(migration))
+	assert.Equal(t, 1, service.opens)
+	assert.Equal(t, []interface{}{"migration", "service"}, listener.opened)
+
+	err = refs.Close("123")
+	assert.Nil(t, err)
+	assert.False(t, migration.IsOpen())
+}
+
+func TestFailedMigration(t *testing.T) {
+	refs := crefer.NewEmptyManagedReferences()
+
+	migration := &migrationComponent{err: errors.New("Schema is locked")}
+	service := &serviceComponent{}
+	refs.Put("migration", migration)
+	refs.Put("service", service)
+
+	err := refs.Open("123")
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, migration.migrations)
+	assert.False(t, refs.Runner.IsExcluded(migration))
+	assert.Equal(t, 0, service.opens)
+}
@@ -0,0 +1,29 @@
+package test_refer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+	crefer "github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+func TestCloneReadOnly(t *testing.T) {
+	refs := crefer.NewContainerReferences()
+
+	descriptor := refer.NewDescriptor("group", "type", "kind", "name1", "1.0")
+	refs.Put(descriptor, "component1")
+
+	snapshot := refs.CloneReadOnly()
+
+	refs.Put(refer.NewDescriptor("group", "type", "kind", "name2", "1.0"), "component2")
+	snapshot.Put(refer.NewDescriptor("group", "type", "kind", "name3", "1.0"), "component3")
+
+	components := snapshot.GetOptional(refer.NewDescriptor("group", "type", "*", "*", "*"))
+	assert.Len(t, components, 1)
+	assert.Equal(t, "component1", components[0])
+
+	assert.Nil(t, snapshot.Remove(descriptor))
+	assert.NotNil(t, snapshot.GetOneOptional(descriptor))
+}
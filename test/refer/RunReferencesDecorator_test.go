@@ -0,0 +1,270 @@
+package test_refer
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+	crefer "github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+type slowComponent struct{}
+
+func (c *slowComponent) OpenWithContext(ctx context.Context, correlationId string) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (c *slowComponent) CloseWithContext(ctx context.Context, correlationId string) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestContextTimeouts(t *testing.T) {
+	refs := crefer.NewRunReferencesDecorator(refer.NewEmptyReferences(), nil)
+
+	component := &slowComponent{}
+	locator := refer.NewDescriptor("group", "component", "slow", "default", "1.0")
+	refs.Put(locator, component)
+	refs.SetTimeouts(component, 10*time.Millisecond, 10*time.Millisecond)
+
+	err := refs.Open("123")
+	assert.NotNil(t, err)
+	assert.Equal(t, locator, refs.GetFailedLocator())
+
+	report := refs.CloseWithReport("123")
+	assert.Len(t, report.Components, 1)
+	assert.True(t, report.Components[0].TimedOut)
+}
+
+type hangingComponent struct {
+	release chan struct{}
+}
+
+func (c *hangingComponent) Close(correlationId string) error {
+	<-c.release
+	return nil
+}
+
+func TestAbandonAtDeadline(t *testing.T) {
+	refs := crefer.NewRunReferencesDecorator(refer.NewEmptyReferences(), nil)
+
+	component := &hangingComponent{release: make(chan struct{})}
+	defer close(component.release)
+	refs.Put(refer.NewDescriptor("group", "component", "hanging", "default", "1.0"), component)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	report := refs.CloseWithReportContext(ctx, "123")
+	assert.Len(t, report.Components, 1)
+	assert.True(t, report.Components[0].TimedOut)
+	assert.NotNil(t, report.FirstError())
+}
+
+type orderedComponent struct {
+	name   string
+	opened *[]string
+	lock   *sync.Mutex
+	isOpen bool
+}
+
+func (c *orderedComponent) IsOpen() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.isOpen
+}
+
+func (c *orderedComponent) Open(correlationId string) error {
+	time.Sleep(10 * time.Millisecond)
+	c.lock.Lock()
+	*c.opened = append(*c.opened, c.name)
+	c.isOpen = true
+	c.lock.Unlock()
+	return nil
+}
+
+func (c *orderedComponent) Close(correlationId string) error {
+	c.lock.Lock()
+	c.isOpen = false
+	c.lock.Unlock()
+	return nil
+}
+
+func TestParallelOpen(t *testing.T) {
+	refs := crefer.NewRunReferencesDecorator(refer.NewEmptyReferences(), nil)
+
+	opened := []string{}
+	lock := &sync.Mutex{}
+	connection := &orderedComponent{name: "connection", opened: &opened, lock: lock}
+	persistence1 := &orderedComponent{name: "persistence1", opened: &opened, lock: lock}
+	persistence2 := &orderedComponent{name: "persistence2", opened: &opened, lock: lock}
+
+	refs.Put(refer.NewDescriptor("group", "connection", "default", "default", "1.0"), connection)
+	refs.Put(refer.NewDescriptor("group", "persistence", "default", "p1", "1.0"), persistence1)
+	refs.Put(refer.NewDescriptor("group", "persistence", "default", "p2", "1.0"), persistence2)
+
+	refs.SetOpenConcurrency(2, func(component interface{}) []interface{} {
+		if component == connection {
+			return []interface{}{}
+		}
+		return []interface{}{connection}
+	})
+
+	err := refs.Open("123")
+	assert.Nil(t, err)
+	assert.Len(t, opened, 3)
+	assert.Equal(t, "connection", opened[0])
+	assert.True(t, persistence1.IsOpen())
+	assert.True(t, persistence2.IsOpen())
+}
+
+func TestContinueOnError(t *testing.T) {
+	refs := crefer.NewRunReferencesDecorator(refer.NewEmptyReferences(), nil)
+
+	optional := &slowComponent{}
+	optionalLocator := refer.NewDescriptor("group", "component", "optional", "default", "1.0")
+	refs.Put(optionalLocator, optional)
+	refs.SetTimeouts(optional, 10*time.Millisecond, 10*time.Millisecond)
+
+	opened := []string{}
+	var lock sync.Mutex
+	refs.Put(refer.NewDescriptor("group", "component", "ordered", "default", "1.0"),
+		&orderedComponent{name: "ordered", opened: &opened, lock: &lock})
+
+	refs.SetContinueOnError(true, nil)
+	err := refs.Open("123")
+	assert.Nil(t, err)
+	assert.True(t, refs.IsOpen())
+	assert.Equal(t, []string{"ordered"}, opened)
+
+	failures := refs.GetOpenFailures()
+	assert.Len(t, failures, 1)
+	assert.Equal(t, optionalLocator, failures[0].Locator)
+	refs.Close("123")
+
+	// Critical components keep fail-fast behavior
+	refs.SetContinueOnError(true, func(component interface{}) bool { return component == optional })
+	err = refs.Open("123")
+	assert.NotNil(t, err)
+}
+
+type bootingComponent struct {
+	attempts int
+	isOpen   bool
+}
+
+func (c *bootingComponent) IsOpen() bool {
+	return c.isOpen
+}
+
+func (c *bootingComponent) Open(correlationId string) error {
+	c.attempts++
+	if c.attempts < 3 {
+		return errors.NewConnectionError(correlationId, "NO_CONNECTION", "Database is booting")
+	}
+	c.isOpen = true
+	return nil
+}
+
+func (c *bootingComponent) Close(correlationId string) error {
+	c.isOpen = false
+	return nil
+}
+
+func TestRetryPolicy(t *testing.T) {
+	refs := crefer.NewRunReferencesDecorator(refer.NewEmptyReferences(), nil)
+
+	component := &bootingComponent{}
+	refs.Put(refer.NewDescriptor("group", "component", "booting", "default", "1.0"), component)
+	refs.SetRetryPolicy(component, &crefer.RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+
+	err := refs.Open("123")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, component.attempts)
+}
+
+func TestOpenBudget(t *testing.T) {
+	refs := crefer.NewRunReferencesDecorator(refer.NewEmptyReferences(), nil)
+
+	opened := []string{}
+	var lock sync.Mutex
+	refs.Put(refer.NewDescriptor("group", "component", "ordered", "c1", "1.0"),
+		&orderedComponent{name: "c1", opened: &opened, lock: &lock})
+	refs.Put(refer.NewDescriptor("group", "component", "ordered", "c2", "1.0"),
+		&orderedComponent{name: "c2", opened: &opened, lock: &lock})
+	slow := &slowComponent{}
+	slowLocator := refer.NewDescriptor("group", "component", "slow", "default", "1.0")
+	refs.Put(slowLocator, slow)
+
+	refs.SetOpenBudget(200*time.Millisecond, 2)
+	err := refs.Open("123")
+	assert.NotNil(t, err)
+	assert.Equal(t, "OPEN_BUDGET_EXCEEDED", err.(*errors.ApplicationError).Code)
+	assert.Equal(t, slowLocator, refs.GetFailedLocator())
+
+	progress := refs.GetOpenProgress()
+	assert.Equal(t, 3, progress.Total)
+	assert.Equal(t, 2, progress.Opened)
+	assert.Equal(t, 2, progress.Batch)
+	assert.Equal(t, 2, progress.Batches)
+	assert.True(t, progress.Elapsed < 300*time.Millisecond)
+}
+
+type envComponent struct {
+	endpoint string
+}
+
+func (c *envComponent) OpenWithContext(ctx context.Context, correlationId string) error {
+	c.endpoint = os.Getenv("SDK_ENDPOINT")
+	return nil
+}
+
+func TestComponentEnvironment(t *testing.T) {
+	refs := crefer.NewRunReferencesDecorator(refer.NewEmptyReferences(), nil)
+
+	component := &envComponent{}
+	refs.Put(refer.NewDescriptor("group", "component", "sdk", "default", "1.0"), component)
+	refs.SetEnvironment(component, map[string]string{"SDK_ENDPOINT": "http://localhost:8080"})
+
+	err := refs.Open("123")
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost:8080", component.endpoint)
+
+	_, ok := os.LookupEnv("SDK_ENDPOINT")
+	assert.False(t, ok)
+}
+
+// A component stored by value that cannot be compared with ==
+type listedComponent struct {
+	names []string
+}
+
+func TestOpenNonComparableComponent(t *testing.T) {
+	refs := crefer.NewRunReferencesDecorator(refer.NewEmptyReferences(), nil)
+
+	refs.Put(refer.NewDescriptor("group", "component", "listed", "l1", "1.0"), listedComponent{names: []string{"a"}})
+	refs.Put(refer.NewDescriptor("group", "component", "listed", "l2", "1.0"), listedComponent{names: []string{"b"}})
+	component := &bootingComponent{attempts: 2}
+	locator := refer.NewDescriptor("group", "component", "booting", "default", "1.0")
+	refs.Put(locator, component)
+
+	assert.NotPanics(t, func() {
+		err := refs.OpenComponent(context.Background(), "123", listedComponent{names: []string{"a"}})
+		assert.Nil(t, err)
+	})
+
+	err := refs.OpenComponent(context.Background(), "123", component)
+	assert.Nil(t, err)
+	assert.True(t, component.IsOpen())
+
+	err = refs.CloseComponent(context.Background(), "123", component)
+	assert.Nil(t, err)
+	assert.False(t, component.IsOpen())
+}
@@ -0,0 +1,167 @@
+package test_refer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+	"github.com/pip-services3-go/pip-services3-components-go/build"
+	"github.com/pip-services3-go/pip-services3-components-go/log"
+	"github.com/pip-services3-go/pip-services3-container-go/config"
+	crefer "github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+func TestSharedComponentReferenceCounting(t *testing.T) {
+	registry := crefer.NewSharedComponentRegistry()
+
+	creates := 0
+	create := func() (interface{}, error) {
+		creates++
+		return &struct{ name string }{name: "engine"}, nil
+	}
+
+	component1, created1, err := registry.Acquire("engine", create)
+	assert.Nil(t, err)
+	assert.True(t, created1)
+
+	component2, created2, err := registry.Acquire("engine", create)
+	assert.Nil(t, err)
+	assert.False(t, created2)
+	assert.Same(t, component1, component2)
+	assert.Equal(t, 1, creates)
+	assert.Equal(t, 2, registry.GetReferenceCount("engine"))
+
+	opens := 0
+	open := func() error {
+		opens++
+		return nil
+	}
+	assert.Nil(t, registry.Open("engine", open))
+	assert.Nil(t, registry.Open("engine", open))
+	assert.Equal(t, 1, opens)
+
+	assert.Equal(t, 1, registry.Release("engine"))
+	assert.Equal(t, 0, registry.Release("engine"))
+	assert.Empty(t, registry.GetKeys())
+}
+
+type sharedEngine struct {
+	references refer.IReferences
+	links      int
+	unlinks    int
+	opens      int
+	closes     int
+	isOpen     bool
+}
+
+func (c *sharedEngine) SetReferences(references refer.IReferences) {
+	c.references = references
+	c.links++
+}
+
+func (c *sharedEngine) UnsetReferences() {
+	c.references = nil
+	c.unlinks++
+}
+
+func (c *sharedEngine) IsOpen() bool {
+	return c.isOpen
+}
+
+func (c *sharedEngine) Open(correlationId string) error {
+	c.opens++
+	c.isOpen = true
+	return nil
+}
+
+func (c *sharedEngine) Close(correlationId string) error {
+	c.closes++
+	c.isOpen = false
+	return nil
+}
+
+func newSharingReferences(t *testing.T, engine *sharedEngine, name string) *crefer.ContainerReferences {
+	factory := build.NewFactory()
+	factory.Register(refer.NewDescriptor("test", "engine", "shared", "default", "1.0"),
+		func(locator interface{}) interface{} { return engine })
+
+	refs := crefer.NewContainerReferences()
+	refs.SetLogger(log.NewNullLogger())
+	refs.Put(nil, factory)
+	refs.Put("name", name)
+
+	containerConfig, err := config.ContainerConfigReader.ReadFromBytes("123", []byte(`
+- descriptor: "test:engine:shared:default:1.0"
+  shared: true
+`), ".yml", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, refs.PutFromConfig(containerConfig))
+	return refs
+}
+
+func TestSharedComponentLinking(t *testing.T) {
+	engine := &sharedEngine{}
+	refs1 := newSharingReferences(t, engine, "container1")
+	refs2 := newSharingReferences(t, engine, "container2")
+	assert.Equal(t, 2, crefer.SharedComponents.GetReferenceCount("test:engine:shared:default:1.0"))
+
+	assert.Nil(t, refs1.Open("123"))
+	assert.Nil(t, refs2.Open("123"))
+	assert.Equal(t, 1, engine.links)
+	assert.Equal(t, 1, engine.opens)
+	assert.Equal(t, "container1", engine.references.GetOneOptional("name"))
+
+	// The engine is handed over to the container that still holds it
+	assert.Nil(t, refs1.Close("123"))
+	assert.Equal(t, 0, engine.closes)
+	assert.Equal(t, 0, engine.unlinks)
+	assert.Equal(t, 2, engine.links)
+	assert.Equal(t, "container2", engine.references.GetOneOptional("name"))
+
+	assert.Nil(t, refs2.Close("123"))
+	assert.Equal(t, 1, engine.closes)
+	assert.Equal(t, 1, engine.unlinks)
+	assert.Nil(t, engine.references)
+	assert.Empty(t, crefer.SharedComponents.GetKeys())
+}
+
+func TestRemoveAllReleasesSharedComponents(t *testing.T) {
+	engine := &sharedEngine{}
+	refs1 := newSharingReferences(t, engine, "container1")
+	refs2 := newSharingReferences(t, engine, "container2")
+	assert.Nil(t, refs1.Open("123"))
+	assert.Nil(t, refs2.Open("123"))
+
+	locator := refer.NewDescriptor("test", "engine", "shared", "default", "1.0")
+	refs1.RemoveAll(locator)
+	assert.Equal(t, 0, engine.closes)
+	assert.Equal(t, 1, crefer.SharedComponents.GetReferenceCount("test:engine:shared:default:1.0"))
+	assert.Equal(t, "container2", engine.references.GetOneOptional("name"))
+
+	refs2.RemoveAll(locator)
+	assert.Equal(t, 1, engine.closes)
+	assert.Empty(t, crefer.SharedComponents.GetKeys())
+
+	refs1.Close("123")
+	refs2.Close("123")
+}
+
+func TestAbandonedSharedComponentIsReleased(t *testing.T) {
+	engine := &sharedEngine{}
+	refs1 := newSharingReferences(t, engine, "container1")
+	refs2 := newSharingReferences(t, engine, "container2")
+	assert.Nil(t, refs1.Open("123"))
+	assert.Nil(t, refs2.Open("123"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := refs1.Runner.CloseWithReportContext(ctx, "123")
+	assert.NotNil(t, report.FirstError())
+	assert.Equal(t, 1, crefer.SharedComponents.GetReferenceCount("test:engine:shared:default:1.0"))
+
+	assert.Nil(t, refs2.Close("123"))
+	assert.Equal(t, 1, engine.closes)
+	assert.Empty(t, crefer.SharedComponents.GetKeys())
+}
@@ -0,0 +1,65 @@
+package test_refer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pip-services3-go/pip-services3-commons-go/errors"
+	"github.com/pip-services3-go/pip-services3-commons-go/refer"
+	crefer "github.com/pip-services3-go/pip-services3-container-go/refer"
+)
+
+type validatableComponent struct {
+	closingComponent
+	missing []interface{}
+}
+
+func (c *validatableComponent) GetMissingDependencies() []interface{} {
+	return c.missing
+}
+
+func TestValidateReferences(t *testing.T) {
+	controller := &validatableComponent{
+		missing: []interface{}{refer.NewDescriptor("mygroup", "persistence", "*", "*", "1.0")},
+	}
+	service := &validatableComponent{
+		missing: []interface{}{refer.NewDescriptor("mygroup", "controller", "*", "*", "1.0")},
+	}
+
+	refs := crefer.NewEmptyManagedReferences()
+	refs.Put(refer.NewDescriptor("mygroup", "controller", "default", "default", "1.0"), controller)
+	refs.Put(refer.NewDescriptor("mygroup", "service", "default", "default", "1.0"), service)
+
+	err := refs.Open("123")
+	assert.NotNil(t, err)
+	appErr, ok := err.(*errors.ApplicationError)
+	assert.True(t, ok)
+	if ok {
+		// The error is the same ReferenceError that is returned for missing required references
+		assert.Equal(t, errors.Internal, appErr.Category)
+		assert.Equal(t, "REF_ERROR", appErr.Code)
+		assert.Len(t, appErr.Details["missing"], 2)
+	}
+	assert.Contains(t, err.Error(), "mygroup:persistence:*:*:1.0")
+	assert.Contains(t, err.Error(), "mygroup:controller:*:*:1.0")
+
+	// Components are not opened when dependencies are missing
+	assert.False(t, controller.opened)
+	assert.False(t, service.opened)
+	assert.False(t, refs.IsOpen())
+}
+
+func TestValidateResolvedReferences(t *testing.T) {
+	component := &validatableComponent{}
+
+	refs := crefer.NewEmptyManagedReferences()
+	refs.Put(refer.NewDescriptor("mygroup", "controller", "default", "default", "1.0"), component)
+
+	err := refs.ValidateReferences("123")
+	assert.Nil(t, err)
+
+	err = refs.Open("123")
+	assert.Nil(t, err)
+	assert.True(t, component.opened)
+}